@@ -0,0 +1,31 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"encoding/json"
+)
+
+// Remarshal converts a model into another by way of its JSON representation, such as a type-specific model into the
+// generic one or a map into a model.
+func Remarshal(from interface{}, to interface{}) error {
+	buffer, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(buffer, to)
+}
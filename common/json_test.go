@@ -0,0 +1,37 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemarshal(t *testing.T) {
+	var result struct {
+		Name  string   `json:"name"`
+		Sizes []string `json:"sizes"`
+	}
+	err := Remarshal(map[string]interface{}{"name": "aes", "sizes": []string{"128", "256"}}, &result)
+	assert.Nil(t, err)
+	assert.Equal(t, "aes", result.Name)
+	assert.Equal(t, []string{"128", "256"}, result.Sizes)
+
+	assert.NotNil(t, Remarshal(func() {}, &result))
+	assert.NotNil(t, Remarshal("aes", &result))
+}
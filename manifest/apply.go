@@ -0,0 +1,265 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-hpcs-uko-sdk/ukov4"
+)
+
+// ApplyError : The error returned when an action of a plan fails. The actions before it have been applied.
+type ApplyError struct {
+	// The action that failed.
+	Action *Action
+
+	// The number of actions applied before the failure.
+	Applied int
+
+	// Whether the action failed because the resource was changed after the plan was made, in which case a new plan
+	// must be made.
+	Stale bool
+
+	// The error returned by the service.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *ApplyError) Error() string {
+	if e.Stale {
+		return fmt.Sprintf("error applying %s: the %s was changed after the plan was made; create a new plan: %s",
+			e.Action.String(), strings.ReplaceAll(e.Action.ResourceType, "_", " "), e.Err.Error())
+	}
+	return fmt.Sprintf("error applying %s: %s", e.Action.String(), e.Err.Error())
+}
+
+// Unwrap returns the error returned by the service.
+func (e *ApplyError) Unwrap() error {
+	return e.Err
+}
+
+// applyState holds the service and the IDs of the vaults a plan applies to, including the vaults it creates, and the
+// ETags returned by the actions applied so far.
+type applyState struct {
+	service  *ukov4.UkoV4
+	vaultIDs map[string]string
+	etags    map[string]string
+}
+
+func (state *applyState) vaultReference(name string) (*ukov4.VaultReferenceInCreationRequest, error) {
+	id, ok := state.vaultIDs[name]
+	if !ok {
+		return nil, fmt.Errorf("vault '%s' not found", name)
+	}
+	return &ukov4.VaultReferenceInCreationRequest{ID: core.StringPtr(id)}, nil
+}
+
+// Apply performs the actions of the plan in order. Existing resources are changed with the ETag that NewPlan read
+// along with their live state, so a resource changed by someone else after the plan was made is not overwritten: the
+// action fails with an *ApplyError whose Stale field is set, and a new plan must be made. An action on a resource that
+// an earlier action of the plan changed uses the ETag returned by that action. Applying stops at the first failed
+// action, which is returned in an *ApplyError. Only a plan returned by NewPlan in the same process can be applied.
+func (plan *Plan) Apply(service *ukov4.UkoV4) error {
+	return plan.ApplyWithContext(context.Background(), service)
+}
+
+// ApplyWithContext is an alternate form of the Apply method which supports a Context parameter
+func (plan *Plan) ApplyWithContext(ctx context.Context, service *ukov4.UkoV4) (err error) {
+	err = core.ValidateNotNil(service, "service cannot be nil")
+	if err != nil {
+		return
+	}
+	state := &applyState{service: service, vaultIDs: map[string]string{}, etags: map[string]string{}}
+	for i := range plan.Actions {
+		action := &plan.Actions[i]
+		if action.ResourceType == Action_ResourceType_Vault && action.ID != "" {
+			state.vaultIDs[action.Vault] = action.ID
+		}
+	}
+	if err = state.resolveVaults(ctx, plan); err != nil {
+		return
+	}
+	for i := range plan.Actions {
+		action := &plan.Actions[i]
+		if action.apply == nil {
+			return &ApplyError{Action: action, Applied: i, Err: fmt.Errorf("action was not created by NewPlan in this process; create a new plan")}
+		}
+		resource := action.ResourceType + "/" + action.ID
+		etag, ok := state.etags[resource]
+		if !ok {
+			etag = action.ETag
+		}
+		var response *core.DetailedResponse
+		response, err = action.apply(ctx, state, etag)
+		if err != nil {
+			stale := response != nil && response.StatusCode == http.StatusPreconditionFailed
+			return &ApplyError{Action: action, Applied: i, Stale: stale, Err: err}
+		}
+		if etag = response.GetHeaders().Get("ETag"); action.ID != "" && etag != "" {
+			state.etags[resource] = etag
+		}
+	}
+	return nil
+}
+
+// applyFunc performs an action with the ETag of the resource it changes, and returns the response of the service.
+type applyFunc func(ctx context.Context, state *applyState, etag string) (*core.DetailedResponse, error)
+
+// resolveVaults looks up the IDs of the existing vaults that the actions of the plan refer to.
+func (state *applyState) resolveVaults(ctx context.Context, plan *Plan) error {
+	missing := false
+	for i := range plan.Actions {
+		action := &plan.Actions[i]
+		if _, ok := state.vaultIDs[action.Vault]; !ok && action.ResourceType != Action_ResourceType_Vault {
+			missing = true
+		}
+	}
+	if !missing {
+		return nil
+	}
+	pager, err := state.service.NewVaultsPager(state.service.NewListVaultsOptions())
+	if err != nil {
+		return err
+	}
+	vaults, err := pager.GetAllWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	for _, vault := range vaults {
+		if vault.Name != nil && vault.ID != nil {
+			if _, ok := state.vaultIDs[*vault.Name]; !ok {
+				state.vaultIDs[*vault.Name] = *vault.ID
+			}
+		}
+	}
+	return nil
+}
+
+func createVault(vault *Vault) applyFunc {
+	return func(ctx context.Context, state *applyState, _ string) (*core.DetailedResponse, error) {
+		options := state.service.NewCreateVaultOptions(vault.Name)
+		options.Description = vault.Description
+		options.RecoveryKeyLabel = vault.RecoveryKeyLabel
+		result, response, err := state.service.CreateVaultWithContext(ctx, options)
+		if err != nil {
+			return response, err
+		}
+		state.vaultIDs[vault.Name] = *result.ID
+		return response, nil
+	}
+}
+
+func updateVault(id string, vault *Vault) applyFunc {
+	return func(ctx context.Context, state *applyState, etag string) (*core.DetailedResponse, error) {
+		options := state.service.NewUpdateVaultOptions(id, etag)
+		options.Description = vault.Description
+		options.RecoveryKeyLabel = vault.RecoveryKeyLabel
+		_, response, err := state.service.UpdateVaultWithContext(ctx, options)
+		return response, err
+	}
+}
+
+func createKeystore(vaultName string, keystore *ukov4.KeystoreCreationRequest) applyFunc {
+	return func(ctx context.Context, state *applyState, _ string) (*core.DetailedResponse, error) {
+		body := *keystore
+		vault, err := state.vaultReference(vaultName)
+		if err != nil {
+			return nil, err
+		}
+		body.Vault = vault
+		_, response, err := state.service.CreateKeystoreWithContext(ctx, state.service.NewCreateKeystoreOptions(&body))
+		return response, err
+	}
+}
+
+func updateKeystore(id string, body *ukov4.KeystoreUpdateRequest) applyFunc {
+	return func(ctx context.Context, state *applyState, etag string) (*core.DetailedResponse, error) {
+		_, response, err := state.service.UpdateKeystoreWithContext(ctx, state.service.NewUpdateKeystoreOptions(id, etag, body))
+		return response, err
+	}
+}
+
+func createKeyTemplate(vaultName string, template *Template) applyFunc {
+	return func(ctx context.Context, state *applyState, _ string) (*core.DetailedResponse, error) {
+		vault, err := state.vaultReference(vaultName)
+		if err != nil {
+			return nil, err
+		}
+		keystores := make([]ukov4.KeystoresPropertiesCreateIntf, len(template.Keystores))
+		for i := range template.Keystores {
+			keystores[i] = &template.Keystores[i]
+		}
+		key := template.Key
+		options := state.service.NewCreateKeyTemplateOptions(vault, template.Name, &key, keystores)
+		options.Description = template.Description
+		options.NamingScheme = template.NamingScheme
+		_, response, err := state.service.CreateKeyTemplateWithContext(ctx, options)
+		return response, err
+	}
+}
+
+func updateKeyTemplate(id string, description *string, key *ukov4.KeyPropertiesUpdate, keystores []ukov4.KeystoresPropertiesUpdateIntf) applyFunc {
+	return func(ctx context.Context, state *applyState, etag string) (*core.DetailedResponse, error) {
+		options := state.service.NewUpdateKeyTemplateOptions(id, etag)
+		options.Description = description
+		options.Key = key
+		options.Keystores = keystores
+		_, response, err := state.service.UpdateKeyTemplateWithContext(ctx, options)
+		return response, err
+	}
+}
+
+func archiveKeyTemplate(id string) applyFunc {
+	return func(ctx context.Context, state *applyState, etag string) (*core.DetailedResponse, error) {
+		_, response, err := state.service.ArchiveKeyTemplateWithContext(ctx, state.service.NewArchiveKeyTemplateOptions(id, etag))
+		return response, err
+	}
+}
+
+func unarchiveKeyTemplate(id string) applyFunc {
+	return func(ctx context.Context, state *applyState, etag string) (*core.DetailedResponse, error) {
+		_, response, err := state.service.UnarchiveKeyTemplateWithContext(ctx, state.service.NewUnarchiveKeyTemplateOptions(id, etag))
+		return response, err
+	}
+}
+
+func createManagedKey(vaultName string, key *Key) applyFunc {
+	return func(ctx context.Context, state *applyState, _ string) (*core.DetailedResponse, error) {
+		vault, err := state.vaultReference(vaultName)
+		if err != nil {
+			return nil, err
+		}
+		options := state.service.NewCreateManagedKeyOptions(key.Template, vault)
+		options.Label = core.StringPtr(key.Label)
+		options.Description = key.Description
+		_, response, err := state.service.CreateManagedKeyWithContext(ctx, options)
+		return response, err
+	}
+}
+
+func updateManagedKey(id string, key *Key) applyFunc {
+	return func(ctx context.Context, state *applyState, etag string) (*core.DetailedResponse, error) {
+		options := state.service.NewUpdateManagedKeyOptions(id, etag)
+		options.Description = key.Description
+		_, response, err := state.service.UpdateManagedKeyWithContext(ctx, options)
+		return response, err
+	}
+}
@@ -0,0 +1,142 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package manifest_test
+
+import (
+	"encoding/json"
+
+	"github.com/IBM/ibm-hpcs-uko-sdk/manifest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`Plan.Apply`, func() {
+	var fake *fakeUKO
+	var m *manifest.Manifest
+
+	BeforeEach(func() {
+		fake = newFakeUKO()
+		var err error
+		m, err = manifest.Parse([]byte(testManifestYAML))
+		Expect(err).To(BeNil())
+	})
+	AfterEach(func() {
+		fake.server.Close()
+	})
+
+	It(`Invoke Apply with invalid arguments (negative test)`, func() {
+		plan := &manifest.Plan{}
+		Expect(plan.Apply(nil)).ToNot(Succeed())
+	})
+	It(`Create a new vault and its contents`, func() {
+		plan, err := manifest.NewPlan(fake.service(), m)
+		Expect(err).To(BeNil())
+		Expect(plan.Apply(fake.service())).To(Succeed())
+		Expect(fake.requests).To(Equal([]string{
+			"POST /api/v4/vaults",
+			"POST /api/v4/keystores",
+			"POST /api/v4/templates",
+			"POST /api/v4/managed_keys",
+		}))
+
+		keystore := fake.resources["keystores"][0]
+		Expect(keystore["vault"]).To(Equal(map[string]interface{}{"id": "new1"}))
+		Expect(keystore["aws_secret_access_key"]).To(Equal("secret"))
+		Expect(fake.resources["templates"][0]["vault"]).To(Equal(map[string]interface{}{"id": "new1"}))
+		Expect(fake.resources["managed_keys"][0]["template_name"]).To(Equal("aes-eu"))
+		Expect(fake.resources["managed_keys"][0]["label"]).To(Equal("PAY-prod"))
+
+		plan, err = manifest.NewPlan(fake.service(), m)
+		Expect(err).To(BeNil())
+		Expect(plan.IsEmpty()).To(BeTrue())
+	})
+	It(`Update existing resources with their ETags`, func() {
+		addLiveState(fake)
+		plan, err := manifest.NewPlan(fake.service(), m)
+		Expect(err).To(BeNil())
+		Expect(plan.Apply(fake.service())).To(Succeed())
+		Expect(fake.requests).To(Equal([]string{
+			"PATCH /api/v4/vaults/v1",
+			"PATCH /api/v4/keystores/ks1",
+			"PATCH /api/v4/templates/t1",
+			"POST /api/v4/templates/t2/archive",
+		}))
+		Expect(fake.find("keystores", "ks1")["aws_region"]).To(Equal("eu-central-1"))
+		Expect(fake.find("templates", "t2")["state"]).To(Equal("archived"))
+
+		plan, err = manifest.NewPlan(fake.service(), m)
+		Expect(err).To(BeNil())
+		Expect(plan.IsEmpty()).To(BeTrue())
+	})
+	It(`Ask for a new plan when a resource changed after planning`, func() {
+		addLiveState(fake)
+		plan, err := manifest.NewPlan(fake.service(), m)
+		Expect(err).To(BeNil())
+		Expect(plan.Actions[1].ETag).To(Equal("etag-ks1-0"))
+
+		fake.update("keystores", "ks1", "aws_region", "us-east-1")
+		err = plan.Apply(fake.service())
+		applyErr, ok := err.(*manifest.ApplyError)
+		Expect(ok).To(BeTrue())
+		Expect(applyErr.Stale).To(BeTrue())
+		Expect(applyErr.Applied).To(Equal(1))
+		Expect(applyErr.Action.String()).To(Equal("update keystore 'payments/aws-eu'"))
+		Expect(err.Error()).To(ContainSubstring("the keystore was changed after the plan was made; create a new plan"))
+		Expect(fake.find("keystores", "ks1")["aws_region"]).To(Equal("us-east-1"))
+		Expect(fake.requests).To(Equal([]string{"PATCH /api/v4/vaults/v1", "PATCH /api/v4/keystores/ks1"}))
+
+		plan, err = manifest.NewPlan(fake.service(), m)
+		Expect(err).To(BeNil())
+		Expect(plan.Apply(fake.service())).To(Succeed())
+		Expect(fake.find("keystores", "ks1")["aws_region"]).To(Equal("eu-central-1"))
+	})
+	It(`Unarchive and update a template with the ETag returned by the first action`, func() {
+		addLiveState(fake)
+		fake.find("templates", "t1")["state"] = "archived"
+		plan, err := manifest.NewPlan(fake.service(), m)
+		Expect(err).To(BeNil())
+		Expect(plan.Apply(fake.service())).To(Succeed())
+		Expect(fake.requests).To(ContainElements("POST /api/v4/templates/t1/unarchive", "PATCH /api/v4/templates/t1"))
+		Expect(fake.find("templates", "t1")["state"]).To(Equal("unarchived"))
+	})
+	It(`Refuse to apply a plan unmarshalled from JSON (negative test)`, func() {
+		addLiveState(fake)
+		plan, err := manifest.NewPlan(fake.service(), m)
+		Expect(err).To(BeNil())
+		buffer, err := json.Marshal(plan)
+		Expect(err).To(BeNil())
+		loaded := new(manifest.Plan)
+		Expect(json.Unmarshal(buffer, loaded)).To(Succeed())
+		Expect(loaded.Actions[0].ETag).To(Equal(plan.Actions[0].ETag))
+
+		err = loaded.Apply(fake.service())
+		Expect(err).To(MatchError(ContainSubstring("not created by NewPlan in this process")))
+		Expect(fake.requests).To(BeEmpty())
+	})
+	It(`Stop at the first failed action`, func() {
+		addLiveState(fake)
+		plan, err := manifest.NewPlan(fake.service(), m)
+		Expect(err).To(BeNil())
+		fake.server.Close()
+		err = plan.Apply(fake.service())
+		Expect(err).ToNot(BeNil())
+		applyErr, ok := err.(*manifest.ApplyError)
+		Expect(ok).To(BeTrue())
+		Expect(applyErr.Applied).To(Equal(0))
+		Expect(applyErr.Action.String()).To(Equal("update vault 'payments'"))
+	})
+})
@@ -0,0 +1,196 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package manifest reconciles UKO vaults with a declarative manifest. A manifest describes vaults and the keystores,
+// key templates and managed keys they contain; NewPlan compares it with the live state of a UKO instance and Apply
+// performs the resulting creates, updates and archives with the ukov4 service.
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/IBM/ibm-hpcs-uko-sdk/ukov4"
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest : The desired configuration of a set of vaults.
+type Manifest struct {
+	// The vaults, identified by name.
+	Vaults []Vault `json:"vaults"`
+}
+
+// Vault : The desired configuration of a vault.
+type Vault struct {
+	// The name of the vault, which identifies it.
+	Name string `json:"name"`
+
+	// Description of the vault.
+	Description *string `json:"description,omitempty"`
+
+	// The label of the primary recovery key of the vault.
+	RecoveryKeyLabel *string `json:"recovery_key_label,omitempty"`
+
+	// The keystores of the vault, identified by name. The vault property of each keystore is ignored. Credentials, such
+	// as aws_secret_access_key, are only used to create keystores: the service does not return them, so they are never
+	// compared with the live keystores.
+	Keystores []ukov4.KeystoreCreationRequest `json:"keystores,omitempty"`
+
+	// The key templates of the vault, identified by name. User-defined templates of the vault that are not listed are
+	// archived.
+	Templates []Template `json:"templates,omitempty"`
+
+	// The managed keys of the vault, identified by label. Managed keys that are not listed are left untouched.
+	Keys []Key `json:"keys,omitempty"`
+}
+
+// Template : The desired configuration of a key template.
+type Template struct {
+	// The name of the template, which identifies it.
+	Name string `json:"name"`
+
+	// Description of the template.
+	Description *string `json:"description,omitempty"`
+
+	// Managed key naming scheme which will be applied to every key created with this template.
+	NamingScheme *string `json:"naming_scheme,omitempty"`
+
+	// Properties describing the properties of the managed key.
+	Key ukov4.KeyProperties `json:"key"`
+
+	// The type and group of the target keystores the managed keys are to be installed in.
+	Keystores []ukov4.KeystoresPropertiesCreate `json:"keystores"`
+}
+
+// Key : The desired configuration of a managed key.
+type Key struct {
+	// The label of the key, which identifies it.
+	Label string `json:"label"`
+
+	// The name of the template the key is created from.
+	Template string `json:"template"`
+
+	// Description of the managed key.
+	Description *string `json:"description,omitempty"`
+}
+
+// Parse reads a manifest in YAML or JSON format. Unknown properties are rejected so that misspelled ones are not
+// silently ignored.
+func Parse(data []byte) (manifest *Manifest, err error) {
+	var document interface{}
+	err = yaml.Unmarshal(data, &document)
+	if err != nil {
+		err = fmt.Errorf("error parsing manifest: %s", err.Error())
+		return
+	}
+	buffer, err := json.Marshal(document)
+	if err != nil {
+		err = fmt.Errorf("error parsing manifest: %s", err.Error())
+		return
+	}
+	decoder := json.NewDecoder(bytes.NewReader(buffer))
+	decoder.DisallowUnknownFields()
+	manifest = new(Manifest)
+	err = decoder.Decode(manifest)
+	if err != nil {
+		err = fmt.Errorf("error parsing manifest: %s", err.Error())
+		return nil, err
+	}
+	err = manifest.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// Load reads a manifest from a YAML or JSON file.
+func Load(path string) (*Manifest, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Validate checks that every resource of the manifest has an identifier, that identifiers are unique and that managed
+// keys refer to templates of their vault.
+func (manifest *Manifest) Validate() error {
+	var problems []string
+	vaults := map[string]bool{}
+	for _, vault := range manifest.Vaults {
+		if vault.Name == "" {
+			problems = append(problems, "vault without a name")
+			continue
+		}
+		if vaults[vault.Name] {
+			problems = append(problems, fmt.Sprintf("duplicate vault '%s'", vault.Name))
+		}
+		vaults[vault.Name] = true
+
+		keystores := map[string]bool{}
+		for _, keystore := range vault.Keystores {
+			name := stringValue(keystore.Name)
+			switch {
+			case name == "":
+				problems = append(problems, fmt.Sprintf("keystore without a name in vault '%s'", vault.Name))
+			case keystores[name]:
+				problems = append(problems, fmt.Sprintf("duplicate keystore '%s' in vault '%s'", name, vault.Name))
+			case keystore.Type == nil:
+				problems = append(problems, fmt.Sprintf("keystore '%s' in vault '%s' has no type", name, vault.Name))
+			}
+			keystores[name] = true
+		}
+
+		templates := map[string]bool{}
+		for _, template := range vault.Templates {
+			switch {
+			case template.Name == "":
+				problems = append(problems, fmt.Sprintf("template without a name in vault '%s'", vault.Name))
+			case templates[template.Name]:
+				problems = append(problems, fmt.Sprintf("duplicate template '%s' in vault '%s'", template.Name, vault.Name))
+			}
+			templates[template.Name] = true
+		}
+
+		keys := map[string]bool{}
+		for _, key := range vault.Keys {
+			switch {
+			case key.Label == "":
+				problems = append(problems, fmt.Sprintf("managed key without a label in vault '%s'", vault.Name))
+			case keys[key.Label]:
+				problems = append(problems, fmt.Sprintf("duplicate managed key '%s' in vault '%s'", key.Label, vault.Name))
+			case !templates[key.Template]:
+				problems = append(problems, fmt.Sprintf("managed key '%s' in vault '%s' refers to unknown template '%s'",
+					key.Label, vault.Name, key.Template))
+			}
+			keys[key.Label] = true
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid manifest: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,136 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package manifest_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/IBM/ibm-hpcs-uko-sdk/manifest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const testManifestYAML = `
+vaults:
+  - name: payments
+    description: Payment keys
+    keystores:
+      - name: aws-eu
+        type: aws_kms
+        groups: [eu]
+        aws_region: eu-central-1
+        aws_access_key_id: AKIA
+        aws_secret_access_key: secret
+    templates:
+      - name: aes-eu
+        description: AES keys for EU
+        naming_scheme: "PAY-<env>"
+        key:
+          algorithm: aes
+          size: "256"
+          activation_date: P0D
+          expiration_date: P365D
+          state: active
+        keystores:
+          - group: eu
+            type: aws_kms
+    keys:
+      - label: PAY-prod
+        template: aes-eu
+        description: Production key
+`
+
+var _ = Describe(`Manifest`, func() {
+	Describe(`Parse`, func() {
+		It(`Parse a YAML manifest`, func() {
+			m, err := manifest.Parse([]byte(testManifestYAML))
+			Expect(err).To(BeNil())
+			Expect(m.Vaults).To(HaveLen(1))
+			vault := m.Vaults[0]
+			Expect(vault.Name).To(Equal("payments"))
+			Expect(*vault.Description).To(Equal("Payment keys"))
+			Expect(*vault.Keystores[0].Type).To(Equal("aws_kms"))
+			Expect(vault.Keystores[0].Groups).To(Equal([]string{"eu"}))
+			Expect(*vault.Templates[0].Key.Size).To(Equal("256"))
+			Expect(*vault.Templates[0].Keystores[0].Group).To(Equal("eu"))
+			Expect(vault.Keys[0].Template).To(Equal("aes-eu"))
+		})
+		It(`Parse a JSON manifest`, func() {
+			m, err := manifest.Parse([]byte(`{"vaults": [{"name": "v", "description": "d"}]}`))
+			Expect(err).To(BeNil())
+			Expect(m.Vaults[0].Name).To(Equal("v"))
+		})
+		It(`Reject unknown properties`, func() {
+			_, err := manifest.Parse([]byte("vaults:\n  - name: v\n    descripton: typo\n"))
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("descripton"))
+		})
+		It(`Reject malformed documents`, func() {
+			_, err := manifest.Parse([]byte("vaults: [\n"))
+			Expect(err).ToNot(BeNil())
+			_, err = manifest.Parse([]byte("vaults: 3\n"))
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Describe(`Load`, func() {
+		It(`Load a manifest from a file`, func() {
+			dir, err := ioutil.TempDir("", "manifest")
+			Expect(err).To(BeNil())
+			defer os.RemoveAll(dir)
+			path := filepath.Join(dir, "uko.yaml")
+			Expect(ioutil.WriteFile(path, []byte(testManifestYAML), 0600)).To(Succeed())
+
+			m, err := manifest.Load(path)
+			Expect(err).To(BeNil())
+			Expect(m.Vaults[0].Name).To(Equal("payments"))
+
+			_, err = manifest.Load(filepath.Join(dir, "missing.yaml"))
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Describe(`Validate`, func() {
+		It(`Report every problem`, func() {
+			_, err := manifest.Parse([]byte(`
+vaults:
+  - name: v
+    keystores:
+      - name: ks
+        type: aws_kms
+      - name: ks
+        type: aws_kms
+      - type: cca
+    templates:
+      - name: t
+        key: {algorithm: aes}
+        keystores: []
+    keys:
+      - label: k
+        template: unknown
+  - name: v
+`))
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("duplicate keystore 'ks' in vault 'v'"))
+			Expect(err.Error()).To(ContainSubstring("keystore without a name in vault 'v'"))
+			Expect(err.Error()).To(ContainSubstring("refers to unknown template 'unknown'"))
+			Expect(err.Error()).To(ContainSubstring("duplicate vault 'v'"))
+		})
+	})
+})
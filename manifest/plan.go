@@ -0,0 +1,685 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-hpcs-uko-sdk/common"
+	"github.com/IBM/ibm-hpcs-uko-sdk/ukov4"
+)
+
+// Constants associated with the Action.Operation property.
+// The operation an action performs.
+const (
+	Action_Operation_Archive   = "archive"
+	Action_Operation_Create    = "create"
+	Action_Operation_Unarchive = "unarchive"
+	Action_Operation_Update    = "update"
+)
+
+// Constants associated with the Action.ResourceType property.
+// The type of resource an action applies to.
+const (
+	Action_ResourceType_KeyTemplate = "key_template"
+	Action_ResourceType_Keystore    = "keystore"
+	Action_ResourceType_ManagedKey  = "managed_key"
+	Action_ResourceType_Vault       = "vault"
+)
+
+// keystoreCredentialFields are the keystore properties holding credentials. The service does not return their values,
+// so they are only sent when a keystore is created.
+var keystoreCredentialFields = map[string]bool{
+	"aws_access_key_id":                true,
+	"aws_secret_access_key":            true,
+	"azure_service_principal_password": true,
+	"google_credentials":               true,
+	"ibm_api_key":                      true,
+}
+
+// keystoreImmutableFields are the keystore properties that cannot be changed once a keystore is created.
+var keystoreImmutableFields = map[string]bool{
+	"ibm_variant": true,
+	"type":        true,
+}
+
+// Plan : The actions that reconcile the live state of a UKO instance with a manifest, in the order they are applied.
+// A plan can be marshalled to JSON for review, but only applied in the process that made it: the operations an action
+// performs are not marshalled, so a plan unmarshalled from JSON cannot be applied.
+type Plan struct {
+	// The actions of the plan.
+	Actions []Action `json:"actions"`
+}
+
+// Action : A single create, update, archive or unarchive of a resource.
+type Action struct {
+	// The operation the action performs.
+	Operation string `json:"operation"`
+
+	// The type of resource the action applies to.
+	ResourceType string `json:"resource_type"`
+
+	// The name of the vault the resource belongs to.
+	Vault string `json:"vault"`
+
+	// The name of the resource, or the label of a managed key.
+	Name string `json:"name"`
+
+	// The ID of the resource; empty if it does not exist yet.
+	ID string `json:"id,omitempty"`
+
+	// The ETag of the live state the action was planned against; empty if the resource does not exist yet.
+	ETag string `json:"etag,omitempty"`
+
+	// The properties an update changes.
+	Changes []Change `json:"changes,omitempty"`
+
+	// The operation, set by NewPlan; nil for an action unmarshalled from JSON.
+	apply applyFunc
+}
+
+// Change : A property changed by an update.
+type Change struct {
+	// The name of the property, such as "description" or "key.size".
+	Field string `json:"field"`
+
+	// The live value of the property.
+	Old interface{} `json:"old"`
+
+	// The value of the property in the manifest.
+	New interface{} `json:"new"`
+}
+
+// String returns a one-line description of the action.
+func (action *Action) String() string {
+	name := action.Name
+	if action.ResourceType != Action_ResourceType_Vault {
+		name = action.Vault + "/" + name
+	}
+	return fmt.Sprintf("%s %s '%s'", action.Operation, action.ResourceType, name)
+}
+
+// IsEmpty returns true if the live state already matches the manifest.
+func (plan *Plan) IsEmpty() bool {
+	return len(plan.Actions) == 0
+}
+
+// String returns a description of the plan with one line per action and one indented line per changed property.
+func (plan *Plan) String() string {
+	var builder strings.Builder
+	for i := range plan.Actions {
+		action := &plan.Actions[i]
+		builder.WriteString(action.String())
+		builder.WriteString("\n")
+		for _, change := range action.Changes {
+			fmt.Fprintf(&builder, "    %s: %s -> %s\n", change.Field, formatValue(change.Old), formatValue(change.New))
+		}
+	}
+	return builder.String()
+}
+
+func formatValue(value interface{}) string {
+	if value == nil {
+		return "<unset>"
+	}
+	buffer, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(buffer)
+}
+
+// PlanError : The error returned when the manifest asks for changes that the service cannot make, such as changing the
+// algorithm of an existing key template.
+type PlanError struct {
+	// A description of each change that cannot be made.
+	Problems []string
+}
+
+// Error implements the error interface.
+func (e *PlanError) Error() string {
+	return fmt.Sprintf("manifest cannot be applied: %s", strings.Join(e.Problems, "; "))
+}
+
+// NewPlan compares a manifest with the live state of the UKO instance and returns the actions that reconcile them.
+// Each existing resource is read along with its ETag, which Apply uses to detect changes made after planning. Vaults, keystores and managed keys that are not in the manifest are left untouched; user-defined key templates of a
+// managed vault that are not in the manifest are archived. Changes the service cannot make are returned together in a
+// *PlanError.
+func NewPlan(service *ukov4.UkoV4, manifest *Manifest) (*Plan, error) {
+	return NewPlanWithContext(context.Background(), service, manifest)
+}
+
+// NewPlanWithContext is an alternate form of the NewPlan function which supports a Context parameter
+func NewPlanWithContext(ctx context.Context, service *ukov4.UkoV4, manifest *Manifest) (plan *Plan, err error) {
+	err = core.ValidateNotNil(service, "service cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateNotNil(manifest, "manifest cannot be nil")
+	if err != nil {
+		return
+	}
+	err = manifest.Validate()
+	if err != nil {
+		return
+	}
+
+	vaultsPager, err := service.NewVaultsPager(service.NewListVaultsOptions())
+	if err != nil {
+		return
+	}
+	liveVaults, err := vaultsPager.GetAllWithContext(ctx)
+	if err != nil {
+		return
+	}
+	vaultsByName := map[string]*ukov4.Vault{}
+	for i := range liveVaults {
+		if liveVaults[i].Name != nil {
+			vaultsByName[*liveVaults[i].Name] = &liveVaults[i]
+		}
+	}
+
+	planner := &planner{ctx: ctx, service: service, plan: &Plan{}}
+	for i := range manifest.Vaults {
+		err = planner.planVault(&manifest.Vaults[i], vaultsByName[manifest.Vaults[i].Name])
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(planner.problems) > 0 {
+		return nil, &PlanError{Problems: planner.problems}
+	}
+	return planner.plan, nil
+}
+
+// planner accumulates the actions and problems found while comparing a manifest with the live state.
+type planner struct {
+	ctx      context.Context
+	service  *ukov4.UkoV4
+	plan     *Plan
+	problems []string
+}
+
+func (p *planner) add(action Action) {
+	p.plan.Actions = append(p.plan.Actions, action)
+}
+
+func (p *planner) problem(format string, args ...interface{}) {
+	p.problems = append(p.problems, fmt.Sprintf(format, args...))
+}
+
+func (p *planner) planVault(vault *Vault, live *ukov4.Vault) (err error) {
+	var keystores []*ukov4.Keystore
+	var templates []ukov4.Template
+	var keys []ukov4.ManagedKey
+	if live == nil {
+		p.add(Action{
+			Operation:    Action_Operation_Create,
+			ResourceType: Action_ResourceType_Vault,
+			Vault:        vault.Name,
+			Name:         vault.Name,
+			apply:        createVault(vault),
+		})
+	} else {
+		var response *core.DetailedResponse
+		live, response, err = p.service.GetVaultWithContext(p.ctx, p.service.NewGetVaultOptions(*live.ID))
+		if err != nil {
+			return
+		}
+		var changes []Change
+		changes = compareString(changes, "description", live.Description, vault.Description)
+		changes = compareString(changes, "recovery_key_label", live.RecoveryKeyLabel, vault.RecoveryKeyLabel)
+		if len(changes) > 0 {
+			p.add(Action{
+				Operation:    Action_Operation_Update,
+				ResourceType: Action_ResourceType_Vault,
+				Vault:        vault.Name,
+				Name:         vault.Name,
+				ID:           *live.ID,
+				ETag:         response.GetHeaders().Get("ETag"),
+				Changes:      changes,
+				apply:        updateVault(*live.ID, vault),
+			})
+		}
+		keystores, templates, keys, err = p.listVaultContents(*live.ID)
+		if err != nil {
+			return
+		}
+	}
+
+	if err = p.planKeystores(vault, keystores); err != nil {
+		return
+	}
+	archives, err := p.planTemplates(vault, templates)
+	if err != nil {
+		return
+	}
+	if err = p.planKeys(vault, keys); err != nil {
+		return
+	}
+	p.plan.Actions = append(p.plan.Actions, archives...)
+	return
+}
+
+func (p *planner) listVaultContents(vaultID string) (keystores []*ukov4.Keystore, templates []ukov4.Template, keys []ukov4.ManagedKey, err error) {
+	keystoresPager, err := p.service.NewKeystoresPager(p.service.NewListKeystoresOptions().SetVaultID([]string{vaultID}))
+	if err != nil {
+		return
+	}
+	liveKeystores, err := keystoresPager.GetAllWithContext(p.ctx)
+	if err != nil {
+		return
+	}
+	for _, liveKeystore := range liveKeystores {
+		var keystore *ukov4.Keystore
+		keystore, err = asKeystore(liveKeystore)
+		if err != nil {
+			return
+		}
+		keystores = append(keystores, keystore)
+	}
+
+	templatesPager, err := p.service.NewKeyTemplatesPager(p.service.NewListKeyTemplatesOptions().SetVaultID([]string{vaultID}))
+	if err != nil {
+		return
+	}
+	templates, err = templatesPager.GetAllWithContext(p.ctx)
+	if err != nil {
+		return
+	}
+
+	keysPager, err := p.service.NewManagedKeysPager(p.service.NewListManagedKeysOptions().SetVaultID([]string{vaultID}))
+	if err != nil {
+		return
+	}
+	keys, err = keysPager.GetAllWithContext(p.ctx)
+	return
+}
+
+func (p *planner) planKeystores(vault *Vault, live []*ukov4.Keystore) error {
+	byName := map[string]*ukov4.Keystore{}
+	for _, keystore := range live {
+		if keystore.Name != nil {
+			byName[*keystore.Name] = keystore
+		}
+	}
+	for i := range vault.Keystores {
+		keystore := &vault.Keystores[i]
+		name := *keystore.Name
+		listedKeystore, ok := byName[name]
+		if !ok {
+			p.add(Action{
+				Operation:    Action_Operation_Create,
+				ResourceType: Action_ResourceType_Keystore,
+				Vault:        vault.Name,
+				Name:         name,
+				apply:        createKeystore(vault.Name, keystore),
+			})
+			continue
+		}
+		result, response, err := p.service.GetKeystoreWithContext(p.ctx, p.service.NewGetKeystoreOptions(*listedKeystore.ID))
+		if err != nil {
+			return err
+		}
+		liveKeystore, err := asKeystore(result)
+		if err != nil {
+			return err
+		}
+
+		desired, err := toMap(keystore)
+		if err != nil {
+			p.problem("keystore '%s' in vault '%s': %s", name, vault.Name, err.Error())
+			continue
+		}
+		current, err := toMap(liveKeystore)
+		if err != nil {
+			p.problem("keystore '%s' in vault '%s': %s", name, vault.Name, err.Error())
+			continue
+		}
+		var changes []Change
+		update := map[string]interface{}{}
+		for _, field := range sortedKeys(desired) {
+			if field == "vault" || keystoreCredentialFields[field] {
+				continue
+			}
+			if equalValues(field, current[field], desired[field]) {
+				continue
+			}
+			if keystoreImmutableFields[field] {
+				p.problem("keystore '%s' in vault '%s': %s cannot be changed from %s to %s", name, vault.Name, field,
+					formatValue(current[field]), formatValue(desired[field]))
+				continue
+			}
+			changes = append(changes, Change{Field: field, Old: current[field], New: desired[field]})
+			update[field] = desired[field]
+		}
+		if len(changes) == 0 {
+			continue
+		}
+		body := new(ukov4.KeystoreUpdateRequest)
+		err = common.Remarshal(update, body)
+		if err != nil {
+			p.problem("keystore '%s' in vault '%s': %s", name, vault.Name, err.Error())
+			continue
+		}
+		p.add(Action{
+			Operation:    Action_Operation_Update,
+			ResourceType: Action_ResourceType_Keystore,
+			Vault:        vault.Name,
+			Name:         name,
+			ID:           *liveKeystore.ID,
+			ETag:         response.GetHeaders().Get("ETag"),
+			Changes:      changes,
+			apply:        updateKeystore(*liveKeystore.ID, body),
+		})
+	}
+	return nil
+}
+
+// planTemplates adds the actions for the key templates of a vault and returns the archive actions, which are applied
+// once the managed keys of the vault have been reconciled.
+func (p *planner) planTemplates(vault *Vault, live []ukov4.Template) (archives []Action, err error) {
+	byName := map[string]*ukov4.Template{}
+	for i := range live {
+		if live[i].Name != nil {
+			byName[*live[i].Name] = &live[i]
+		}
+	}
+	inManifest := map[string]bool{}
+	for i := range vault.Templates {
+		template := &vault.Templates[i]
+		inManifest[template.Name] = true
+		listedTemplate, ok := byName[template.Name]
+		if !ok {
+			p.add(Action{
+				Operation:    Action_Operation_Create,
+				ResourceType: Action_ResourceType_KeyTemplate,
+				Vault:        vault.Name,
+				Name:         template.Name,
+				apply:        createKeyTemplate(vault.Name, template),
+			})
+			continue
+		}
+		liveTemplate, response, getErr := p.service.GetKeyTemplateWithContext(p.ctx,
+			p.service.NewGetKeyTemplateOptions(*listedTemplate.ID))
+		if getErr != nil {
+			return nil, getErr
+		}
+		etag := response.GetHeaders().Get("ETag")
+		if core.StringNilMapper(liveTemplate.State) == ukov4.Template_State_Archived {
+			p.add(Action{
+				Operation:    Action_Operation_Unarchive,
+				ResourceType: Action_ResourceType_KeyTemplate,
+				Vault:        vault.Name,
+				Name:         template.Name,
+				ID:           *liveTemplate.ID,
+				ETag:         etag,
+				apply:        unarchiveKeyTemplate(*liveTemplate.ID),
+			})
+		}
+		p.planTemplateUpdate(vault, template, liveTemplate, etag)
+	}
+
+	for i := range live {
+		if live[i].Name == nil || inManifest[*live[i].Name] ||
+			!core.SliceContains(live[i].Type, ukov4.Template_Type_UserDefined) {
+			continue
+		}
+		liveTemplate, response, getErr := p.service.GetKeyTemplateWithContext(p.ctx, p.service.NewGetKeyTemplateOptions(*live[i].ID))
+		if getErr != nil {
+			return nil, getErr
+		}
+		if core.StringNilMapper(liveTemplate.State) == ukov4.Template_State_Archived {
+			continue
+		}
+		archives = append(archives, Action{
+			Operation:    Action_Operation_Archive,
+			ResourceType: Action_ResourceType_KeyTemplate,
+			Vault:        vault.Name,
+			Name:         *liveTemplate.Name,
+			ID:           *liveTemplate.ID,
+			ETag:         response.GetHeaders().Get("ETag"),
+			apply:        archiveKeyTemplate(*liveTemplate.ID),
+		})
+	}
+	return
+}
+
+func (p *planner) planTemplateUpdate(vault *Vault, template *Template, live *ukov4.Template, etag string) {
+	problem := func(format string, args ...interface{}) {
+		p.problem("key template '%s' in vault '%s': %s", template.Name, vault.Name, fmt.Sprintf(format, args...))
+	}
+	liveKey := live.Key
+	if liveKey == nil {
+		liveKey = new(ukov4.KeyProperties)
+	}
+	if isChanged(liveKey.Algorithm, template.Key.Algorithm) {
+		problem("key.algorithm cannot be changed from %s to %s", formatValue(liveKey.Algorithm), formatValue(template.Key.Algorithm))
+	}
+	if isChanged(live.NamingScheme, template.NamingScheme) {
+		problem("naming_scheme cannot be changed from %s to %s", formatValue(live.NamingScheme), formatValue(template.NamingScheme))
+	}
+
+	var changes []Change
+	changes = compareString(changes, "description", live.Description, template.Description)
+
+	keyUpdate := new(ukov4.KeyPropertiesUpdate)
+	keyChanges := len(changes)
+	changes = compareString(changes, "key.size", liveKey.Size, template.Key.Size)
+	changes = compareString(changes, "key.activation_date", liveKey.ActivationDate, template.Key.ActivationDate)
+	changes = compareString(changes, "key.expiration_date", liveKey.ExpirationDate, template.Key.ExpirationDate)
+	changes = compareString(changes, "key.state", liveKey.State, template.Key.State)
+	if template.Key.DeactivateOnRotation != nil &&
+		(liveKey.DeactivateOnRotation == nil || *liveKey.DeactivateOnRotation != *template.Key.DeactivateOnRotation) {
+		changes = append(changes, Change{"key.deactivate_on_rotation", liveKey.DeactivateOnRotation, template.Key.DeactivateOnRotation})
+	}
+	if len(changes) > keyChanges {
+		keyUpdate.Size = template.Key.Size
+		keyUpdate.ActivationDate = template.Key.ActivationDate
+		keyUpdate.ExpirationDate = template.Key.ExpirationDate
+		keyUpdate.State = template.Key.State
+		keyUpdate.DeactivateOnRotation = template.Key.DeactivateOnRotation
+	} else {
+		keyUpdate = nil
+	}
+
+	liveGroups := map[string]*ukov4.KeystoresPropertiesCreate{}
+	for _, liveKeystore := range live.Keystores {
+		properties := new(ukov4.KeystoresPropertiesCreate)
+		if err := common.Remarshal(liveKeystore, properties); err != nil {
+			problem(err.Error())
+			return
+		}
+		liveGroups[core.StringNilMapper(properties.Group)] = properties
+	}
+	desiredGroups := map[string]bool{}
+	keystoresChanged := false
+	var keystoresUpdate []ukov4.KeystoresPropertiesUpdateIntf
+	for i := range template.Keystores {
+		keystore := &template.Keystores[i]
+		group := core.StringNilMapper(keystore.Group)
+		desiredGroups[group] = true
+		liveKeystore, ok := liveGroups[group]
+		if !ok {
+			problem("keystore group '%s' cannot be added", group)
+			continue
+		}
+		prefix := fmt.Sprintf("keystores[%s].", group)
+		if isChanged(liveKeystore.Type, keystore.Type) {
+			problem("%stype cannot be changed from %s to %s", prefix, formatValue(liveKeystore.Type), formatValue(keystore.Type))
+		}
+		if isChanged(liveKeystore.NamingScheme, keystore.NamingScheme) {
+			problem("%snaming_scheme cannot be changed", prefix)
+		}
+		if isChanged(liveKeystore.AzureKeyProtectionLevel, keystore.AzureKeyProtectionLevel) ||
+			keystore.AzureKeyOperations != nil && !equalValues("", toSortedStrings(liveKeystore.AzureKeyOperations), toSortedStrings(keystore.AzureKeyOperations)) {
+			problem("%sazure properties cannot be changed", prefix)
+		}
+		before := len(changes)
+		changes = compareString(changes, prefix+"google_key_protection_level", liveKeystore.GoogleKeyProtectionLevel, keystore.GoogleKeyProtectionLevel)
+		changes = compareString(changes, prefix+"google_key_purpose", liveKeystore.GoogleKeyPurpose, keystore.GoogleKeyPurpose)
+		changes = compareString(changes, prefix+"google_kms_algorithm", liveKeystore.GoogleKmsAlgorithm, keystore.GoogleKmsAlgorithm)
+		changes = compareString(changes, prefix+"cca_usage_control", liveKeystore.CcaUsageControl, keystore.CcaUsageControl)
+		changes = compareString(changes, prefix+"cca_key_type", liveKeystore.CcaKeyType, keystore.CcaKeyType)
+		if keystore.CcaKeyWords != nil && !reflect.DeepEqual(liveKeystore.CcaKeyWords, keystore.CcaKeyWords) {
+			changes = append(changes, Change{prefix + "cca_key_words", liveKeystore.CcaKeyWords, keystore.CcaKeyWords})
+		}
+		keystoresChanged = keystoresChanged || len(changes) > before
+		keystoresUpdate = append(keystoresUpdate, &ukov4.KeystoresPropertiesUpdate{
+			Group:                    keystore.Group,
+			GoogleKeyProtectionLevel: keystore.GoogleKeyProtectionLevel,
+			GoogleKeyPurpose:         keystore.GoogleKeyPurpose,
+			GoogleKmsAlgorithm:       keystore.GoogleKmsAlgorithm,
+			CcaUsageControl:          keystore.CcaUsageControl,
+			CcaKeyType:               keystore.CcaKeyType,
+			CcaKeyWords:              keystore.CcaKeyWords,
+		})
+	}
+	for _, group := range sortedKeys(liveGroups) {
+		if !desiredGroups[group] {
+			problem("keystore group '%s' cannot be removed", group)
+		}
+	}
+	if !keystoresChanged {
+		keystoresUpdate = nil
+	}
+
+	if len(changes) == 0 {
+		return
+	}
+	p.add(Action{
+		Operation:    Action_Operation_Update,
+		ResourceType: Action_ResourceType_KeyTemplate,
+		Vault:        vault.Name,
+		Name:         template.Name,
+		ID:           *live.ID,
+		ETag:         etag,
+		Changes:      changes,
+		apply:        updateKeyTemplate(*live.ID, template.Description, keyUpdate, keystoresUpdate),
+	})
+}
+
+func (p *planner) planKeys(vault *Vault, live []ukov4.ManagedKey) error {
+	byLabel := map[string]*ukov4.ManagedKey{}
+	for i := range live {
+		if live[i].Label != nil {
+			byLabel[*live[i].Label] = &live[i]
+		}
+	}
+	for i := range vault.Keys {
+		key := &vault.Keys[i]
+		listedKey, ok := byLabel[key.Label]
+		if !ok {
+			p.add(Action{
+				Operation:    Action_Operation_Create,
+				ResourceType: Action_ResourceType_ManagedKey,
+				Vault:        vault.Name,
+				Name:         key.Label,
+				apply:        createManagedKey(vault.Name, key),
+			})
+			continue
+		}
+		liveKey, response, err := p.service.GetManagedKeyWithContext(p.ctx, p.service.NewGetManagedKeyOptions(*listedKey.ID))
+		if err != nil {
+			return err
+		}
+		if liveKey.Template != nil && liveKey.Template.Name != nil && *liveKey.Template.Name != key.Template {
+			p.problem("managed key '%s' in vault '%s': template cannot be changed from '%s' to '%s'", key.Label, vault.Name,
+				*liveKey.Template.Name, key.Template)
+		}
+		changes := compareString(nil, "description", liveKey.Description, key.Description)
+		if len(changes) > 0 {
+			p.add(Action{
+				Operation:    Action_Operation_Update,
+				ResourceType: Action_ResourceType_ManagedKey,
+				Vault:        vault.Name,
+				Name:         key.Label,
+				ID:           *liveKey.ID,
+				ETag:         response.GetHeaders().Get("ETag"),
+				Changes:      changes,
+				apply:        updateManagedKey(*liveKey.ID, key),
+			})
+		}
+	}
+	return nil
+}
+
+// compareString appends a change if the desired value is set and differs from the live value.
+func compareString(changes []Change, field string, live *string, desired *string) []Change {
+	if isChanged(live, desired) {
+		changes = append(changes, Change{Field: field, Old: live, New: desired})
+	}
+	return changes
+}
+
+// isChanged returns true if the desired value is set and differs from the live value.
+func isChanged(live *string, desired *string) bool {
+	return desired != nil && (live == nil || *live != *desired)
+}
+
+// equalValues compares two JSON values. Groups are compared as sets.
+func equalValues(field string, live interface{}, desired interface{}) bool {
+	if field == "groups" {
+		live, desired = toSortedStrings(live), toSortedStrings(desired)
+	}
+	return reflect.DeepEqual(live, desired)
+}
+
+func toSortedStrings(value interface{}) []string {
+	var result []string
+	switch values := value.(type) {
+	case []string:
+		result = append(result, values...)
+	case []interface{}:
+		for _, v := range values {
+			result = append(result, fmt.Sprint(v))
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	for _, key := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// toMap returns the JSON representation of a model as a map.
+func toMap(model interface{}) (result map[string]interface{}, err error) {
+	err = common.Remarshal(model, &result)
+	return
+}
+
+// asKeystore returns the generic keystore model of a keystore returned by the service.
+func asKeystore(keystore ukov4.KeystoreIntf) (*ukov4.Keystore, error) {
+	if result, ok := keystore.(*ukov4.Keystore); ok {
+		return result, nil
+	}
+	result := new(ukov4.Keystore)
+	err := common.Remarshal(keystore, result)
+	return result, err
+}
@@ -0,0 +1,255 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package manifest_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-hpcs-uko-sdk/manifest"
+	"github.com/IBM/ibm-hpcs-uko-sdk/ukov4"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// fakeUKO is an in-memory UKO instance serving the list, get, create and update operations used by plans. The ETag
+// of a resource changes with each update.
+type fakeUKO struct {
+	server    *httptest.Server
+	resources map[string][]map[string]interface{}
+	versions  map[string]int
+	requests  []string
+	nextID    int
+}
+
+func newFakeUKO() *fakeUKO {
+	fake := &fakeUKO{resources: map[string][]map[string]interface{}{}, versions: map[string]int{}}
+	fake.server = httptest.NewServer(http.HandlerFunc(fake.serve))
+	return fake
+}
+
+func (fake *fakeUKO) add(collection string, resource string) map[string]interface{} {
+	var item map[string]interface{}
+	Expect(json.Unmarshal([]byte(resource), &item)).To(Succeed())
+	fake.resources[collection] = append(fake.resources[collection], item)
+	return item
+}
+
+func (fake *fakeUKO) find(collection string, id string) map[string]interface{} {
+	for _, item := range fake.resources[collection] {
+		if item["id"] == id {
+			return item
+		}
+	}
+	return nil
+}
+
+func (fake *fakeUKO) etag(id string) string {
+	return fmt.Sprintf("etag-%s-%d", id, fake.versions[id])
+}
+
+// update changes a property of a resource as another client would.
+func (fake *fakeUKO) update(collection string, id string, field string, value interface{}) {
+	fake.find(collection, id)[field] = value
+	fake.versions[id]++
+}
+
+func (fake *fakeUKO) service() *ukov4.UkoV4 {
+	ukoService, err := ukov4.NewUkoV4(&ukov4.UkoV4Options{
+		URL:           fake.server.URL,
+		Authenticator: &core.NoAuthAuthenticator{},
+	})
+	Expect(err).To(BeNil())
+	return ukoService
+}
+
+func (fake *fakeUKO) serve(res http.ResponseWriter, req *http.Request) {
+	defer GinkgoRecover()
+
+	res.Header().Set("Content-type", "application/json")
+	parts := strings.Split(strings.TrimPrefix(req.URL.Path, "/api/v4/"), "/")
+	collection := parts[0]
+	if req.Method != http.MethodGet {
+		fake.requests = append(fake.requests, req.Method+" "+req.URL.Path)
+		if len(parts) > 1 {
+			if req.Header.Get("If-Match") != fake.etag(parts[1]) {
+				fake.write(res, 412, map[string]interface{}{"errors": []map[string]string{{"code": "PRECONDITION_FAILED"}}})
+				return
+			}
+			fake.versions[parts[1]]++
+			res.Header().Set("ETag", fake.etag(parts[1]))
+		}
+	}
+
+	switch {
+	case req.Method == http.MethodGet && len(parts) == 1:
+		items := []map[string]interface{}{}
+		vaultID := req.URL.Query().Get("vault.id")
+		for _, item := range fake.resources[collection] {
+			if vault, ok := item["vault"].(map[string]interface{}); vaultID == "" || ok && vault["id"] == vaultID {
+				items = append(items, item)
+			}
+		}
+		fake.write(res, 200, map[string]interface{}{"total_count": len(items), "limit": 1000, "offset": 0, collection: items})
+	case req.Method == http.MethodGet && len(parts) == 2:
+		item := fake.find(collection, parts[1])
+		Expect(item).ToNot(BeNil())
+		res.Header().Set("ETag", fake.etag(parts[1]))
+		fake.write(res, 200, item)
+	case req.Method == http.MethodPost && len(parts) == 1:
+		var item map[string]interface{}
+		Expect(json.NewDecoder(req.Body).Decode(&item)).To(Succeed())
+		fake.nextID++
+		item["id"] = fmt.Sprintf("new%d", fake.nextID)
+		if collection == "templates" {
+			item["type"] = []string{"user_defined"}
+			item["state"] = "unarchived"
+		}
+		fake.resources[collection] = append(fake.resources[collection], item)
+		fake.write(res, 201, item)
+	case req.Method == http.MethodPatch && len(parts) == 2:
+		item := fake.find(collection, parts[1])
+		Expect(item).ToNot(BeNil())
+		var update map[string]interface{}
+		Expect(json.NewDecoder(req.Body).Decode(&update)).To(Succeed())
+		for field, value := range update {
+			nested, isMap := value.(map[string]interface{})
+			current, wasMap := item[field].(map[string]interface{})
+			if isMap && wasMap {
+				for name, v := range nested {
+					current[name] = v
+				}
+				continue
+			}
+			item[field] = value
+		}
+		fake.write(res, 200, item)
+	case req.Method == http.MethodPost && len(parts) == 3:
+		item := fake.find(collection, parts[1])
+		Expect(item).ToNot(BeNil())
+		item["state"] = parts[2] + "d"
+		fake.write(res, 200, item)
+	default:
+		Fail(fmt.Sprintf("unexpected request %s %s", req.Method, req.URL.Path))
+	}
+}
+
+func (fake *fakeUKO) write(res http.ResponseWriter, status int, body interface{}) {
+	res.WriteHeader(status)
+	Expect(json.NewEncoder(res).Encode(body)).To(Succeed())
+}
+
+// addLiveState adds a vault matching testManifestYAML, except for the given differences.
+func addLiveState(fake *fakeUKO) {
+	fake.add("vaults", `{"id": "v1", "name": "payments", "description": "Old description"}`)
+	fake.add("keystores", `{"id": "ks1", "vault": {"id": "v1"}, "name": "aws-eu", "type": "aws_kms", "groups": ["eu"], "aws_region": "eu-west-1"}`)
+	fake.add("templates", `{"id": "t1", "vault": {"id": "v1"}, "name": "aes-eu", "description": "AES keys for EU", "naming_scheme": "PAY-<env>", "type": ["user_defined"], "state": "unarchived", "key": {"algorithm": "aes", "size": "128", "activation_date": "P0D", "expiration_date": "P365D", "state": "active"}, "keystores": [{"group": "eu", "type": "aws_kms"}]}`)
+	fake.add("templates", `{"id": "t2", "vault": {"id": "v1"}, "name": "obsolete", "type": ["user_defined"], "state": "unarchived", "key": {"algorithm": "aes"}, "keystores": []}`)
+	fake.add("templates", `{"id": "t3", "vault": {"id": "v1"}, "name": "system-template", "type": ["system"], "state": "unarchived", "key": {"algorithm": "aes"}, "keystores": []}`)
+	fake.add("managed_keys", `{"id": "k1", "vault": {"id": "v1"}, "label": "PAY-prod", "template": {"id": "t1", "name": "aes-eu"}, "description": "Production key"}`)
+	fake.add("managed_keys", `{"id": "k2", "vault": {"id": "v1"}, "label": "PAY-unmanaged", "template": {"id": "t1", "name": "aes-eu"}}`)
+}
+
+var _ = Describe(`NewPlan`, func() {
+	var fake *fakeUKO
+	var m *manifest.Manifest
+
+	BeforeEach(func() {
+		fake = newFakeUKO()
+		var err error
+		m, err = manifest.Parse([]byte(testManifestYAML))
+		Expect(err).To(BeNil())
+	})
+	AfterEach(func() {
+		fake.server.Close()
+	})
+
+	It(`Invoke NewPlan with invalid arguments (negative test)`, func() {
+		_, err := manifest.NewPlan(nil, m)
+		Expect(err).ToNot(BeNil())
+		_, err = manifest.NewPlan(fake.service(), nil)
+		Expect(err).ToNot(BeNil())
+	})
+	It(`Create everything in a new vault`, func() {
+		plan, err := manifest.NewPlan(fake.service(), m)
+		Expect(err).To(BeNil())
+		Expect(plan.String()).To(Equal(
+			"create vault 'payments'\n" +
+				"create keystore 'payments/aws-eu'\n" +
+				"create key_template 'payments/aes-eu'\n" +
+				"create managed_key 'payments/PAY-prod'\n"))
+		Expect(fake.requests).To(BeEmpty())
+	})
+	It(`Update changed resources and archive unlisted templates`, func() {
+		addLiveState(fake)
+		plan, err := manifest.NewPlan(fake.service(), m)
+		Expect(err).To(BeNil())
+		Expect(plan.Actions).To(HaveLen(4))
+
+		Expect(plan.Actions[0].String()).To(Equal("update vault 'payments'"))
+		Expect(plan.Actions[0].ID).To(Equal("v1"))
+		Expect(plan.Actions[0].Changes).To(HaveLen(1))
+		Expect(plan.Actions[0].Changes[0].Field).To(Equal("description"))
+
+		Expect(plan.Actions[1].String()).To(Equal("update keystore 'payments/aws-eu'"))
+		Expect(plan.Actions[1].Changes).To(Equal([]manifest.Change{{Field: "aws_region", Old: "eu-west-1", New: "eu-central-1"}}))
+
+		Expect(plan.Actions[2].String()).To(Equal("update key_template 'payments/aes-eu'"))
+		Expect(plan.Actions[2].Changes).To(HaveLen(1))
+		Expect(plan.Actions[2].Changes[0].Field).To(Equal("key.size"))
+
+		Expect(plan.Actions[3].String()).To(Equal("archive key_template 'payments/obsolete'"))
+		Expect(plan.Actions[3].ID).To(Equal("t2"))
+		Expect(plan.String()).To(ContainSubstring(`    key.size: "128" -> "256"`))
+	})
+	It(`Produce an empty plan when the live state matches`, func() {
+		addLiveState(fake)
+		m.Vaults[0].Description = core.StringPtr("Old description")
+		m.Vaults[0].Keystores[0].AwsRegion = core.StringPtr("eu-west-1")
+		m.Vaults[0].Templates[0].Key.Size = core.StringPtr("128")
+		m.Vaults[0].Templates = append(m.Vaults[0].Templates, manifest.Template{Name: "obsolete"})
+		plan, err := manifest.NewPlan(fake.service(), m)
+		Expect(err).To(BeNil())
+		Expect(plan.IsEmpty()).To(BeTrue())
+	})
+	It(`Unarchive archived templates listed in the manifest`, func() {
+		addLiveState(fake)
+		fake.find("templates", "t1")["state"] = "archived"
+		plan, err := manifest.NewPlan(fake.service(), m)
+		Expect(err).To(BeNil())
+		Expect(plan.String()).To(ContainSubstring("unarchive key_template 'payments/aes-eu'\nupdate key_template 'payments/aes-eu'\n"))
+	})
+	It(`Reject changes the service cannot make`, func() {
+		addLiveState(fake)
+		m.Vaults[0].Keystores[0].Type = core.StringPtr("azure_key_vault")
+		m.Vaults[0].Templates[0].Key.Algorithm = core.StringPtr("rsa")
+		m.Vaults[0].Templates[0].Keystores[0].Group = core.StringPtr("us")
+		_, err := manifest.NewPlan(fake.service(), m)
+		Expect(err).ToNot(BeNil())
+		planErr, ok := err.(*manifest.PlanError)
+		Expect(ok).To(BeTrue())
+		Expect(planErr.Problems).To(HaveLen(4))
+		Expect(err.Error()).To(ContainSubstring("type cannot be changed"))
+		Expect(err.Error()).To(ContainSubstring("key.algorithm cannot be changed"))
+		Expect(err.Error()).To(ContainSubstring("keystore group 'us' cannot be added"))
+		Expect(err.Error()).To(ContainSubstring("keystore group 'eu' cannot be removed"))
+	})
+})
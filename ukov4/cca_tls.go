@@ -0,0 +1,334 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/go-openapi/strfmt"
+)
+
+// DefaultCcaTlsCheckTimeout is the default timeout of CheckCcaTls.
+const DefaultCcaTlsCheckTimeout = 10 * time.Second
+
+// LoadCcaTrustedIssuer reads a PEM file holding the CA certificate that issued the certificate of an EKMF agent, and
+// returns it in the Base64 encoding expected by the cca_trusted_issuer property. The file may hold a chain; every
+// certificate must parse and be currently valid, and the first one must be a CA certificate.
+func LoadCcaTrustedIssuer(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	trustedIssuer, err := EncodeCcaTrustedIssuer(data)
+	if err != nil {
+		return "", fmt.Errorf("%s: %s", path, err.Error())
+	}
+	return trustedIssuer, nil
+}
+
+// EncodeCcaTrustedIssuer validates PEM encoded CA certificates, see LoadCcaTrustedIssuer, and returns them encoded in
+// Base64. Anything in the PEM data other than certificates is dropped.
+func EncodeCcaTrustedIssuer(data []byte) (string, error) {
+	certificates, err := parseCertificates(data)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	var encoded []byte
+	for _, certificate := range certificates {
+		if now.Before(certificate.NotBefore) || now.After(certificate.NotAfter) {
+			return "", fmt.Errorf("the certificate '%s' is only valid from %s to %s", certificate.Subject.String(),
+				certificate.NotBefore.Format(time.RFC3339), certificate.NotAfter.Format(time.RFC3339))
+		}
+		encoded = append(encoded, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Raw})...)
+	}
+	if !certificates[0].IsCA {
+		return "", fmt.Errorf("the certificate '%s' is not a CA certificate", certificates[0].Subject.String())
+	}
+	return base64.StdEncoding.EncodeToString(encoded), nil
+}
+
+// LoadCcaPublicKeyHash reads the signature public key of an EKMF agent and returns the value of the
+// cca_public_key_hash property: the SHA-256 hash of the DER encoded public key (SubjectPublicKeyInfo), in upper case
+// hex. The file may hold a PEM "PUBLIC KEY" block, a PEM certificate, or the DER encoded public key.
+func LoadCcaPublicKeyHash(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	hash, err := ComputeCcaPublicKeyHash(data)
+	if err != nil {
+		return "", fmt.Errorf("%s: %s", path, err.Error())
+	}
+	return hash, nil
+}
+
+// ComputeCcaPublicKeyHash returns the value of the cca_public_key_hash property for a public key, see
+// LoadCcaPublicKeyHash.
+func ComputeCcaPublicKeyHash(data []byte) (string, error) {
+	der := data
+	if block, _ := pem.Decode(data); block != nil {
+		switch block.Type {
+		case "PUBLIC KEY":
+			der = block.Bytes
+		case "CERTIFICATE":
+			certificate, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return "", err
+			}
+			der = certificate.RawSubjectPublicKeyInfo
+		default:
+			return "", fmt.Errorf("unexpected PEM block '%s'; expected a public key or a certificate", block.Type)
+		}
+	}
+	if _, err := x509.ParsePKIXPublicKey(der); err != nil {
+		return "", fmt.Errorf("not a public key: %s", err.Error())
+	}
+	hash := sha256.Sum256(der)
+	return strings.ToUpper(hex.EncodeToString(hash[:])), nil
+}
+
+// CcaTlsCheckReport : The result of a TLS handshake with an EKMF agent.
+type CcaTlsCheckReport struct {
+	// The address that was dialed.
+	Address *string `json:"address"`
+
+	// Whether the TLS handshake completed.
+	Connected *bool `json:"connected"`
+
+	// Whether the certificate chain presented by the agent verified against the trusted issuer.
+	Verified *bool `json:"verified"`
+
+	// The negotiated TLS version, such as "TLS 1.3".
+	TlsVersion *string `json:"tls_version,omitempty"`
+
+	// The certificates presented by the agent, leaf first.
+	PeerCertificates []CcaCertificateInfo `json:"peer_certificates,omitempty"`
+
+	// The problems found.
+	Problems []string `json:"problems,omitempty"`
+}
+
+// CcaCertificateInfo : A certificate presented by an EKMF agent.
+type CcaCertificateInfo struct {
+	// The subject of the certificate.
+	Subject *string `json:"subject"`
+
+	// The issuer of the certificate.
+	Issuer *string `json:"issuer"`
+
+	// Start of the validity period.
+	NotBefore *strfmt.DateTime `json:"not_before"`
+
+	// End of the validity period.
+	NotAfter *strfmt.DateTime `json:"not_after"`
+
+	// Whether the certificate is a CA certificate.
+	IsCA *bool `json:"is_ca"`
+}
+
+// CheckCcaTls performs a TLS handshake with an EKMF agent, as the service would with a CCA keystore using TLS, and
+// reports the certificate chain and its problems: the agent being unreachable, or a chain that does not verify
+// against the trusted issuer because it is expired, issued by another CA or for another host name. Only invalid
+// options are returned as errors.
+func CheckCcaTls(ctx context.Context, checkCcaTlsOptions *CheckCcaTlsOptions) (report *CcaTlsCheckReport, err error) {
+	err = core.ValidateNotNil(checkCcaTlsOptions, "checkCcaTlsOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(checkCcaTlsOptions, "checkCcaTlsOptions")
+	if err != nil {
+		return
+	}
+
+	var roots *x509.CertPool
+	if checkCcaTlsOptions.TrustedIssuer != nil && *checkCcaTlsOptions.TrustedIssuer != "" {
+		var decoded []byte
+		decoded, err = base64.StdEncoding.DecodeString(*checkCcaTlsOptions.TrustedIssuer)
+		if err != nil {
+			err = fmt.Errorf("the trusted issuer must be base64 encoded")
+			return
+		}
+		var certificates []*x509.Certificate
+		certificates, err = parseCertificates(decoded)
+		if err != nil {
+			return
+		}
+		roots = x509.NewCertPool()
+		for _, certificate := range certificates {
+			roots.AddCert(certificate)
+		}
+	}
+
+	host := *checkCcaTlsOptions.Host
+	address := net.JoinHostPort(host, strconv.FormatInt(*checkCcaTlsOptions.Port, 10))
+	report = &CcaTlsCheckReport{
+		Address:   core.StringPtr(address),
+		Connected: core.BoolPtr(false),
+		Verified:  core.BoolPtr(false),
+	}
+	timeout := checkCcaTlsOptions.Timeout
+	if timeout <= 0 {
+		timeout = DefaultCcaTlsCheckTimeout
+	}
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: timeout},
+		// The chain is verified below, so that its problems are reported rather than failing the handshake.
+		Config: &tls.Config{InsecureSkipVerify: true, ServerName: host},
+	}
+	conn, dialErr := dialer.DialContext(ctx, "tcp", address)
+	if dialErr != nil {
+		report.Problems = append(report.Problems, fmt.Sprintf("TLS handshake with %s failed: %s", address, dialErr.Error()))
+		return
+	}
+	defer conn.Close()
+	state := conn.(*tls.Conn).ConnectionState()
+	report.Connected = core.BoolPtr(true)
+	report.TlsVersion = core.StringPtr(tlsVersionName(state.Version))
+
+	now := time.Now()
+	intermediates := x509.NewCertPool()
+	for i, certificate := range state.PeerCertificates {
+		notBefore := strfmt.DateTime(certificate.NotBefore)
+		notAfter := strfmt.DateTime(certificate.NotAfter)
+		report.PeerCertificates = append(report.PeerCertificates, CcaCertificateInfo{
+			Subject:   core.StringPtr(certificate.Subject.String()),
+			Issuer:    core.StringPtr(certificate.Issuer.String()),
+			NotBefore: &notBefore,
+			NotAfter:  &notAfter,
+			IsCA:      core.BoolPtr(certificate.IsCA),
+		})
+		if now.After(certificate.NotAfter.Add(-30*24*time.Hour)) && now.Before(certificate.NotAfter) {
+			report.Problems = append(report.Problems, fmt.Sprintf("the certificate '%s' expires on %s",
+				certificate.Subject.String(), certificate.NotAfter.Format(time.RFC3339)))
+		}
+		if i > 0 {
+			intermediates.AddCert(certificate)
+		}
+	}
+	if len(state.PeerCertificates) == 0 {
+		report.Problems = append(report.Problems, "the agent presented no certificate")
+		return
+	}
+	_, verifyErr := state.PeerCertificates[0].Verify(x509.VerifyOptions{
+		DNSName:       host,
+		Roots:         roots,
+		Intermediates: intermediates,
+	})
+	if verifyErr != nil {
+		report.Problems = append(report.Problems, "the certificate chain does not verify: "+verifyErr.Error())
+		return
+	}
+	report.Verified = core.BoolPtr(true)
+	return
+}
+
+// parseCertificates parses every PEM certificate in the data; there must be at least one.
+func parseCertificates(data []byte) (certificates []*x509.Certificate, err error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		var certificate *x509.Certificate
+		certificate, err = x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return
+		}
+		certificates = append(certificates, certificate)
+	}
+	if len(certificates) == 0 {
+		err = fmt.Errorf("no PEM certificate found")
+	}
+	return
+}
+
+func tlsVersionName(version uint16) string {
+	switch version {
+	case tls.VersionTLS10:
+		return "TLS 1.0"
+	case tls.VersionTLS11:
+		return "TLS 1.1"
+	case tls.VersionTLS12:
+		return "TLS 1.2"
+	case tls.VersionTLS13:
+		return "TLS 1.3"
+	}
+	return fmt.Sprintf("0x%04x", version)
+}
+
+// CheckCcaTlsOptions : The CheckCcaTls options.
+type CheckCcaTlsOptions struct {
+	// The host of the EKMF agent.
+	Host *string `json:"host" validate:"required,ne="`
+
+	// The port of the EKMF agent.
+	Port *int64 `json:"port" validate:"required"`
+
+	// The Base64 encoded PEM certificate of the trusted issuer. The system roots are used if not set.
+	TrustedIssuer *string `json:"trusted_issuer,omitempty"`
+
+	// The timeout of the connection. Defaults to DefaultCcaTlsCheckTimeout.
+	Timeout time.Duration
+}
+
+// NewCheckCcaTlsOptions : Instantiate CheckCcaTlsOptions
+func NewCheckCcaTlsOptions(host string, port int64) *CheckCcaTlsOptions {
+	return &CheckCcaTlsOptions{
+		Host: core.StringPtr(host),
+		Port: core.Int64Ptr(port),
+	}
+}
+
+// NewCheckCcaTlsOptionsForKeystore : Instantiate CheckCcaTlsOptions with the host, port and trusted issuer of a CCA
+// keystore creation request
+func NewCheckCcaTlsOptionsForKeystore(keystore *KeystoreCreationRequestKeystoreTypeCcaCreate) *CheckCcaTlsOptions {
+	return &CheckCcaTlsOptions{
+		Host:          keystore.CcaHost,
+		Port:          keystore.CcaPort,
+		TrustedIssuer: keystore.CcaTrustedIssuer,
+	}
+}
+
+// SetTrustedIssuer : Allow user to set TrustedIssuer
+func (_options *CheckCcaTlsOptions) SetTrustedIssuer(trustedIssuer string) *CheckCcaTlsOptions {
+	_options.TrustedIssuer = core.StringPtr(trustedIssuer)
+	return _options
+}
+
+// SetTimeout : Allow user to set Timeout
+func (_options *CheckCcaTlsOptions) SetTimeout(timeout time.Duration) *CheckCcaTlsOptions {
+	_options.Timeout = timeout
+	return _options
+}
@@ -0,0 +1,184 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IBM/ibm-hpcs-uko-sdk/ukov4"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`CCA TLS material`, func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "cca-tls")
+		Expect(err).To(BeNil())
+	})
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	writeFile := func(name string, contents []byte) string {
+		path := filepath.Join(dir, name)
+		Expect(ioutil.WriteFile(path, contents, 0600)).To(Succeed())
+		return path
+	}
+	decoded := func(encoded string) []byte {
+		data, err := base64.StdEncoding.DecodeString(encoded)
+		Expect(err).To(BeNil())
+		return data
+	}
+
+	It(`Load a trusted issuer from a PEM file`, func() {
+		now := time.Now()
+		certificate := decoded(testCertificate(true, now.Add(-time.Hour), now.Add(time.Hour)))
+		path := writeFile("ca.pem", append([]byte("EKMF agent CA\n"), certificate...))
+
+		trustedIssuer, err := ukov4.LoadCcaTrustedIssuer(path)
+		Expect(err).To(BeNil())
+		Expect(decoded(trustedIssuer)).To(Equal(certificate))
+
+		keystore, err := ukov4.NewCcaKeystore("v1", "ekmf").
+			Endpoint("ekmf.example.com", 9443).
+			PublicKeyHash("AB01").
+			TLS(trustedIssuer).
+			Build()
+		Expect(err).To(BeNil())
+		Expect(*keystore.CcaTrustedIssuer).To(Equal(trustedIssuer))
+	})
+	It(`Reject unusable trusted issuers (negative test)`, func() {
+		now := time.Now()
+		_, err := ukov4.LoadCcaTrustedIssuer(filepath.Join(dir, "missing.pem"))
+		Expect(os.IsNotExist(err)).To(BeTrue())
+
+		_, err = ukov4.LoadCcaTrustedIssuer(writeFile("empty.pem", []byte("not a certificate")))
+		Expect(err.Error()).To(ContainSubstring("no PEM certificate found"))
+
+		_, err = ukov4.EncodeCcaTrustedIssuer(decoded(testCertificate(true, now.Add(-2*time.Hour), now.Add(-time.Hour))))
+		Expect(err.Error()).To(ContainSubstring("is only valid from"))
+
+		_, err = ukov4.EncodeCcaTrustedIssuer(decoded(testCertificate(false, now.Add(-time.Hour), now.Add(time.Hour))))
+		Expect(err.Error()).To(ContainSubstring("is not a CA certificate"))
+	})
+	It(`Compute the public key hash`, func() {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).To(BeNil())
+		der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+		Expect(err).To(BeNil())
+		sum := sha256.Sum256(der)
+		expected := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+		hash, err := ukov4.LoadCcaPublicKeyHash(writeFile("key.pem", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})))
+		Expect(err).To(BeNil())
+		Expect(hash).To(Equal(expected))
+
+		hash, err = ukov4.ComputeCcaPublicKeyHash(der)
+		Expect(err).To(BeNil())
+		Expect(hash).To(Equal(expected))
+
+		_, err = ukov4.ComputeCcaPublicKeyHash(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+		Expect(err.Error()).To(ContainSubstring("unexpected PEM block 'PRIVATE KEY'"))
+		_, err = ukov4.ComputeCcaPublicKeyHash([]byte("garbage"))
+		Expect(err.Error()).To(ContainSubstring("not a public key"))
+	})
+
+	Describe(`CheckCcaTls`, func() {
+		var testServer *httptest.Server
+		var host string
+		var port int64
+
+		BeforeEach(func() {
+			testServer = httptest.NewTLSServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {}))
+			serverURL, err := url.Parse(testServer.URL)
+			Expect(err).To(BeNil())
+			var portString string
+			host, portString, err = net.SplitHostPort(serverURL.Host)
+			Expect(err).To(BeNil())
+			port, err = strconv.ParseInt(portString, 10, 64)
+			Expect(err).To(BeNil())
+		})
+		AfterEach(func() {
+			testServer.Close()
+		})
+
+		It(`Verify the agent certificate against the trusted issuer`, func() {
+			certificate := testServer.Certificate()
+			trustedIssuer := base64.StdEncoding.EncodeToString(
+				pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Raw}))
+			keystore, err := ukov4.NewCcaKeystore("v1", "ekmf").
+				Endpoint(host, port).
+				PublicKeyHash("AB01").
+				TLS(trustedIssuer).
+				Build()
+			Expect(err).To(BeNil())
+
+			report, err := ukov4.CheckCcaTls(context.Background(), ukov4.NewCheckCcaTlsOptionsForKeystore(keystore))
+			Expect(err).To(BeNil())
+			Expect(*report.Connected).To(BeTrue())
+			Expect(*report.Verified).To(BeTrue())
+			Expect(report.Problems).To(BeEmpty())
+			Expect(report.TlsVersion).ToNot(BeNil())
+			Expect(report.PeerCertificates).To(HaveLen(1))
+			Expect(*report.PeerCertificates[0].Subject).To(Equal(certificate.Subject.String()))
+		})
+		It(`Report chain problems (negative test)`, func() {
+			now := time.Now()
+			otherIssuer := testCertificate(true, now.Add(-time.Hour), now.Add(time.Hour))
+			report, err := ukov4.CheckCcaTls(context.Background(),
+				ukov4.NewCheckCcaTlsOptions(host, port).SetTrustedIssuer(otherIssuer).SetTimeout(5*time.Second))
+			Expect(err).To(BeNil())
+			Expect(*report.Connected).To(BeTrue())
+			Expect(*report.Verified).To(BeFalse())
+			Expect(report.Problems).To(HaveLen(1))
+			Expect(report.Problems[0]).To(ContainSubstring("the certificate chain does not verify"))
+
+			testServer.Close()
+			report, err = ukov4.CheckCcaTls(context.Background(), ukov4.NewCheckCcaTlsOptions(host, port))
+			Expect(err).To(BeNil())
+			Expect(*report.Connected).To(BeFalse())
+			Expect(report.Problems[0]).To(ContainSubstring("TLS handshake with"))
+		})
+		It(`Reject invalid options (negative test)`, func() {
+			_, err := ukov4.CheckCcaTls(context.Background(), nil)
+			Expect(err).ToNot(BeNil())
+			_, err = ukov4.CheckCcaTls(context.Background(), ukov4.NewCheckCcaTlsOptions(host, port).SetTrustedIssuer("%%%"))
+			Expect(err.Error()).To(ContainSubstring("must be base64 encoded"))
+		})
+	})
+})
@@ -0,0 +1,392 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+)
+
+// GuardedDestroyManagedKey : Destroy a managed key that no resource depends on
+// Lists the resources associated with the managed key and refuses to destroy it while any of them is registered, unless
+// the caller explicitly overrides the guard and gives a reason. A blocked call returns an *AssociatedResourcesError and
+// no request is made to destroy the key.
+func (uko *UkoV4) GuardedDestroyManagedKey(guardedDestroyManagedKeyOptions *GuardedDestroyManagedKeyOptions) (result *ManagedKey, response *core.DetailedResponse, err error) {
+	return uko.GuardedDestroyManagedKeyWithContext(context.Background(), guardedDestroyManagedKeyOptions)
+}
+
+// GuardedDestroyManagedKeyWithContext is an alternate form of the GuardedDestroyManagedKey method which supports a Context parameter
+func (uko *UkoV4) GuardedDestroyManagedKeyWithContext(ctx context.Context, guardedDestroyManagedKeyOptions *GuardedDestroyManagedKeyOptions) (result *ManagedKey, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(guardedDestroyManagedKeyOptions, "guardedDestroyManagedKeyOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(guardedDestroyManagedKeyOptions, "guardedDestroyManagedKeyOptions")
+	if err != nil {
+		return
+	}
+	options := guardedDestroyManagedKeyOptions
+
+	err = uko.checkManagedKeyDeletionGuard(ctx, "destroy", *options.ID, options.Override, options.OverrideReason)
+	if err != nil {
+		return
+	}
+	return uko.DestroyManagedKeyWithContext(ctx, &DestroyManagedKeyOptions{
+		ID:      options.ID,
+		IfMatch: options.IfMatch,
+		Headers: options.Headers,
+	})
+}
+
+// GuardedDeleteManagedKey : Delete a managed key that no resource depends on
+// Lists the resources associated with the managed key and refuses to delete it while any of them is registered, unless
+// the caller explicitly overrides the guard and gives a reason. A blocked call returns an *AssociatedResourcesError and
+// no request is made to delete the key.
+func (uko *UkoV4) GuardedDeleteManagedKey(guardedDeleteManagedKeyOptions *GuardedDeleteManagedKeyOptions) (response *core.DetailedResponse, err error) {
+	return uko.GuardedDeleteManagedKeyWithContext(context.Background(), guardedDeleteManagedKeyOptions)
+}
+
+// GuardedDeleteManagedKeyWithContext is an alternate form of the GuardedDeleteManagedKey method which supports a Context parameter
+func (uko *UkoV4) GuardedDeleteManagedKeyWithContext(ctx context.Context, guardedDeleteManagedKeyOptions *GuardedDeleteManagedKeyOptions) (response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(guardedDeleteManagedKeyOptions, "guardedDeleteManagedKeyOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(guardedDeleteManagedKeyOptions, "guardedDeleteManagedKeyOptions")
+	if err != nil {
+		return
+	}
+	options := guardedDeleteManagedKeyOptions
+
+	err = uko.checkManagedKeyDeletionGuard(ctx, "delete", *options.ID, options.Override, options.OverrideReason)
+	if err != nil {
+		return
+	}
+	return uko.DeleteManagedKeyWithContext(ctx, &DeleteManagedKeyOptions{
+		ID:      options.ID,
+		IfMatch: options.IfMatch,
+		Headers: options.Headers,
+	})
+}
+
+// GuardedDeleteKeystore : Delete a keystore that no resource depends on
+// Lists the resources associated with keys in the target keystore and refuses to delete it while any of them is
+// registered, unless the caller explicitly overrides the guard and gives a reason. A blocked call returns an
+// *AssociatedResourcesError and no request is made to delete the keystore.
+func (uko *UkoV4) GuardedDeleteKeystore(guardedDeleteKeystoreOptions *GuardedDeleteKeystoreOptions) (response *core.DetailedResponse, err error) {
+	return uko.GuardedDeleteKeystoreWithContext(context.Background(), guardedDeleteKeystoreOptions)
+}
+
+// GuardedDeleteKeystoreWithContext is an alternate form of the GuardedDeleteKeystore method which supports a Context parameter
+func (uko *UkoV4) GuardedDeleteKeystoreWithContext(ctx context.Context, guardedDeleteKeystoreOptions *GuardedDeleteKeystoreOptions) (response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(guardedDeleteKeystoreOptions, "guardedDeleteKeystoreOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(guardedDeleteKeystoreOptions, "guardedDeleteKeystoreOptions")
+	if err != nil {
+		return
+	}
+	options := guardedDeleteKeystoreOptions
+
+	err = validateGuardOverride(options.Override, options.OverrideReason)
+	if err != nil {
+		return
+	}
+	pager, err := uko.NewAssociatedResourcesForTargetKeystorePager(&ListAssociatedResourcesForTargetKeystoreOptions{ID: options.ID})
+	if err != nil {
+		return
+	}
+	resources, err := pager.GetAllWithContext(ctx)
+	if err != nil {
+		return
+	}
+	err = enforceDeletionGuard("delete", "keystore", *options.ID, resources, options.Override, options.OverrideReason)
+	if err != nil {
+		return
+	}
+	return uko.DeleteKeystoreWithContext(ctx, &DeleteKeystoreOptions{
+		ID:      options.ID,
+		IfMatch: options.IfMatch,
+		Mode:    options.Mode,
+		Headers: options.Headers,
+	})
+}
+
+// checkManagedKeyDeletionGuard returns an error if the managed key must not be destroyed or deleted.
+func (uko *UkoV4) checkManagedKeyDeletionGuard(ctx context.Context, operation string, id string, override *bool, overrideReason *string) (err error) {
+	err = validateGuardOverride(override, overrideReason)
+	if err != nil {
+		return
+	}
+	pager, err := uko.NewAssociatedResourcesForManagedKeyPager(&ListAssociatedResourcesForManagedKeyOptions{ID: core.StringPtr(id)})
+	if err != nil {
+		return
+	}
+	resources, err := pager.GetAllWithContext(ctx)
+	if err != nil {
+		return
+	}
+	return enforceDeletionGuard(operation, "managed key", id, resources, override, overrideReason)
+}
+
+// validateGuardOverride ensures that an override of the deletion guard is always accompanied by a reason.
+func validateGuardOverride(override *bool, overrideReason *string) error {
+	if override != nil && *override && strings.TrimSpace(core.StringNilMapper(overrideReason)) == "" {
+		return fmt.Errorf("a reason must be provided to override the deletion guard")
+	}
+	return nil
+}
+
+// enforceDeletionGuard returns an *AssociatedResourcesError if any of the resources blocks the operation, unless the
+// guard is overridden, in which case the override and its reason are logged.
+func enforceDeletionGuard(operation string, resourceType string, id string, resources []AssociatedResource, override *bool, overrideReason *string) error {
+	var blocking []AssociatedResource
+	for _, resource := range resources {
+		if resource.ComIbmCloudKmsRegistration != nil || (resource.Type != nil && *resource.Type == AssociatedResource_Type_ComIbmCloudKmsRegistration) {
+			blocking = append(blocking, resource)
+		}
+	}
+	if len(blocking) == 0 {
+		return nil
+	}
+	if override != nil && *override {
+		core.GetLogger().Warn("Overriding the deletion guard to %s %s '%s' with %d associated resources: %s",
+			operation, resourceType, id, len(blocking), *overrideReason)
+		return nil
+	}
+	return &AssociatedResourcesError{
+		Operation:    operation,
+		ResourceType: resourceType,
+		ID:           id,
+		Resources:    blocking,
+	}
+}
+
+// Constants associated with the AssociatedResource.Type property.
+// Type of the associated resource, in reverse domain name notation.
+const (
+	AssociatedResource_Type_ComIbmCloudKmsRegistration = "com_ibm_cloud_kms_registration"
+)
+
+// AssociatedResourcesError : The error returned when a guarded operation is blocked by associated resources.
+type AssociatedResourcesError struct {
+	// The blocked operation, such as "destroy" or "delete".
+	Operation string
+
+	// The type of the protected resource, such as "managed key" or "keystore".
+	ResourceType string
+
+	// The v4 UUID of the protected resource.
+	ID string
+
+	// The associated resources that block the operation.
+	Resources []AssociatedResource
+}
+
+// PreventsKeyDeletion returns true if any of the blocking resources is registered with prevents_key_deletion set.
+func (e *AssociatedResourcesError) PreventsKeyDeletion() bool {
+	for _, resource := range e.Resources {
+		registration := resource.ComIbmCloudKmsRegistration
+		if registration != nil && registration.PreventsKeyDeletion != nil && *registration.PreventsKeyDeletion {
+			return true
+		}
+	}
+	return false
+}
+
+// Error implements the error interface.
+func (e *AssociatedResourcesError) Error() string {
+	names := make([]string, 0, len(e.Resources))
+	for _, resource := range e.Resources {
+		name := core.StringNilMapper(resource.Name)
+		if registration := resource.ComIbmCloudKmsRegistration; registration != nil && registration.Crn != nil {
+			name = *registration.Crn
+			if registration.PreventsKeyDeletion != nil && *registration.PreventsKeyDeletion {
+				name += " (prevents key deletion)"
+			}
+		}
+		names = append(names, name)
+	}
+	return fmt.Sprintf("refusing to %s %s '%s': it is used by %d associated resources: %s",
+		e.Operation, e.ResourceType, e.ID, len(e.Resources), strings.Join(names, ", "))
+}
+
+// GuardedDestroyManagedKeyOptions : The GuardedDestroyManagedKey options.
+type GuardedDestroyManagedKeyOptions struct {
+	// UUID of the key.
+	ID *string `json:"id" validate:"required,ne="`
+
+	// Precondition of the update; Value of the ETag from the header on a GET request.
+	IfMatch *string `json:"If-Match" validate:"required"`
+
+	// Destroy the key even if resources are still associated with it.
+	Override *bool `json:"override,omitempty"`
+
+	// Why the guard is overridden. Required when Override is true.
+	OverrideReason *string `json:"override_reason,omitempty"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewGuardedDestroyManagedKeyOptions : Instantiate GuardedDestroyManagedKeyOptions
+func (*UkoV4) NewGuardedDestroyManagedKeyOptions(id string, ifMatch string) *GuardedDestroyManagedKeyOptions {
+	return &GuardedDestroyManagedKeyOptions{
+		ID:      core.StringPtr(id),
+		IfMatch: core.StringPtr(ifMatch),
+	}
+}
+
+// SetID : Allow user to set ID
+func (_options *GuardedDestroyManagedKeyOptions) SetID(id string) *GuardedDestroyManagedKeyOptions {
+	_options.ID = core.StringPtr(id)
+	return _options
+}
+
+// SetIfMatch : Allow user to set IfMatch
+func (_options *GuardedDestroyManagedKeyOptions) SetIfMatch(ifMatch string) *GuardedDestroyManagedKeyOptions {
+	_options.IfMatch = core.StringPtr(ifMatch)
+	return _options
+}
+
+// SetOverride : Override the guard, giving the reason for doing so
+func (_options *GuardedDestroyManagedKeyOptions) SetOverride(reason string) *GuardedDestroyManagedKeyOptions {
+	_options.Override = core.BoolPtr(true)
+	_options.OverrideReason = core.StringPtr(reason)
+	return _options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *GuardedDestroyManagedKeyOptions) SetHeaders(param map[string]string) *GuardedDestroyManagedKeyOptions {
+	options.Headers = param
+	return options
+}
+
+// GuardedDeleteManagedKeyOptions : The GuardedDeleteManagedKey options.
+type GuardedDeleteManagedKeyOptions struct {
+	// UUID of the key.
+	ID *string `json:"id" validate:"required,ne="`
+
+	// Precondition of the update; Value of the ETag from the header on a GET request.
+	IfMatch *string `json:"If-Match" validate:"required"`
+
+	// Delete the key even if resources are still associated with it.
+	Override *bool `json:"override,omitempty"`
+
+	// Why the guard is overridden. Required when Override is true.
+	OverrideReason *string `json:"override_reason,omitempty"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewGuardedDeleteManagedKeyOptions : Instantiate GuardedDeleteManagedKeyOptions
+func (*UkoV4) NewGuardedDeleteManagedKeyOptions(id string, ifMatch string) *GuardedDeleteManagedKeyOptions {
+	return &GuardedDeleteManagedKeyOptions{
+		ID:      core.StringPtr(id),
+		IfMatch: core.StringPtr(ifMatch),
+	}
+}
+
+// SetID : Allow user to set ID
+func (_options *GuardedDeleteManagedKeyOptions) SetID(id string) *GuardedDeleteManagedKeyOptions {
+	_options.ID = core.StringPtr(id)
+	return _options
+}
+
+// SetIfMatch : Allow user to set IfMatch
+func (_options *GuardedDeleteManagedKeyOptions) SetIfMatch(ifMatch string) *GuardedDeleteManagedKeyOptions {
+	_options.IfMatch = core.StringPtr(ifMatch)
+	return _options
+}
+
+// SetOverride : Override the guard, giving the reason for doing so
+func (_options *GuardedDeleteManagedKeyOptions) SetOverride(reason string) *GuardedDeleteManagedKeyOptions {
+	_options.Override = core.BoolPtr(true)
+	_options.OverrideReason = core.StringPtr(reason)
+	return _options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *GuardedDeleteManagedKeyOptions) SetHeaders(param map[string]string) *GuardedDeleteManagedKeyOptions {
+	options.Headers = param
+	return options
+}
+
+// GuardedDeleteKeystoreOptions : The GuardedDeleteKeystore options.
+type GuardedDeleteKeystoreOptions struct {
+	// UUID of the keystore.
+	ID *string `json:"id" validate:"required,ne="`
+
+	// Precondition of the update; Value of the ETag from the header on a GET request.
+	IfMatch *string `json:"If-Match" validate:"required"`
+
+	// Mode of disconnecting from keystore.
+	Mode *string `json:"mode,omitempty"`
+
+	// Delete the keystore even if resources are still associated with its keys.
+	Override *bool `json:"override,omitempty"`
+
+	// Why the guard is overridden. Required when Override is true.
+	OverrideReason *string `json:"override_reason,omitempty"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewGuardedDeleteKeystoreOptions : Instantiate GuardedDeleteKeystoreOptions
+func (*UkoV4) NewGuardedDeleteKeystoreOptions(id string, ifMatch string) *GuardedDeleteKeystoreOptions {
+	return &GuardedDeleteKeystoreOptions{
+		ID:      core.StringPtr(id),
+		IfMatch: core.StringPtr(ifMatch),
+	}
+}
+
+// SetID : Allow user to set ID
+func (_options *GuardedDeleteKeystoreOptions) SetID(id string) *GuardedDeleteKeystoreOptions {
+	_options.ID = core.StringPtr(id)
+	return _options
+}
+
+// SetIfMatch : Allow user to set IfMatch
+func (_options *GuardedDeleteKeystoreOptions) SetIfMatch(ifMatch string) *GuardedDeleteKeystoreOptions {
+	_options.IfMatch = core.StringPtr(ifMatch)
+	return _options
+}
+
+// SetMode : Allow user to set Mode
+func (_options *GuardedDeleteKeystoreOptions) SetMode(mode string) *GuardedDeleteKeystoreOptions {
+	_options.Mode = core.StringPtr(mode)
+	return _options
+}
+
+// SetOverride : Override the guard, giving the reason for doing so
+func (_options *GuardedDeleteKeystoreOptions) SetOverride(reason string) *GuardedDeleteKeystoreOptions {
+	_options.Override = core.BoolPtr(true)
+	_options.OverrideReason = core.StringPtr(reason)
+	return _options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *GuardedDeleteKeystoreOptions) SetHeaders(param map[string]string) *GuardedDeleteKeystoreOptions {
+	options.Headers = param
+	return options
+}
@@ -0,0 +1,129 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-hpcs-uko-sdk/ukov4"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const mockRegisteredResources = `{"total_count": 1, "limit": 1, "offset": 0, "associated_resources": [{"id": "r1", "key_id_in_keystore": "kid", "name": "bucket", "type": "com_ibm_cloud_kms_registration", "com_ibm_cloud_kms_registration": {"prevents_key_deletion": true, "service_name": "cloud-object-storage", "service_instance_name": "cos-prod", "crn": "crn:v1:bluemix:public:cloud-object-storage:global:a/1::bucket:prod", "description": "prod bucket"}}]}`
+
+const mockNoResources = `{"total_count": 0, "limit": 10, "offset": 0, "associated_resources": []}`
+
+var _ = Describe(`Deletion guard`, func() {
+	var testServer *httptest.Server
+	var associatedResources string
+	var deleted []string
+
+	BeforeEach(func() {
+		associatedResources = mockRegisteredResources
+		deleted = nil
+		testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+
+			res.Header().Set("Content-type", "application/json")
+			switch req.Method + " " + req.URL.EscapedPath() {
+			case "GET /api/v4/managed_keys/k1/associated_resources", "GET /api/v4/keystores/ks1/associated_resources":
+				res.WriteHeader(200)
+				fmt.Fprint(res, associatedResources)
+			case "POST /api/v4/managed_keys/k1/destroy":
+				deleted = append(deleted, "destroy k1")
+				res.WriteHeader(200)
+				fmt.Fprint(res, mockVersionedKey("k1", 1, "destroyed"))
+			case "DELETE /api/v4/managed_keys/k1":
+				deleted = append(deleted, "delete k1")
+				res.WriteHeader(204)
+			case "DELETE /api/v4/keystores/ks1":
+				Expect(req.URL.Query().Get("mode")).To(Equal("disconnect"))
+				deleted = append(deleted, "delete ks1")
+				res.WriteHeader(204)
+			default:
+				Fail(fmt.Sprintf("unexpected request %s %s", req.Method, req.URL.EscapedPath()))
+			}
+		}))
+	})
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	newService := func() *ukov4.UkoV4 {
+		ukoService, serviceErr := ukov4.NewUkoV4(&ukov4.UkoV4Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+		return ukoService
+	}
+
+	It(`Invoke guarded operations with nil options (negative test)`, func() {
+		ukoService := newService()
+		_, _, err := ukoService.GuardedDestroyManagedKey(nil)
+		Expect(err).ToNot(BeNil())
+		_, err = ukoService.GuardedDeleteManagedKey(nil)
+		Expect(err).ToNot(BeNil())
+		_, err = ukoService.GuardedDeleteKeystore(nil)
+		Expect(err).ToNot(BeNil())
+	})
+	It(`Block destroying a managed key with registered resources`, func() {
+		ukoService := newService()
+		result, response, err := ukoService.GuardedDestroyManagedKey(ukoService.NewGuardedDestroyManagedKeyOptions("k1", "etag"))
+		Expect(result).To(BeNil())
+		Expect(response).To(BeNil())
+		guardErr, ok := err.(*ukov4.AssociatedResourcesError)
+		Expect(ok).To(BeTrue())
+		Expect(guardErr.PreventsKeyDeletion()).To(BeTrue())
+		Expect(guardErr.Error()).To(ContainSubstring("crn:v1:bluemix:public:cloud-object-storage:global:a/1::bucket:prod"))
+		Expect(deleted).To(BeEmpty())
+	})
+	It(`Require a reason to override the guard`, func() {
+		ukoService := newService()
+		options := ukoService.NewGuardedDeleteManagedKeyOptions("k1", "etag")
+		options.Override = core.BoolPtr(true)
+		_, err := ukoService.GuardedDeleteManagedKey(options)
+		Expect(err).ToNot(BeNil())
+		Expect(deleted).To(BeEmpty())
+
+		_, err = ukoService.GuardedDeleteManagedKey(options.SetOverride("bucket was emptied and decommissioned"))
+		Expect(err).To(BeNil())
+		Expect(deleted).To(Equal([]string{"delete k1"}))
+	})
+	It(`Allow destructive calls when no resources are associated`, func() {
+		ukoService := newService()
+		associatedResources = mockNoResources
+
+		result, _, err := ukoService.GuardedDestroyManagedKey(ukoService.NewGuardedDestroyManagedKeyOptions("k1", "etag"))
+		Expect(err).To(BeNil())
+		Expect(*result.State).To(Equal("destroyed"))
+
+		_, err = ukoService.GuardedDeleteKeystore(ukoService.NewGuardedDeleteKeystoreOptions("ks1", "etag").SetMode("disconnect"))
+		Expect(err).To(BeNil())
+		Expect(deleted).To(Equal([]string{"destroy k1", "delete ks1"}))
+	})
+	It(`Block deleting a keystore with registered resources`, func() {
+		ukoService := newService()
+		_, err := ukoService.GuardedDeleteKeystore(ukoService.NewGuardedDeleteKeystoreOptions("ks1", "etag"))
+		Expect(err).To(BeAssignableToTypeOf(&ukov4.AssociatedResourcesError{}))
+		Expect(deleted).To(BeEmpty())
+	})
+})
@@ -0,0 +1,876 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// UnknownEnumValueError : The error returned when parsing a value that is not one of the known values of an enum type.
+// The enum types themselves accept any value, so that values added by newer versions of the service are preserved when
+// unmarshalled; their Valid method reports whether a value is known.
+type UnknownEnumValueError struct {
+	// The name of the enum type.
+	Type string
+
+	// The unknown value.
+	Value string
+}
+
+// Error implements the error interface.
+func (e *UnknownEnumValueError) Error() string {
+	return fmt.Sprintf("unknown %s '%s'", e.Type, e.Value)
+}
+
+// KeyState : The state of a managed key.
+type KeyState string
+
+// Constants for the known values of KeyState.
+const (
+	KeyState_Active               KeyState = ManagedKey_State_Active
+	KeyState_Compromised          KeyState = ManagedKey_State_Compromised
+	KeyState_Deactivated          KeyState = ManagedKey_State_Deactivated
+	KeyState_Destroyed            KeyState = ManagedKey_State_Destroyed
+	KeyState_DestroyedCompromised KeyState = ManagedKey_State_DestroyedCompromised
+	KeyState_PreActivation        KeyState = ManagedKey_State_PreActivation
+)
+
+// KeyStates returns the known values of KeyState.
+func KeyStates() []KeyState {
+	return []KeyState{
+		KeyState_Active,
+		KeyState_Compromised,
+		KeyState_Deactivated,
+		KeyState_Destroyed,
+		KeyState_DestroyedCompromised,
+		KeyState_PreActivation,
+	}
+}
+
+// ParseKeyState returns the specified value as a KeyState. If it is not a known value, it is returned together with an
+// *UnknownEnumValueError.
+func ParseKeyState(value string) (KeyState, error) {
+	result := KeyState(value)
+	if !result.Valid() {
+		return result, &UnknownEnumValueError{Type: "KeyState", Value: value}
+	}
+	return result, nil
+}
+
+// String returns the value as a string.
+func (value KeyState) String() string {
+	return string(value)
+}
+
+// Ptr returns a pointer to the value as a string, for use in the models and options.
+func (value KeyState) Ptr() *string {
+	s := string(value)
+	return &s
+}
+
+// Valid returns true if the value is one of the known values of KeyState.
+func (value KeyState) Valid() bool {
+	for _, known := range KeyStates() {
+		if value == known {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON implements json.Marshaler.
+func (value KeyState) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(value))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Unknown values are preserved.
+func (value *KeyState) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*value = KeyState(s)
+	return nil
+}
+
+// GetState returns the State property as a KeyState, or "" if it is not set.
+func (managedKey *ManagedKey) GetState() KeyState {
+	if managedKey.State == nil {
+		return ""
+	}
+	return KeyState(*managedKey.State)
+}
+
+// KeyAlgorithm : The algorithm of a managed key.
+type KeyAlgorithm string
+
+// Constants for the known values of KeyAlgorithm.
+const (
+	KeyAlgorithm_Aes       KeyAlgorithm = ManagedKey_Algorithm_Aes
+	KeyAlgorithm_Des       KeyAlgorithm = ManagedKey_Algorithm_Des
+	KeyAlgorithm_Dilithium KeyAlgorithm = ManagedKey_Algorithm_Dilithium
+	KeyAlgorithm_Ec        KeyAlgorithm = ManagedKey_Algorithm_Ec
+	KeyAlgorithm_Hmac      KeyAlgorithm = ManagedKey_Algorithm_Hmac
+	KeyAlgorithm_Rsa       KeyAlgorithm = ManagedKey_Algorithm_Rsa
+)
+
+// KeyAlgorithms returns the known values of KeyAlgorithm.
+func KeyAlgorithms() []KeyAlgorithm {
+	return []KeyAlgorithm{
+		KeyAlgorithm_Aes,
+		KeyAlgorithm_Des,
+		KeyAlgorithm_Dilithium,
+		KeyAlgorithm_Ec,
+		KeyAlgorithm_Hmac,
+		KeyAlgorithm_Rsa,
+	}
+}
+
+// ParseKeyAlgorithm returns the specified value as a KeyAlgorithm. If it is not a known value, it is returned together
+// with an *UnknownEnumValueError.
+func ParseKeyAlgorithm(value string) (KeyAlgorithm, error) {
+	result := KeyAlgorithm(value)
+	if !result.Valid() {
+		return result, &UnknownEnumValueError{Type: "KeyAlgorithm", Value: value}
+	}
+	return result, nil
+}
+
+// String returns the value as a string.
+func (value KeyAlgorithm) String() string {
+	return string(value)
+}
+
+// Ptr returns a pointer to the value as a string, for use in the models and options.
+func (value KeyAlgorithm) Ptr() *string {
+	s := string(value)
+	return &s
+}
+
+// Valid returns true if the value is one of the known values of KeyAlgorithm.
+func (value KeyAlgorithm) Valid() bool {
+	for _, known := range KeyAlgorithms() {
+		if value == known {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON implements json.Marshaler.
+func (value KeyAlgorithm) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(value))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Unknown values are preserved.
+func (value *KeyAlgorithm) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*value = KeyAlgorithm(s)
+	return nil
+}
+
+// GetAlgorithm returns the Algorithm property as a KeyAlgorithm, or "" if it is not set.
+func (managedKey *ManagedKey) GetAlgorithm() KeyAlgorithm {
+	if managedKey.Algorithm == nil {
+		return ""
+	}
+	return KeyAlgorithm(*managedKey.Algorithm)
+}
+
+// KeystoreType : The type of a keystore.
+type KeystoreType string
+
+// Constants for the known values of KeystoreType.
+const (
+	KeystoreType_AwsKms        KeystoreType = Keystore_Type_AwsKms
+	KeystoreType_AzureKeyVault KeystoreType = Keystore_Type_AzureKeyVault
+	KeystoreType_Cca           KeystoreType = Keystore_Type_Cca
+	KeystoreType_GoogleKms     KeystoreType = Keystore_Type_GoogleKms
+	KeystoreType_IbmCloudKms   KeystoreType = Keystore_Type_IbmCloudKms
+)
+
+// KeystoreTypes returns the known values of KeystoreType.
+func KeystoreTypes() []KeystoreType {
+	return []KeystoreType{
+		KeystoreType_AwsKms,
+		KeystoreType_AzureKeyVault,
+		KeystoreType_Cca,
+		KeystoreType_GoogleKms,
+		KeystoreType_IbmCloudKms,
+	}
+}
+
+// ParseKeystoreType returns the specified value as a KeystoreType. If it is not a known value, it is returned together
+// with an *UnknownEnumValueError.
+func ParseKeystoreType(value string) (KeystoreType, error) {
+	result := KeystoreType(value)
+	if !result.Valid() {
+		return result, &UnknownEnumValueError{Type: "KeystoreType", Value: value}
+	}
+	return result, nil
+}
+
+// String returns the value as a string.
+func (value KeystoreType) String() string {
+	return string(value)
+}
+
+// Ptr returns a pointer to the value as a string, for use in the models and options.
+func (value KeystoreType) Ptr() *string {
+	s := string(value)
+	return &s
+}
+
+// Valid returns true if the value is one of the known values of KeystoreType.
+func (value KeystoreType) Valid() bool {
+	for _, known := range KeystoreTypes() {
+		if value == known {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON implements json.Marshaler.
+func (value KeystoreType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(value))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Unknown values are preserved.
+func (value *KeystoreType) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*value = KeystoreType(s)
+	return nil
+}
+
+// GetType returns the Type property as a KeystoreType, or "" if it is not set.
+func (keystore *Keystore) GetType() KeystoreType {
+	if keystore.Type == nil {
+		return ""
+	}
+	return KeystoreType(*keystore.Type)
+}
+
+// GetType returns the Type property as a KeystoreType, or "" if it is not set.
+func (keystore *KeystoreTypeAwsKms) GetType() KeystoreType {
+	if keystore.Type == nil {
+		return ""
+	}
+	return KeystoreType(*keystore.Type)
+}
+
+// GetType returns the Type property as a KeystoreType, or "" if it is not set.
+func (keystore *KeystoreTypeAzure) GetType() KeystoreType {
+	if keystore.Type == nil {
+		return ""
+	}
+	return KeystoreType(*keystore.Type)
+}
+
+// GetType returns the Type property as a KeystoreType, or "" if it is not set.
+func (keystore *KeystoreTypeCca) GetType() KeystoreType {
+	if keystore.Type == nil {
+		return ""
+	}
+	return KeystoreType(*keystore.Type)
+}
+
+// GetType returns the Type property as a KeystoreType, or "" if it is not set.
+func (keystore *KeystoreTypeGoogleKms) GetType() KeystoreType {
+	if keystore.Type == nil {
+		return ""
+	}
+	return KeystoreType(*keystore.Type)
+}
+
+// GetType returns the Type property as a KeystoreType, or "" if it is not set.
+func (keystore *KeystoreTypeIbmCloudKms) GetType() KeystoreType {
+	if keystore.Type == nil {
+		return ""
+	}
+	return KeystoreType(*keystore.Type)
+}
+
+// KeystoreHealthStatus : The health status of a keystore.
+type KeystoreHealthStatus string
+
+// Constants for the known values of KeystoreHealthStatus.
+const (
+	KeystoreHealthStatus_ConfigurationError KeystoreHealthStatus = KeystoreStatus_HealthStatus_ConfigurationError
+	KeystoreHealthStatus_NotResponding      KeystoreHealthStatus = KeystoreStatus_HealthStatus_NotResponding
+	KeystoreHealthStatus_Ok                 KeystoreHealthStatus = KeystoreStatus_HealthStatus_Ok
+)
+
+// KeystoreHealthStatuses returns the known values of KeystoreHealthStatus.
+func KeystoreHealthStatuses() []KeystoreHealthStatus {
+	return []KeystoreHealthStatus{
+		KeystoreHealthStatus_ConfigurationError,
+		KeystoreHealthStatus_NotResponding,
+		KeystoreHealthStatus_Ok,
+	}
+}
+
+// ParseKeystoreHealthStatus returns the specified value as a KeystoreHealthStatus. If it is not a known value, it is
+// returned together with an *UnknownEnumValueError.
+func ParseKeystoreHealthStatus(value string) (KeystoreHealthStatus, error) {
+	result := KeystoreHealthStatus(value)
+	if !result.Valid() {
+		return result, &UnknownEnumValueError{Type: "KeystoreHealthStatus", Value: value}
+	}
+	return result, nil
+}
+
+// String returns the value as a string.
+func (value KeystoreHealthStatus) String() string {
+	return string(value)
+}
+
+// Ptr returns a pointer to the value as a string, for use in the models and options.
+func (value KeystoreHealthStatus) Ptr() *string {
+	s := string(value)
+	return &s
+}
+
+// Valid returns true if the value is one of the known values of KeystoreHealthStatus.
+func (value KeystoreHealthStatus) Valid() bool {
+	for _, known := range KeystoreHealthStatuses() {
+		if value == known {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON implements json.Marshaler.
+func (value KeystoreHealthStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(value))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Unknown values are preserved.
+func (value *KeystoreHealthStatus) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*value = KeystoreHealthStatus(s)
+	return nil
+}
+
+// GetHealthStatus returns the HealthStatus property as a KeystoreHealthStatus, or "" if it is not set.
+func (keystoreStatus *KeystoreStatus) GetHealthStatus() KeystoreHealthStatus {
+	if keystoreStatus.HealthStatus == nil {
+		return ""
+	}
+	return KeystoreHealthStatus(*keystoreStatus.HealthStatus)
+}
+
+// KeystoreSyncFlag : The synchronization status between a managed key and a target keystore.
+type KeystoreSyncFlag string
+
+// Constants for the known values of KeystoreSyncFlag.
+const (
+	KeystoreSyncFlag_Error     KeystoreSyncFlag = StatusInKeystore_KeystoreSyncFlag_Error
+	KeystoreSyncFlag_Ok        KeystoreSyncFlag = StatusInKeystore_KeystoreSyncFlag_Ok
+	KeystoreSyncFlag_OutOfSync KeystoreSyncFlag = StatusInKeystore_KeystoreSyncFlag_OutOfSync
+)
+
+// KeystoreSyncFlags returns the known values of KeystoreSyncFlag.
+func KeystoreSyncFlags() []KeystoreSyncFlag {
+	return []KeystoreSyncFlag{
+		KeystoreSyncFlag_Error,
+		KeystoreSyncFlag_Ok,
+		KeystoreSyncFlag_OutOfSync,
+	}
+}
+
+// ParseKeystoreSyncFlag returns the specified value as a KeystoreSyncFlag. If it is not a known value, it is returned
+// together with an *UnknownEnumValueError.
+func ParseKeystoreSyncFlag(value string) (KeystoreSyncFlag, error) {
+	result := KeystoreSyncFlag(value)
+	if !result.Valid() {
+		return result, &UnknownEnumValueError{Type: "KeystoreSyncFlag", Value: value}
+	}
+	return result, nil
+}
+
+// String returns the value as a string.
+func (value KeystoreSyncFlag) String() string {
+	return string(value)
+}
+
+// Ptr returns a pointer to the value as a string, for use in the models and options.
+func (value KeystoreSyncFlag) Ptr() *string {
+	s := string(value)
+	return &s
+}
+
+// Valid returns true if the value is one of the known values of KeystoreSyncFlag.
+func (value KeystoreSyncFlag) Valid() bool {
+	for _, known := range KeystoreSyncFlags() {
+		if value == known {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON implements json.Marshaler.
+func (value KeystoreSyncFlag) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(value))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Unknown values are preserved.
+func (value *KeystoreSyncFlag) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*value = KeystoreSyncFlag(s)
+	return nil
+}
+
+// GetKeystoreSyncFlag returns the KeystoreSyncFlag property as a KeystoreSyncFlag, or "" if it is not set.
+func (statusInKeystore *StatusInKeystore) GetKeystoreSyncFlag() KeystoreSyncFlag {
+	if statusInKeystore.KeystoreSyncFlag == nil {
+		return ""
+	}
+	return KeystoreSyncFlag(*statusInKeystore.KeystoreSyncFlag)
+}
+
+// KeystoreSyncFlagDetail : The detailed description of the mismatch between a managed key and a target keystore.
+type KeystoreSyncFlagDetail string
+
+// Constants for the known values of KeystoreSyncFlagDetail.
+const (
+	KeystoreSyncFlagDetail_ActiveKeyIsActiveInKeystore                    KeystoreSyncFlagDetail = StatusInKeystore_KeystoreSyncFlagDetail_ActiveKeyIsActiveInKeystore
+	KeystoreSyncFlagDetail_ActiveKeyIsNotActiveInKeystore                 KeystoreSyncFlagDetail = StatusInKeystore_KeystoreSyncFlagDetail_ActiveKeyIsNotActiveInKeystore
+	KeystoreSyncFlagDetail_ConnectionError                                KeystoreSyncFlagDetail = StatusInKeystore_KeystoreSyncFlagDetail_ConnectionError
+	KeystoreSyncFlagDetail_DeactivatedKeyIsDeactivatedInKeystore          KeystoreSyncFlagDetail = StatusInKeystore_KeystoreSyncFlagDetail_DeactivatedKeyIsDeactivatedInKeystore
+	KeystoreSyncFlagDetail_DeactivatedKeyIsNotDeactivatedInKeystore       KeystoreSyncFlagDetail = StatusInKeystore_KeystoreSyncFlagDetail_DeactivatedKeyIsNotDeactivatedInKeystore
+	KeystoreSyncFlagDetail_DestroyedKeyIsNotPresentInKeystore             KeystoreSyncFlagDetail = StatusInKeystore_KeystoreSyncFlagDetail_DestroyedKeyIsNotPresentInKeystore
+	KeystoreSyncFlagDetail_DestroyedKeyIsPresentInKeystore                KeystoreSyncFlagDetail = StatusInKeystore_KeystoreSyncFlagDetail_DestroyedKeyIsPresentInKeystore
+	KeystoreSyncFlagDetail_PreActiveKeyIsNotPresentInKeystore             KeystoreSyncFlagDetail = StatusInKeystore_KeystoreSyncFlagDetail_PreActiveKeyIsNotPresentInKeystore
+	KeystoreSyncFlagDetail_PreActiveKeyIsPresentInKeystore                KeystoreSyncFlagDetail = StatusInKeystore_KeystoreSyncFlagDetail_PreActiveKeyIsPresentInKeystore
+	KeystoreSyncFlagDetail_TargetKeystoreRemovedByUser                    KeystoreSyncFlagDetail = StatusInKeystore_KeystoreSyncFlagDetail_TargetKeystoreRemovedByUser
+	KeystoreSyncFlagDetail_TargetKeystoreRemovedByUserContainsAnActiveKey KeystoreSyncFlagDetail = StatusInKeystore_KeystoreSyncFlagDetail_TargetKeystoreRemovedByUserContainsAnActiveKey
+)
+
+// KeystoreSyncFlagDetails returns the known values of KeystoreSyncFlagDetail.
+func KeystoreSyncFlagDetails() []KeystoreSyncFlagDetail {
+	return []KeystoreSyncFlagDetail{
+		KeystoreSyncFlagDetail_ActiveKeyIsActiveInKeystore,
+		KeystoreSyncFlagDetail_ActiveKeyIsNotActiveInKeystore,
+		KeystoreSyncFlagDetail_ConnectionError,
+		KeystoreSyncFlagDetail_DeactivatedKeyIsDeactivatedInKeystore,
+		KeystoreSyncFlagDetail_DeactivatedKeyIsNotDeactivatedInKeystore,
+		KeystoreSyncFlagDetail_DestroyedKeyIsNotPresentInKeystore,
+		KeystoreSyncFlagDetail_DestroyedKeyIsPresentInKeystore,
+		KeystoreSyncFlagDetail_PreActiveKeyIsNotPresentInKeystore,
+		KeystoreSyncFlagDetail_PreActiveKeyIsPresentInKeystore,
+		KeystoreSyncFlagDetail_TargetKeystoreRemovedByUser,
+		KeystoreSyncFlagDetail_TargetKeystoreRemovedByUserContainsAnActiveKey,
+	}
+}
+
+// ParseKeystoreSyncFlagDetail returns the specified value as a KeystoreSyncFlagDetail. If it is not a known value, it
+// is returned together with an *UnknownEnumValueError.
+func ParseKeystoreSyncFlagDetail(value string) (KeystoreSyncFlagDetail, error) {
+	result := KeystoreSyncFlagDetail(value)
+	if !result.Valid() {
+		return result, &UnknownEnumValueError{Type: "KeystoreSyncFlagDetail", Value: value}
+	}
+	return result, nil
+}
+
+// String returns the value as a string.
+func (value KeystoreSyncFlagDetail) String() string {
+	return string(value)
+}
+
+// Ptr returns a pointer to the value as a string, for use in the models and options.
+func (value KeystoreSyncFlagDetail) Ptr() *string {
+	s := string(value)
+	return &s
+}
+
+// Valid returns true if the value is one of the known values of KeystoreSyncFlagDetail.
+func (value KeystoreSyncFlagDetail) Valid() bool {
+	for _, known := range KeystoreSyncFlagDetails() {
+		if value == known {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON implements json.Marshaler.
+func (value KeystoreSyncFlagDetail) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(value))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Unknown values are preserved.
+func (value *KeystoreSyncFlagDetail) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*value = KeystoreSyncFlagDetail(s)
+	return nil
+}
+
+// GetKeystoreSyncFlagDetail returns the KeystoreSyncFlagDetail property as a KeystoreSyncFlagDetail, or "" if it is not
+// set.
+func (statusInKeystore *StatusInKeystore) GetKeystoreSyncFlagDetail() KeystoreSyncFlagDetail {
+	if statusInKeystore.KeystoreSyncFlagDetail == nil {
+		return ""
+	}
+	return KeystoreSyncFlagDetail(*statusInKeystore.KeystoreSyncFlagDetail)
+}
+
+// AzureLocation : The location of an Azure Key Vault.
+type AzureLocation string
+
+// Constants for the known values of AzureLocation.
+const (
+	AzureLocation_AsiaEast           AzureLocation = Keystore_AzureLocation_AsiaEast
+	AzureLocation_AsiaSoutheast      AzureLocation = Keystore_AzureLocation_AsiaSoutheast
+	AzureLocation_AustraliaCentral   AzureLocation = Keystore_AzureLocation_AustraliaCentral
+	AzureLocation_AustraliaCentral2  AzureLocation = Keystore_AzureLocation_AustraliaCentral2
+	AzureLocation_AustraliaEast      AzureLocation = Keystore_AzureLocation_AustraliaEast
+	AzureLocation_AustraliaSoutheast AzureLocation = Keystore_AzureLocation_AustraliaSoutheast
+	AzureLocation_BrazilSouth        AzureLocation = Keystore_AzureLocation_BrazilSouth
+	AzureLocation_CanadaCentral      AzureLocation = Keystore_AzureLocation_CanadaCentral
+	AzureLocation_CanadaEast         AzureLocation = Keystore_AzureLocation_CanadaEast
+	AzureLocation_ChinaEast          AzureLocation = Keystore_AzureLocation_ChinaEast
+	AzureLocation_ChinaEast2         AzureLocation = Keystore_AzureLocation_ChinaEast2
+	AzureLocation_ChinaNorth         AzureLocation = Keystore_AzureLocation_ChinaNorth
+	AzureLocation_ChinaNorth2        AzureLocation = Keystore_AzureLocation_ChinaNorth2
+	AzureLocation_EuropeNorth        AzureLocation = Keystore_AzureLocation_EuropeNorth
+	AzureLocation_EuropeWest         AzureLocation = Keystore_AzureLocation_EuropeWest
+	AzureLocation_FranceCentral      AzureLocation = Keystore_AzureLocation_FranceCentral
+	AzureLocation_FranceSouth        AzureLocation = Keystore_AzureLocation_FranceSouth
+	AzureLocation_GermanyCentral     AzureLocation = Keystore_AzureLocation_GermanyCentral
+	AzureLocation_GermanyNortheast   AzureLocation = Keystore_AzureLocation_GermanyNortheast
+	AzureLocation_IndiaCentral       AzureLocation = Keystore_AzureLocation_IndiaCentral
+	AzureLocation_IndiaSouth         AzureLocation = Keystore_AzureLocation_IndiaSouth
+	AzureLocation_IndiaWest          AzureLocation = Keystore_AzureLocation_IndiaWest
+	AzureLocation_JapanEast          AzureLocation = Keystore_AzureLocation_JapanEast
+	AzureLocation_JapanWest          AzureLocation = Keystore_AzureLocation_JapanWest
+	AzureLocation_KoreaCentral       AzureLocation = Keystore_AzureLocation_KoreaCentral
+	AzureLocation_KoreaSouth         AzureLocation = Keystore_AzureLocation_KoreaSouth
+	AzureLocation_SouthAfricaNorth   AzureLocation = Keystore_AzureLocation_SouthAfricaNorth
+	AzureLocation_SouthAfricaWest    AzureLocation = Keystore_AzureLocation_SouthAfricaWest
+	AzureLocation_UkSouth            AzureLocation = Keystore_AzureLocation_UkSouth
+	AzureLocation_UkWest             AzureLocation = Keystore_AzureLocation_UkWest
+	AzureLocation_UsCentral          AzureLocation = Keystore_AzureLocation_UsCentral
+	AzureLocation_UsDodCentral       AzureLocation = Keystore_AzureLocation_UsDodCentral
+	AzureLocation_UsDodEast          AzureLocation = Keystore_AzureLocation_UsDodEast
+	AzureLocation_UsEast             AzureLocation = Keystore_AzureLocation_UsEast
+	AzureLocation_UsEast2            AzureLocation = Keystore_AzureLocation_UsEast2
+	AzureLocation_UsGovArizona       AzureLocation = Keystore_AzureLocation_UsGovArizona
+	AzureLocation_UsGovIowa          AzureLocation = Keystore_AzureLocation_UsGovIowa
+	AzureLocation_UsGovTexas         AzureLocation = Keystore_AzureLocation_UsGovTexas
+	AzureLocation_UsGovVirginia      AzureLocation = Keystore_AzureLocation_UsGovVirginia
+	AzureLocation_UsNorthCentral     AzureLocation = Keystore_AzureLocation_UsNorthCentral
+	AzureLocation_UsSouthCentral     AzureLocation = Keystore_AzureLocation_UsSouthCentral
+	AzureLocation_UsWest             AzureLocation = Keystore_AzureLocation_UsWest
+	AzureLocation_UsWest2            AzureLocation = Keystore_AzureLocation_UsWest2
+	AzureLocation_UsWestCentral      AzureLocation = Keystore_AzureLocation_UsWestCentral
+)
+
+// AzureLocations returns the known values of AzureLocation.
+func AzureLocations() []AzureLocation {
+	return []AzureLocation{
+		AzureLocation_AsiaEast,
+		AzureLocation_AsiaSoutheast,
+		AzureLocation_AustraliaCentral,
+		AzureLocation_AustraliaCentral2,
+		AzureLocation_AustraliaEast,
+		AzureLocation_AustraliaSoutheast,
+		AzureLocation_BrazilSouth,
+		AzureLocation_CanadaCentral,
+		AzureLocation_CanadaEast,
+		AzureLocation_ChinaEast,
+		AzureLocation_ChinaEast2,
+		AzureLocation_ChinaNorth,
+		AzureLocation_ChinaNorth2,
+		AzureLocation_EuropeNorth,
+		AzureLocation_EuropeWest,
+		AzureLocation_FranceCentral,
+		AzureLocation_FranceSouth,
+		AzureLocation_GermanyCentral,
+		AzureLocation_GermanyNortheast,
+		AzureLocation_IndiaCentral,
+		AzureLocation_IndiaSouth,
+		AzureLocation_IndiaWest,
+		AzureLocation_JapanEast,
+		AzureLocation_JapanWest,
+		AzureLocation_KoreaCentral,
+		AzureLocation_KoreaSouth,
+		AzureLocation_SouthAfricaNorth,
+		AzureLocation_SouthAfricaWest,
+		AzureLocation_UkSouth,
+		AzureLocation_UkWest,
+		AzureLocation_UsCentral,
+		AzureLocation_UsDodCentral,
+		AzureLocation_UsDodEast,
+		AzureLocation_UsEast,
+		AzureLocation_UsEast2,
+		AzureLocation_UsGovArizona,
+		AzureLocation_UsGovIowa,
+		AzureLocation_UsGovTexas,
+		AzureLocation_UsGovVirginia,
+		AzureLocation_UsNorthCentral,
+		AzureLocation_UsSouthCentral,
+		AzureLocation_UsWest,
+		AzureLocation_UsWest2,
+		AzureLocation_UsWestCentral,
+	}
+}
+
+// ParseAzureLocation returns the specified value as an AzureLocation. If it is not a known value, it is returned
+// together with an *UnknownEnumValueError.
+func ParseAzureLocation(value string) (AzureLocation, error) {
+	result := AzureLocation(value)
+	if !result.Valid() {
+		return result, &UnknownEnumValueError{Type: "AzureLocation", Value: value}
+	}
+	return result, nil
+}
+
+// String returns the value as a string.
+func (value AzureLocation) String() string {
+	return string(value)
+}
+
+// Ptr returns a pointer to the value as a string, for use in the models and options.
+func (value AzureLocation) Ptr() *string {
+	s := string(value)
+	return &s
+}
+
+// Valid returns true if the value is one of the known values of AzureLocation.
+func (value AzureLocation) Valid() bool {
+	for _, known := range AzureLocations() {
+		if value == known {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON implements json.Marshaler.
+func (value AzureLocation) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(value))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Unknown values are preserved.
+func (value *AzureLocation) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*value = AzureLocation(s)
+	return nil
+}
+
+// GetAzureLocation returns the AzureLocation property as an AzureLocation, or "" if it is not set.
+func (keystore *Keystore) GetAzureLocation() AzureLocation {
+	if keystore.AzureLocation == nil {
+		return ""
+	}
+	return AzureLocation(*keystore.AzureLocation)
+}
+
+// GetAzureLocation returns the AzureLocation property as an AzureLocation, or "" if it is not set.
+func (keystore *KeystoreTypeAzure) GetAzureLocation() AzureLocation {
+	if keystore.AzureLocation == nil {
+		return ""
+	}
+	return AzureLocation(*keystore.AzureLocation)
+}
+
+// AzureEnvironment : The Azure environment of an Azure Key Vault.
+type AzureEnvironment string
+
+// Constants for the known values of AzureEnvironment.
+const (
+	AzureEnvironment_Azure             AzureEnvironment = Keystore_AzureEnvironment_Azure
+	AzureEnvironment_AzureChina        AzureEnvironment = Keystore_AzureEnvironment_AzureChina
+	AzureEnvironment_AzureGermany      AzureEnvironment = Keystore_AzureEnvironment_AzureGermany
+	AzureEnvironment_AzureUsGovernment AzureEnvironment = Keystore_AzureEnvironment_AzureUsGovernment
+)
+
+// AzureEnvironments returns the known values of AzureEnvironment.
+func AzureEnvironments() []AzureEnvironment {
+	return []AzureEnvironment{
+		AzureEnvironment_Azure,
+		AzureEnvironment_AzureChina,
+		AzureEnvironment_AzureGermany,
+		AzureEnvironment_AzureUsGovernment,
+	}
+}
+
+// ParseAzureEnvironment returns the specified value as an AzureEnvironment. If it is not a known value, it is returned
+// together with an *UnknownEnumValueError.
+func ParseAzureEnvironment(value string) (AzureEnvironment, error) {
+	result := AzureEnvironment(value)
+	if !result.Valid() {
+		return result, &UnknownEnumValueError{Type: "AzureEnvironment", Value: value}
+	}
+	return result, nil
+}
+
+// String returns the value as a string.
+func (value AzureEnvironment) String() string {
+	return string(value)
+}
+
+// Ptr returns a pointer to the value as a string, for use in the models and options.
+func (value AzureEnvironment) Ptr() *string {
+	s := string(value)
+	return &s
+}
+
+// Valid returns true if the value is one of the known values of AzureEnvironment.
+func (value AzureEnvironment) Valid() bool {
+	for _, known := range AzureEnvironments() {
+		if value == known {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON implements json.Marshaler.
+func (value AzureEnvironment) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(value))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Unknown values are preserved.
+func (value *AzureEnvironment) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*value = AzureEnvironment(s)
+	return nil
+}
+
+// GetAzureEnvironment returns the AzureEnvironment property as an AzureEnvironment, or "" if it is not set.
+func (keystore *Keystore) GetAzureEnvironment() AzureEnvironment {
+	if keystore.AzureEnvironment == nil {
+		return ""
+	}
+	return AzureEnvironment(*keystore.AzureEnvironment)
+}
+
+// GetAzureEnvironment returns the AzureEnvironment property as an AzureEnvironment, or "" if it is not set.
+func (keystore *KeystoreTypeAzure) GetAzureEnvironment() AzureEnvironment {
+	if keystore.AzureEnvironment == nil {
+		return ""
+	}
+	return AzureEnvironment(*keystore.AzureEnvironment)
+}
+
+// IbmVariant : The variant of an IBM Cloud KMS keystore.
+type IbmVariant string
+
+// Constants for the known values of IbmVariant.
+const (
+	IbmVariant_Hpcs       IbmVariant = Keystore_IbmVariant_Hpcs
+	IbmVariant_Internal   IbmVariant = Keystore_IbmVariant_Internal
+	IbmVariant_KeyProtect IbmVariant = Keystore_IbmVariant_KeyProtect
+)
+
+// IbmVariants returns the known values of IbmVariant.
+func IbmVariants() []IbmVariant {
+	return []IbmVariant{
+		IbmVariant_Hpcs,
+		IbmVariant_Internal,
+		IbmVariant_KeyProtect,
+	}
+}
+
+// ParseIbmVariant returns the specified value as an IbmVariant. If it is not a known value, it is returned together
+// with an *UnknownEnumValueError.
+func ParseIbmVariant(value string) (IbmVariant, error) {
+	result := IbmVariant(value)
+	if !result.Valid() {
+		return result, &UnknownEnumValueError{Type: "IbmVariant", Value: value}
+	}
+	return result, nil
+}
+
+// String returns the value as a string.
+func (value IbmVariant) String() string {
+	return string(value)
+}
+
+// Ptr returns a pointer to the value as a string, for use in the models and options.
+func (value IbmVariant) Ptr() *string {
+	s := string(value)
+	return &s
+}
+
+// Valid returns true if the value is one of the known values of IbmVariant.
+func (value IbmVariant) Valid() bool {
+	for _, known := range IbmVariants() {
+		if value == known {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON implements json.Marshaler.
+func (value IbmVariant) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(value))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Unknown values are preserved.
+func (value *IbmVariant) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*value = IbmVariant(s)
+	return nil
+}
+
+// GetIbmVariant returns the IbmVariant property as an IbmVariant, or "" if it is not set.
+func (keystore *Keystore) GetIbmVariant() IbmVariant {
+	if keystore.IbmVariant == nil {
+		return ""
+	}
+	return IbmVariant(*keystore.IbmVariant)
+}
+
+// GetIbmVariant returns the IbmVariant property as an IbmVariant, or "" if it is not set.
+func (keystore *KeystoreTypeIbmCloudKms) GetIbmVariant() IbmVariant {
+	if keystore.IbmVariant == nil {
+		return ""
+	}
+	return IbmVariant(*keystore.IbmVariant)
+}
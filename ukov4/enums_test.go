@@ -0,0 +1,87 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4_test
+
+import (
+	"encoding/json"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-hpcs-uko-sdk/ukov4"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`Typed enums`, func() {
+	It(`Parse and validate known values`, func() {
+		state, err := ukov4.ParseKeyState("pre_activation")
+		Expect(err).To(BeNil())
+		Expect(state).To(Equal(ukov4.KeyState_PreActivation))
+		Expect(state.Valid()).To(BeTrue())
+		Expect(state.String()).To(Equal(ukov4.ManagedKey_State_PreActivation))
+		Expect(ukov4.KeystoreTypes()).To(ContainElement(ukov4.KeystoreType_GoogleKms))
+		Expect(ukov4.KeystoreHealthStatuses()).To(ContainElement(ukov4.KeystoreHealthStatus_Ok))
+	})
+	It(`Preserve unknown values (negative test)`, func() {
+		algorithm, err := ukov4.ParseKeyAlgorithm("kyber")
+		Expect(err).To(Equal(&ukov4.UnknownEnumValueError{Type: "KeyAlgorithm", Value: "kyber"}))
+		Expect(algorithm).To(Equal(ukov4.KeyAlgorithm("kyber")))
+		Expect(algorithm.Valid()).To(BeFalse())
+
+		var status struct {
+			Health ukov4.KeystoreHealthStatus `json:"health"`
+		}
+		Expect(json.Unmarshal([]byte(`{"health": "degraded"}`), &status)).To(Succeed())
+		Expect(status.Health.Valid()).To(BeFalse())
+		buffer, err := json.Marshal(status)
+		Expect(err).To(BeNil())
+		Expect(string(buffer)).To(Equal(`{"health":"degraded"}`))
+	})
+	It(`Read model properties through typed accessors`, func() {
+		key := &ukov4.ManagedKey{State: ukov4.KeyState_Active.Ptr(), Algorithm: core.StringPtr("aes")}
+		Expect(key.GetState()).To(Equal(ukov4.KeyState_Active))
+		Expect(key.GetAlgorithm()).To(Equal(ukov4.KeyAlgorithm_Aes))
+		Expect(new(ukov4.Keystore).GetType()).To(Equal(ukov4.KeystoreType("")))
+
+		status := &ukov4.StatusInKeystore{
+			KeystoreSyncFlag:       core.StringPtr("out_of_sync"),
+			KeystoreSyncFlagDetail: core.StringPtr("connection_error"),
+		}
+		Expect(status.GetKeystoreSyncFlag()).To(Equal(ukov4.KeystoreSyncFlag_OutOfSync))
+		Expect(status.GetKeystoreSyncFlagDetail()).To(Equal(ukov4.KeystoreSyncFlagDetail_ConnectionError))
+
+		keystore := &ukov4.Keystore{
+			AzureLocation:    core.StringPtr("europe_west"),
+			AzureEnvironment: core.StringPtr("azure"),
+			IbmVariant:       core.StringPtr("key_protect"),
+		}
+		Expect(keystore.GetAzureLocation()).To(Equal(ukov4.AzureLocation_EuropeWest))
+		Expect(keystore.GetAzureEnvironment()).To(Equal(ukov4.AzureEnvironment_Azure))
+		Expect(keystore.GetIbmVariant()).To(Equal(ukov4.IbmVariant_KeyProtect))
+
+		azure := &ukov4.KeystoreTypeAzure{
+			Type:             core.StringPtr("azure_key_vault"),
+			AzureLocation:    core.StringPtr("europe_west"),
+			AzureEnvironment: core.StringPtr("azure"),
+		}
+		Expect(azure.GetType()).To(Equal(ukov4.KeystoreType_AzureKeyVault))
+		Expect(azure.GetAzureLocation()).To(Equal(ukov4.AzureLocation_EuropeWest))
+		Expect(azure.GetAzureEnvironment()).To(Equal(ukov4.AzureEnvironment_Azure))
+		ibm := &ukov4.KeystoreTypeIbmCloudKms{IbmVariant: core.StringPtr("key_protect")}
+		Expect(ibm.GetIbmVariant()).To(Equal(ukov4.IbmVariant_KeyProtect))
+		Expect(ibm.GetType()).To(Equal(ukov4.KeystoreType("")))
+	})
+})
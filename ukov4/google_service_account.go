@@ -0,0 +1,150 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+)
+
+// GoogleServiceAccountKey : A Google Cloud service account key file, as downloaded from the Google Cloud console.
+// Only the properties used by Google Cloud KMS keystores are decoded; the file itself is kept unchanged and is what is
+// sent to the service.
+type GoogleServiceAccountKey struct {
+	// The type of the key file; always "service_account".
+	Type string `json:"type"`
+
+	// The project the service account belongs to.
+	ProjectID string `json:"project_id"`
+
+	// The ID of the private key.
+	PrivateKeyID string `json:"private_key_id"`
+
+	// The PEM encoded private key.
+	PrivateKey string `json:"private_key"`
+
+	// The email address of the service account.
+	ClientEmail string `json:"client_email"`
+
+	data []byte
+}
+
+// InvalidGoogleServiceAccountKeyError : The error returned when a file is not a valid Google Cloud service account key.
+type InvalidGoogleServiceAccountKeyError struct {
+	// The path of the file, if it was loaded from disk.
+	Path string
+
+	// The problems found.
+	Problems []string
+}
+
+// Error implements the error interface.
+func (e *InvalidGoogleServiceAccountKeyError) Error() string {
+	source := "service account key"
+	if e.Path != "" {
+		source = fmt.Sprintf("service account key '%s'", e.Path)
+	}
+	return fmt.Sprintf("invalid %s: %s", source, strings.Join(e.Problems, "; "))
+}
+
+// LoadGoogleServiceAccountKey reads and validates a Google Cloud service account key file.
+func LoadGoogleServiceAccountKey(path string) (*GoogleServiceAccountKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := ParseGoogleServiceAccountKey(data)
+	if invalidErr, ok := err.(*InvalidGoogleServiceAccountKeyError); ok {
+		invalidErr.Path = path
+	}
+	return key, err
+}
+
+// ParseGoogleServiceAccountKey validates the contents of a Google Cloud service account key file. It returns an
+// *InvalidGoogleServiceAccountKeyError listing every problem found.
+func ParseGoogleServiceAccountKey(data []byte) (*GoogleServiceAccountKey, error) {
+	key := &GoogleServiceAccountKey{}
+	if err := json.Unmarshal(data, key); err != nil {
+		return nil, &InvalidGoogleServiceAccountKeyError{Problems: []string{"not a JSON key file: " + err.Error()}}
+	}
+
+	var problems []string
+	if key.Type != "service_account" {
+		problems = append(problems, fmt.Sprintf("type is '%s' instead of 'service_account'", key.Type))
+	}
+	for _, field := range []struct{ name, value string }{
+		{"project_id", key.ProjectID},
+		{"private_key_id", key.PrivateKeyID},
+		{"private_key", key.PrivateKey},
+		{"client_email", key.ClientEmail},
+	} {
+		if field.value == "" {
+			problems = append(problems, field.name+" is missing")
+		}
+	}
+	if key.PrivateKey != "" {
+		if block, _ := pem.Decode([]byte(key.PrivateKey)); block == nil || block.Type != "PRIVATE KEY" {
+			problems = append(problems, "private_key is not a PEM encoded private key")
+		}
+	}
+	if len(problems) > 0 {
+		return nil, &InvalidGoogleServiceAccountKeyError{Problems: problems}
+	}
+	key.data = data
+	return key, nil
+}
+
+// Credentials returns the key file encoded in Base64, as expected by the google_credentials property of keystores.
+func (key *GoogleServiceAccountKey) Credentials() string {
+	return base64.StdEncoding.EncodeToString(key.data)
+}
+
+// KeystoreCreationRequest returns the creation request of a Google Cloud KMS keystore that uses the key, with the
+// project and private key IDs taken from the key. Use GoogleKmsKeystoreBuilder.ServiceAccountKey to also set the
+// location or key ring.
+func (key *GoogleServiceAccountKey) KeystoreCreationRequest(vaultID string, name string) (*KeystoreCreationRequestKeystoreTypeGoogleKmsCreate, error) {
+	return NewGoogleKmsKeystore(vaultID, name).ServiceAccountKey(key).Build()
+}
+
+// KeystoreUpdateRequest returns the update request that makes a Google Cloud KMS keystore use the key.
+func (key *GoogleServiceAccountKey) KeystoreUpdateRequest() *KeystoreUpdateRequestKeystoreTypeGoogleKmsUpdate {
+	return &KeystoreUpdateRequestKeystoreTypeGoogleKmsUpdate{
+		GoogleCredentials:  core.StringPtr(key.Credentials()),
+		GoogleProjectID:    core.StringPtr(key.ProjectID),
+		GooglePrivateKeyID: core.StringPtr(key.PrivateKeyID),
+	}
+}
+
+// KeystoreCredentials returns the key as the credentials of a Google Cloud KMS keystore, for use with
+// RotateKeystoreCredentials.
+func (key *GoogleServiceAccountKey) KeystoreCredentials() *KeystoreCredentials {
+	return &KeystoreCredentials{
+		GoogleCredentials:  core.StringPtr(key.Credentials()),
+		GooglePrivateKeyID: core.StringPtr(key.PrivateKeyID),
+	}
+}
+
+// ServiceAccountKey sets the credentials, project ID and private key ID from a service account key.
+func (builder *GoogleKmsKeystoreBuilder) ServiceAccountKey(key *GoogleServiceAccountKey) *GoogleKmsKeystoreBuilder {
+	return builder.Credentials(key.Credentials()).ProjectID(key.ProjectID).PrivateKeyID(key.PrivateKeyID)
+}
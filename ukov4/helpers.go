@@ -0,0 +1,122 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-hpcs-uko-sdk/common"
+	"github.com/go-openapi/strfmt"
+)
+
+// getETag returns the value of the ETag header of the specified response, or "" if there is none.
+func getETag(response *core.DetailedResponse) string {
+	if response == nil || response.Headers == nil {
+		return ""
+	}
+	return response.Headers.Get("ETag")
+}
+
+// isConflict returns true if the specified response indicates that the resource was modified concurrently,
+// either because the If-Match precondition failed or because the resource is no longer in the expected state.
+func isConflict(response *core.DetailedResponse) bool {
+	if response == nil {
+		return false
+	}
+	return response.StatusCode == http.StatusConflict || response.StatusCode == http.StatusPreconditionFailed
+}
+
+// dateOf returns the calendar date of the specified time, in UTC.
+func dateOf(t time.Time) strfmt.Date {
+	y, m, d := t.UTC().Date()
+	return strfmt.Date(time.Date(y, m, d, 0, 0, 0, 0, time.UTC))
+}
+
+// sleepWithContext pauses for the specified duration, returning early with the context's error if it is done.
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// int64NilMapper returns the decimal representation of the specified value, or "" if it is nil.
+func int64NilMapper(i *int64) string {
+	if i == nil {
+		return ""
+	}
+	return strconv.FormatInt(*i, 10)
+}
+
+// dateNilMapper returns the string representation of the specified date, or "" if it is nil.
+func dateNilMapper(d *strfmt.Date) string {
+	if d == nil {
+		return ""
+	}
+	return d.String()
+}
+
+// markdownPrinter writes formatted text to a writer, remembering the first error so that callers
+// producing multi-line documents only need to check it once at the end.
+type markdownPrinter struct {
+	w   io.Writer
+	err error
+}
+
+func (p *markdownPrinter) printf(format string, args ...interface{}) {
+	if p.err != nil {
+		return
+	}
+	_, p.err = fmt.Fprintf(p.w, format, args...)
+}
+
+// toKeystoresPropertiesCreate returns the keystore properties of a template as the base KeystoresPropertiesCreate
+// model, whichever of its subtypes they were created as.
+func toKeystoresPropertiesCreate(properties KeystoresPropertiesCreateIntf) (result *KeystoresPropertiesCreate, err error) {
+	if base, ok := properties.(*KeystoresPropertiesCreate); ok {
+		return base, nil
+	}
+	result = new(KeystoresPropertiesCreate)
+	err = remarshal(properties, result)
+	return
+}
+
+// toKeystoresPropertiesUpdate returns the keystore properties of a template update as the base
+// KeystoresPropertiesUpdate model, whichever of its subtypes they were created as.
+func toKeystoresPropertiesUpdate(properties KeystoresPropertiesUpdateIntf) (result *KeystoresPropertiesUpdate, err error) {
+	if base, ok := properties.(*KeystoresPropertiesUpdate); ok {
+		return base, nil
+	}
+	result = new(KeystoresPropertiesUpdate)
+	err = remarshal(properties, result)
+	return
+}
+
+// remarshal converts a model into another by way of its JSON representation, see common.Remarshal.
+func remarshal(from interface{}, to interface{}) error {
+	return common.Remarshal(from, to)
+}
@@ -0,0 +1,319 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/go-openapi/strfmt"
+)
+
+// Default values used by ReconcilePendingActivations when waiting for key distribution.
+const (
+	DefaultDistributionPollInterval = 5 * time.Second
+	DefaultDistributionTimeout      = 5 * time.Minute
+)
+
+// ReconcilePendingActivations : Activate managed keys whose activation date has arrived
+// Finds all managed keys in the pre_activation state whose activation_date is on or before the reconciliation date and
+// activates them. Every key is re-read before activation and activated with its current ETag, so running the
+// reconciler concurrently from several replicas activates each key at most once; a replica that loses the race
+// reports the key as skipped.
+func (uko *UkoV4) ReconcilePendingActivations(reconcilePendingActivationsOptions *ReconcilePendingActivationsOptions) (result *ReconcilePendingActivationsResult, err error) {
+	return uko.ReconcilePendingActivationsWithContext(context.Background(), reconcilePendingActivationsOptions)
+}
+
+// ReconcilePendingActivationsWithContext is an alternate form of the ReconcilePendingActivations method which supports a Context parameter
+func (uko *UkoV4) ReconcilePendingActivationsWithContext(ctx context.Context, reconcilePendingActivationsOptions *ReconcilePendingActivationsOptions) (result *ReconcilePendingActivationsResult, err error) {
+	err = core.ValidateNotNil(reconcilePendingActivationsOptions, "reconcilePendingActivationsOptions cannot be nil")
+	if err != nil {
+		return
+	}
+
+	asOf := dateOf(time.Now())
+	if reconcilePendingActivationsOptions.AsOf != nil {
+		asOf = *reconcilePendingActivationsOptions.AsOf
+	}
+
+	listOptions := &ListManagedKeysOptions{
+		VaultID:           reconcilePendingActivationsOptions.VaultID,
+		State:             []string{ManagedKey_State_PreActivation},
+		ActivationDateMax: core.StringPtr(asOf.String()),
+	}
+	pager, err := uko.NewManagedKeysPager(listOptions)
+	if err != nil {
+		return
+	}
+	keys, err := pager.GetAllWithContext(ctx)
+	if err != nil {
+		return
+	}
+
+	result = &ReconcilePendingActivationsResult{
+		AsOf: &asOf,
+	}
+	for i := range keys {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		key := &keys[i]
+		// The server-side filter is authoritative, but keys without an activation date or with one in the future
+		// must never be activated, so check again before acting.
+		if key.ActivationDate == nil || time.Time(*key.ActivationDate).After(time.Time(asOf)) {
+			continue
+		}
+		uko.reconcilePendingActivation(ctx, reconcilePendingActivationsOptions, key, result)
+	}
+	return
+}
+
+// reconcilePendingActivation activates a single managed key and records the outcome in the result.
+func (uko *UkoV4) reconcilePendingActivation(ctx context.Context, options *ReconcilePendingActivationsOptions, key *ManagedKey, result *ReconcilePendingActivationsResult) {
+	current, response, err := uko.GetManagedKeyWithContext(ctx, &GetManagedKeyOptions{ID: key.ID})
+	if err != nil {
+		result.emit(options, KeyActivationEvent_Type_Failed, key, "unable to read managed key", err)
+		return
+	}
+	if current.State == nil || *current.State != ManagedKey_State_PreActivation {
+		result.emit(options, KeyActivationEvent_Type_Skipped, current,
+			fmt.Sprintf("managed key is in state '%s'", core.StringNilMapper(current.State)), nil)
+		return
+	}
+	if options.DryRun != nil && *options.DryRun {
+		result.emit(options, KeyActivationEvent_Type_WouldActivate, current, "dry run; managed key not activated", nil)
+		return
+	}
+
+	activated, response, err := uko.ActivateManagedKeyWithContext(ctx, &ActivateManagedKeyOptions{
+		ID:      current.ID,
+		IfMatch: core.StringPtr(getETag(response)),
+	})
+	if err != nil {
+		if isConflict(response) {
+			result.emit(options, KeyActivationEvent_Type_Skipped, current, "managed key was modified concurrently", nil)
+			return
+		}
+		result.emit(options, KeyActivationEvent_Type_Failed, current, "unable to activate managed key", err)
+		return
+	}
+	result.emit(options, KeyActivationEvent_Type_Activated, activated, "managed key activated", nil)
+
+	if options.WaitForDistribution == nil || !*options.WaitForDistribution {
+		return
+	}
+	distributed, err := uko.waitForKeyDistribution(ctx, *activated.ID, options.PollInterval, options.DistributionTimeout)
+	switch {
+	case err != nil:
+		result.emit(options, KeyActivationEvent_Type_Failed, activated, "unable to read key distribution status", err)
+	case distributed:
+		result.emit(options, KeyActivationEvent_Type_Distributed, activated, "managed key is active in all keystores", nil)
+	default:
+		result.emit(options, KeyActivationEvent_Type_DistributionTimeout, activated,
+			"managed key is not yet active in all keystores", nil)
+	}
+}
+
+// waitForKeyDistribution polls the distribution status of a managed key until it is active in every keystore, the
+// timeout expires (false is returned) or an error occurs.
+func (uko *UkoV4) waitForKeyDistribution(ctx context.Context, id string, pollInterval time.Duration, timeout time.Duration) (distributed bool, err error) {
+	if pollInterval <= 0 {
+		pollInterval = DefaultDistributionPollInterval
+	}
+	if timeout <= 0 {
+		timeout = DefaultDistributionTimeout
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		var status *StatusInKeystores
+		status, _, err = uko.GetKeyDistributionStatusForKeystoresWithContext(ctx, &GetKeyDistributionStatusForKeystoresOptions{
+			ID: core.StringPtr(id),
+		})
+		if err != nil {
+			return
+		}
+		if isDistributed(status.StatusInKeystores) {
+			return true, nil
+		}
+		if !time.Now().Add(pollInterval).Before(deadline) {
+			return false, nil
+		}
+		if err = sleepWithContext(ctx, pollInterval); err != nil {
+			return
+		}
+	}
+}
+
+// isDistributed returns true if the key is active in every keystore.
+func isDistributed(statuses []StatusInKeystore) bool {
+	for _, status := range statuses {
+		if status.Status == nil || *status.Status != StatusInKeystore_Status_Active {
+			return false
+		}
+	}
+	return true
+}
+
+// ReconcilePendingActivationsOptions : The ReconcilePendingActivations options.
+type ReconcilePendingActivationsOptions struct {
+	// The UUIDs of the vaults to reconcile. All vaults are reconciled if empty.
+	VaultID []string
+
+	// Keys whose activation date is on or before this date are activated. Defaults to the current date in UTC.
+	AsOf *strfmt.Date
+
+	// Only report the keys that would be activated, without activating them.
+	DryRun *bool
+
+	// After activating a key, wait until it is active in all of its keystores.
+	WaitForDistribution *bool
+
+	// The interval between distribution status checks. Defaults to DefaultDistributionPollInterval.
+	PollInterval time.Duration
+
+	// How long to wait for the distribution of a single key. Defaults to DefaultDistributionTimeout.
+	DistributionTimeout time.Duration
+
+	// Invoked synchronously for every event emitted during reconciliation.
+	EventHandler func(*KeyActivationEvent)
+}
+
+// NewReconcilePendingActivationsOptions : Instantiate ReconcilePendingActivationsOptions
+func (*UkoV4) NewReconcilePendingActivationsOptions() *ReconcilePendingActivationsOptions {
+	return &ReconcilePendingActivationsOptions{}
+}
+
+// SetVaultID : Allow user to set VaultID
+func (_options *ReconcilePendingActivationsOptions) SetVaultID(vaultID []string) *ReconcilePendingActivationsOptions {
+	_options.VaultID = vaultID
+	return _options
+}
+
+// SetAsOf : Allow user to set AsOf
+func (_options *ReconcilePendingActivationsOptions) SetAsOf(asOf *strfmt.Date) *ReconcilePendingActivationsOptions {
+	_options.AsOf = asOf
+	return _options
+}
+
+// SetDryRun : Allow user to set DryRun
+func (_options *ReconcilePendingActivationsOptions) SetDryRun(dryRun bool) *ReconcilePendingActivationsOptions {
+	_options.DryRun = core.BoolPtr(dryRun)
+	return _options
+}
+
+// SetWaitForDistribution : Allow user to set WaitForDistribution
+func (_options *ReconcilePendingActivationsOptions) SetWaitForDistribution(waitForDistribution bool) *ReconcilePendingActivationsOptions {
+	_options.WaitForDistribution = core.BoolPtr(waitForDistribution)
+	return _options
+}
+
+// SetPollInterval : Allow user to set PollInterval
+func (_options *ReconcilePendingActivationsOptions) SetPollInterval(pollInterval time.Duration) *ReconcilePendingActivationsOptions {
+	_options.PollInterval = pollInterval
+	return _options
+}
+
+// SetDistributionTimeout : Allow user to set DistributionTimeout
+func (_options *ReconcilePendingActivationsOptions) SetDistributionTimeout(distributionTimeout time.Duration) *ReconcilePendingActivationsOptions {
+	_options.DistributionTimeout = distributionTimeout
+	return _options
+}
+
+// SetEventHandler : Allow user to set EventHandler
+func (_options *ReconcilePendingActivationsOptions) SetEventHandler(eventHandler func(*KeyActivationEvent)) *ReconcilePendingActivationsOptions {
+	_options.EventHandler = eventHandler
+	return _options
+}
+
+// KeyActivationEvent : An event emitted while reconciling the activation of a managed key.
+type KeyActivationEvent struct {
+	// The type of the event.
+	Type *string `json:"type"`
+
+	// The v4 UUID of the managed key.
+	KeyID *string `json:"key_id,omitempty"`
+
+	// The label of the managed key.
+	Label *string `json:"label,omitempty"`
+
+	// First day when the key is active.
+	ActivationDate *strfmt.Date `json:"activation_date,omitempty"`
+
+	// A human-readable description of the event.
+	Message *string `json:"message,omitempty"`
+
+	// The error that caused a failed event.
+	Error error `json:"-"`
+
+	// Date and time when the event was emitted.
+	Timestamp *strfmt.DateTime `json:"timestamp"`
+}
+
+// Constants associated with the KeyActivationEvent.Type property.
+// The type of the event.
+const (
+	KeyActivationEvent_Type_Activated           = "activated"
+	KeyActivationEvent_Type_Distributed         = "distributed"
+	KeyActivationEvent_Type_DistributionTimeout = "distribution_timeout"
+	KeyActivationEvent_Type_Failed              = "failed"
+	KeyActivationEvent_Type_Skipped             = "skipped"
+	KeyActivationEvent_Type_WouldActivate       = "would_activate"
+)
+
+// ReconcilePendingActivationsResult : The outcome of a ReconcilePendingActivations run.
+type ReconcilePendingActivationsResult struct {
+	// The date that activation dates were compared against.
+	AsOf *strfmt.Date `json:"as_of"`
+
+	// All events emitted during the run, in order.
+	Events []KeyActivationEvent `json:"events"`
+}
+
+// Count returns the number of events of the specified type.
+func (result *ReconcilePendingActivationsResult) Count(eventType string) (count int) {
+	for _, event := range result.Events {
+		if *event.Type == eventType {
+			count++
+		}
+	}
+	return
+}
+
+// emit records an event in the result and passes it to the event handler, if any.
+func (result *ReconcilePendingActivationsResult) emit(options *ReconcilePendingActivationsOptions, eventType string, key *ManagedKey, message string, err error) {
+	now := strfmt.DateTime(time.Now())
+	event := KeyActivationEvent{
+		Type:      core.StringPtr(eventType),
+		Message:   core.StringPtr(message),
+		Error:     err,
+		Timestamp: &now,
+	}
+	if key != nil {
+		event.KeyID = key.ID
+		event.Label = key.Label
+		event.ActivationDate = key.ActivationDate
+	}
+	if err != nil {
+		event.Message = core.StringPtr(fmt.Sprintf("%s: %s", message, err.Error()))
+	}
+	result.Events = append(result.Events, event)
+	if options.EventHandler != nil {
+		options.EventHandler(&result.Events[len(result.Events)-1])
+	}
+}
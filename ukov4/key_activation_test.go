@@ -0,0 +1,152 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-hpcs-uko-sdk/ukov4"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`ReconcilePendingActivations`, func() {
+	var testServer *httptest.Server
+	var activateCalls int
+	var keyState string
+	var activateStatus int
+
+	BeforeEach(func() {
+		activateCalls = 0
+		keyState = "pre_activation"
+		activateStatus = 200
+		testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+
+			res.Header().Set("Content-type", "application/json")
+			switch {
+			case req.Method == "GET" && req.URL.EscapedPath() == "/api/v4/managed_keys":
+				Expect(req.URL.Query()["state"]).To(Equal([]string{"pre_activation"}))
+				Expect(req.URL.Query()["activation_date_max"]).To(Equal([]string{"2023-06-01"}))
+				res.WriteHeader(200)
+				fmt.Fprintf(res, `{"total_count": 2, "limit": 2, "offset": 0, "managed_keys": [%s, %s]}`,
+					mockPendingKey("k1", keyState, "2023-05-01"), mockPendingKey("k2", "pre_activation", "2030-01-01"))
+			case req.Method == "GET" && req.URL.EscapedPath() == "/api/v4/managed_keys/k1":
+				res.Header().Set("ETag", "etag-k1")
+				res.WriteHeader(200)
+				fmt.Fprint(res, mockPendingKey("k1", keyState, "2023-05-01"))
+			case req.Method == "POST" && req.URL.EscapedPath() == "/api/v4/managed_keys/k1/activate":
+				Expect(req.Header.Get("If-Match")).To(Equal("etag-k1"))
+				activateCalls++
+				res.WriteHeader(activateStatus)
+				if activateStatus != 200 {
+					fmt.Fprint(res, `{"errors": [{"code": "precondition_failed", "message": "ETag mismatch"}]}`)
+					return
+				}
+				fmt.Fprint(res, mockPendingKey("k1", "active", "2023-05-01"))
+			case req.Method == "GET" && req.URL.EscapedPath() == "/api/v4/managed_keys/k1/status_in_keystores":
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"status_in_keystores": [{"status": "active", "keystore_sync_flag": "ok", "keystore_sync_flag_detail": "active_key_is_active_in_keystore"}]}`)
+			default:
+				Fail(fmt.Sprintf("unexpected request %s %s", req.Method, req.URL.EscapedPath()))
+			}
+		}))
+	})
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	newService := func() *ukov4.UkoV4 {
+		ukoService, serviceErr := ukov4.NewUkoV4(&ukov4.UkoV4Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+		return ukoService
+	}
+
+	It(`Invoke ReconcilePendingActivations with nil options (negative test)`, func() {
+		result, err := newService().ReconcilePendingActivations(nil)
+		Expect(err).ToNot(BeNil())
+		Expect(result).To(BeNil())
+	})
+	It(`Activate due keys and wait for distribution`, func() {
+		ukoService := newService()
+		var events []string
+		options := ukoService.NewReconcilePendingActivationsOptions().
+			SetAsOf(CreateMockDate("2023-06-01")).
+			SetWaitForDistribution(true).
+			SetPollInterval(time.Millisecond).
+			SetEventHandler(func(event *ukov4.KeyActivationEvent) {
+				events = append(events, *event.Type)
+			})
+
+		result, err := ukoService.ReconcilePendingActivations(options)
+		Expect(err).To(BeNil())
+		Expect(activateCalls).To(Equal(1))
+		Expect(events).To(Equal([]string{ukov4.KeyActivationEvent_Type_Activated, ukov4.KeyActivationEvent_Type_Distributed}))
+		Expect(result.Count(ukov4.KeyActivationEvent_Type_Activated)).To(Equal(1))
+		Expect(*result.Events[0].KeyID).To(Equal("k1"))
+	})
+	It(`Report due keys without activating them in dry-run mode`, func() {
+		ukoService := newService()
+		options := ukoService.NewReconcilePendingActivationsOptions().
+			SetAsOf(CreateMockDate("2023-06-01")).
+			SetDryRun(true)
+
+		result, err := ukoService.ReconcilePendingActivations(options)
+		Expect(err).To(BeNil())
+		Expect(activateCalls).To(Equal(0))
+		Expect(result.Count(ukov4.KeyActivationEvent_Type_WouldActivate)).To(Equal(1))
+	})
+	It(`Skip keys that were activated by another replica`, func() {
+		ukoService := newService()
+		options := ukoService.NewReconcilePendingActivationsOptions().SetAsOf(CreateMockDate("2023-06-01"))
+
+		activateStatus = 412
+		result, err := ukoService.ReconcilePendingActivations(options)
+		Expect(err).To(BeNil())
+		Expect(result.Count(ukov4.KeyActivationEvent_Type_Skipped)).To(Equal(1))
+		Expect(result.Count(ukov4.KeyActivationEvent_Type_Failed)).To(Equal(0))
+
+		keyState = "active"
+		activateCalls = 0
+		result, err = ukoService.ReconcilePendingActivations(options)
+		Expect(err).To(BeNil())
+		Expect(activateCalls).To(Equal(0))
+		Expect(result.Count(ukov4.KeyActivationEvent_Type_Skipped)).To(Equal(1))
+	})
+	It(`Report activation failures as events`, func() {
+		ukoService := newService()
+		options := ukoService.NewReconcilePendingActivationsOptions().SetAsOf(CreateMockDate("2023-06-01"))
+
+		activateStatus = 500
+		result, err := ukoService.ReconcilePendingActivations(options)
+		Expect(err).To(BeNil())
+		Expect(result.Count(ukov4.KeyActivationEvent_Type_Failed)).To(Equal(1))
+		Expect(result.Events[0].Error).ToNot(BeNil())
+	})
+})
+
+func mockPendingKey(id string, state string, activationDate string) string {
+	return fmt.Sprintf(`{"id": "%s", "label": "KEY-%s", "state": "%s", "algorithm": "aes", "size": "256", "activation_date": "%s", "referenced_keystores": [], "instances": [], "status_in_keystores": []}`,
+		id, id, state, activationDate)
+}
@@ -0,0 +1,334 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/go-openapi/strfmt"
+)
+
+// RespondToKeyCompromise : Run the incident response workflow for a compromised managed key
+// Takes a snapshot of the managed key, all of its versions, its associated resources and its status in keystores, then
+// rotates the key and deactivates every older version that is still active. The returned report names the IBM Cloud
+// KMS registrations affected by the compromise and can be written as JSON or Markdown. The report is returned even if
+// a remediation step fails, so that the snapshot is never lost.
+func (uko *UkoV4) RespondToKeyCompromise(respondToKeyCompromiseOptions *RespondToKeyCompromiseOptions) (result *KeyIncidentReport, err error) {
+	return uko.RespondToKeyCompromiseWithContext(context.Background(), respondToKeyCompromiseOptions)
+}
+
+// RespondToKeyCompromiseWithContext is an alternate form of the RespondToKeyCompromise method which supports a Context parameter
+func (uko *UkoV4) RespondToKeyCompromiseWithContext(ctx context.Context, respondToKeyCompromiseOptions *RespondToKeyCompromiseOptions) (result *KeyIncidentReport, err error) {
+	err = core.ValidateNotNil(respondToKeyCompromiseOptions, "respondToKeyCompromiseOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(respondToKeyCompromiseOptions, "respondToKeyCompromiseOptions")
+	if err != nil {
+		return
+	}
+
+	startedAt := strfmt.DateTime(time.Now())
+	result = &KeyIncidentReport{
+		KeyID:     respondToKeyCompromiseOptions.ID,
+		Reason:    respondToKeyCompromiseOptions.Reason,
+		StartedAt: &startedAt,
+	}
+	defer func() {
+		completedAt := strfmt.DateTime(time.Now())
+		result.CompletedAt = &completedAt
+	}()
+
+	// Snapshot
+	key, response, err := uko.GetManagedKeyWithContext(ctx, &GetManagedKeyOptions{ID: respondToKeyCompromiseOptions.ID})
+	if err != nil {
+		return
+	}
+	result.Key = key
+	result.StatusInKeystores = key.StatusInKeystores
+
+	versionsPager, err := uko.NewManagedKeyVersionsPager(&ListManagedKeyVersionsOptions{ID: key.ID})
+	if err != nil {
+		return
+	}
+	result.Versions, err = versionsPager.GetAllWithContext(ctx)
+	if err != nil {
+		return
+	}
+
+	resourcesPager, err := uko.NewAssociatedResourcesForManagedKeyPager(&ListAssociatedResourcesForManagedKeyOptions{ID: key.ID})
+	if err != nil {
+		return
+	}
+	result.AssociatedResources, err = resourcesPager.GetAllWithContext(ctx)
+	if err != nil {
+		return
+	}
+	for _, resource := range result.AssociatedResources {
+		if resource.ComIbmCloudKmsRegistration != nil {
+			result.AffectedRegistrations = append(result.AffectedRegistrations, *resource.ComIbmCloudKmsRegistration)
+		}
+	}
+
+	if respondToKeyCompromiseOptions.DryRun != nil && *respondToKeyCompromiseOptions.DryRun {
+		return
+	}
+
+	// Remediation
+	rotated, _, err := uko.RotateManagedKeyWithContext(ctx, &RotateManagedKeyOptions{
+		ID:      key.ID,
+		IfMatch: core.StringPtr(getETag(response)),
+	})
+	if err != nil {
+		result.addAction(KeyIncidentAction_Action_Rotate, key, err)
+		return
+	}
+	result.RotatedKey = rotated
+	result.addAction(KeyIncidentAction_Action_Rotate, rotated, nil)
+
+	failed := 0
+	for i := range result.Versions {
+		version := &result.Versions[i]
+		if !isSupersededVersion(version, rotated) {
+			continue
+		}
+		err = uko.deactivateManagedKeyVersion(ctx, version)
+		result.addAction(KeyIncidentAction_Action_Deactivate, version, err)
+		if err != nil {
+			failed++
+		}
+	}
+	err = nil
+	if failed > 0 {
+		err = fmt.Errorf("unable to deactivate %d of the previous versions of managed key '%s'", failed, *key.ID)
+	}
+	return
+}
+
+// isSupersededVersion returns true if the specified version is an older, still active version of the rotated key.
+// Versions that share the ID of the rotated key cannot be deactivated without deactivating the new version as well,
+// so they are never considered superseded.
+func isSupersededVersion(version *ManagedKey, rotated *ManagedKey) bool {
+	if version.State == nil || *version.State != ManagedKey_State_Active {
+		return false
+	}
+	if version.ID == nil || rotated.ID == nil || *version.ID == *rotated.ID {
+		return false
+	}
+	return version.Version == nil || rotated.Version == nil || *version.Version < *rotated.Version
+}
+
+// deactivateManagedKeyVersion deactivates a single version of a managed key using its current ETag.
+func (uko *UkoV4) deactivateManagedKeyVersion(ctx context.Context, version *ManagedKey) error {
+	_, response, err := uko.GetManagedKeyWithContext(ctx, &GetManagedKeyOptions{ID: version.ID})
+	if err != nil {
+		return err
+	}
+	_, _, err = uko.DeactivateManagedKeyWithContext(ctx, &DeactivateManagedKeyOptions{
+		ID:      version.ID,
+		IfMatch: core.StringPtr(getETag(response)),
+	})
+	return err
+}
+
+// RespondToKeyCompromiseOptions : The RespondToKeyCompromise options.
+type RespondToKeyCompromiseOptions struct {
+	// UUID of the compromised key.
+	ID *string `json:"id" validate:"required,ne="`
+
+	// Free-form description of the incident, copied to the report.
+	Reason *string `json:"reason,omitempty"`
+
+	// Only take the snapshot and write the report, without rotating the key or deactivating its versions.
+	DryRun *bool `json:"dry_run,omitempty"`
+}
+
+// NewRespondToKeyCompromiseOptions : Instantiate RespondToKeyCompromiseOptions
+func (*UkoV4) NewRespondToKeyCompromiseOptions(id string) *RespondToKeyCompromiseOptions {
+	return &RespondToKeyCompromiseOptions{
+		ID: core.StringPtr(id),
+	}
+}
+
+// SetID : Allow user to set ID
+func (_options *RespondToKeyCompromiseOptions) SetID(id string) *RespondToKeyCompromiseOptions {
+	_options.ID = core.StringPtr(id)
+	return _options
+}
+
+// SetReason : Allow user to set Reason
+func (_options *RespondToKeyCompromiseOptions) SetReason(reason string) *RespondToKeyCompromiseOptions {
+	_options.Reason = core.StringPtr(reason)
+	return _options
+}
+
+// SetDryRun : Allow user to set DryRun
+func (_options *RespondToKeyCompromiseOptions) SetDryRun(dryRun bool) *RespondToKeyCompromiseOptions {
+	_options.DryRun = core.BoolPtr(dryRun)
+	return _options
+}
+
+// KeyIncidentReport : The report produced by the key compromise incident response workflow.
+type KeyIncidentReport struct {
+	// UUID of the compromised key.
+	KeyID *string `json:"key_id"`
+
+	// Free-form description of the incident.
+	Reason *string `json:"reason,omitempty"`
+
+	// Date and time when the workflow started.
+	StartedAt *strfmt.DateTime `json:"started_at"`
+
+	// Date and time when the workflow completed.
+	CompletedAt *strfmt.DateTime `json:"completed_at"`
+
+	// The managed key as it was before any remediation.
+	Key *ManagedKey `json:"key,omitempty"`
+
+	// All versions of the managed key as they were before any remediation.
+	Versions []ManagedKey `json:"versions,omitempty"`
+
+	// The resources associated with the managed key.
+	AssociatedResources []AssociatedResource `json:"associated_resources,omitempty"`
+
+	// The status of the managed key in its keystores before any remediation.
+	StatusInKeystores []StatusInKeystore `json:"status_in_keystores,omitempty"`
+
+	// The IBM Cloud KMS registrations of the resources protected by the compromised key.
+	AffectedRegistrations []IbmCloudKmsRegistration `json:"affected_registrations,omitempty"`
+
+	// The new version of the managed key, if it was rotated.
+	RotatedKey *ManagedKey `json:"rotated_key,omitempty"`
+
+	// The remediation actions taken, in order.
+	Actions []KeyIncidentAction `json:"actions,omitempty"`
+}
+
+// KeyIncidentAction : A remediation action taken by the key compromise incident response workflow.
+type KeyIncidentAction struct {
+	// The action taken.
+	Action *string `json:"action"`
+
+	// The v4 UUID of the managed key the action was applied to.
+	KeyID *string `json:"key_id,omitempty"`
+
+	// The version of the managed key the action was applied to.
+	Version *int64 `json:"version,omitempty"`
+
+	// Whether the action succeeded.
+	Succeeded *bool `json:"succeeded"`
+
+	// The error message of a failed action.
+	Error *string `json:"error,omitempty"`
+}
+
+// Constants associated with the KeyIncidentAction.Action property.
+// The action taken.
+const (
+	KeyIncidentAction_Action_Deactivate = "deactivate"
+	KeyIncidentAction_Action_Rotate     = "rotate"
+)
+
+// addAction records a remediation action in the report.
+func (report *KeyIncidentReport) addAction(action string, key *ManagedKey, err error) {
+	entry := KeyIncidentAction{
+		Action:    core.StringPtr(action),
+		KeyID:     key.ID,
+		Version:   key.Version,
+		Succeeded: core.BoolPtr(err == nil),
+	}
+	if err != nil {
+		entry.Error = core.StringPtr(err.Error())
+	}
+	report.Actions = append(report.Actions, entry)
+}
+
+// WriteJSON writes the report to the specified writer as indented JSON.
+func (report *KeyIncidentReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// WriteMarkdown writes the report to the specified writer as a human-readable Markdown document.
+func (report *KeyIncidentReport) WriteMarkdown(w io.Writer) (err error) {
+	p := &markdownPrinter{w: w}
+	p.printf("# Key compromise incident report\n\n")
+	p.printf("- Managed key: `%s`\n", core.StringNilMapper(report.KeyID))
+	if report.Key != nil {
+		p.printf("- Label: %s\n", core.StringNilMapper(report.Key.Label))
+		if report.Key.Vault != nil {
+			p.printf("- Vault: %s (`%s`)\n", core.StringNilMapper(report.Key.Vault.Name), core.StringNilMapper(report.Key.Vault.ID))
+		}
+	}
+	if report.Reason != nil {
+		p.printf("- Reason: %s\n", *report.Reason)
+	}
+	if report.StartedAt != nil {
+		p.printf("- Started at: %s\n", report.StartedAt.String())
+	}
+	if report.CompletedAt != nil {
+		p.printf("- Completed at: %s\n", report.CompletedAt.String())
+	}
+
+	p.printf("\n## Affected IBM Cloud KMS registrations\n\n")
+	if len(report.AffectedRegistrations) == 0 {
+		p.printf("None.\n")
+	} else {
+		p.printf("| CRN | Service | Instance | Prevents key deletion |\n|---|---|---|---|\n")
+		for _, registration := range report.AffectedRegistrations {
+			p.printf("| %s | %s | %s | %t |\n", core.StringNilMapper(registration.Crn), core.StringNilMapper(registration.ServiceName),
+				core.StringNilMapper(registration.ServiceInstanceName), registration.PreventsKeyDeletion != nil && *registration.PreventsKeyDeletion)
+		}
+	}
+
+	p.printf("\n## Versions\n\n")
+	p.printf("| ID | Version | State | Activation date | Expiration date |\n|---|---|---|---|---|\n")
+	for _, version := range report.Versions {
+		p.printf("| %s | %s | %s | %s | %s |\n", core.StringNilMapper(version.ID), int64NilMapper(version.Version),
+			core.StringNilMapper(version.State), dateNilMapper(version.ActivationDate), dateNilMapper(version.ExpirationDate))
+	}
+
+	p.printf("\n## Status in keystores\n\n")
+	p.printf("| Keystore | Type | Status | Sync flag | Detail |\n|---|---|---|---|---|\n")
+	for _, status := range report.StatusInKeystores {
+		name, keystoreType := "", ""
+		if status.Keystore != nil {
+			name, keystoreType = core.StringNilMapper(status.Keystore.Name), core.StringNilMapper(status.Keystore.Type)
+		}
+		p.printf("| %s | %s | %s | %s | %s |\n", name, keystoreType, core.StringNilMapper(status.Status),
+			core.StringNilMapper(status.KeystoreSyncFlag), core.StringNilMapper(status.KeystoreSyncFlagDetail))
+	}
+
+	p.printf("\n## Actions\n\n")
+	if len(report.Actions) == 0 {
+		p.printf("None.\n")
+	}
+	for _, action := range report.Actions {
+		outcome := "succeeded"
+		if action.Error != nil {
+			outcome = "failed: " + *action.Error
+		}
+		p.printf("- %s `%s` version %s: %s\n", *action.Action, core.StringNilMapper(action.KeyID), int64NilMapper(action.Version), outcome)
+	}
+	return p.err
+}
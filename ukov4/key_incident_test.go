@@ -0,0 +1,144 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-hpcs-uko-sdk/ukov4"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`RespondToKeyCompromise`, func() {
+	var testServer *httptest.Server
+	var requests []string
+	var deactivateStatus int
+
+	BeforeEach(func() {
+		requests = nil
+		deactivateStatus = 200
+		testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+
+			requests = append(requests, req.Method+" "+req.URL.EscapedPath())
+			res.Header().Set("Content-type", "application/json")
+			switch req.Method + " " + req.URL.EscapedPath() {
+			case "GET /api/v4/managed_keys/k1":
+				res.Header().Set("ETag", "etag-k1")
+				res.WriteHeader(200)
+				fmt.Fprint(res, mockVersionedKey("k1", 2, "active"))
+			case "GET /api/v4/managed_keys/k1/versions":
+				res.WriteHeader(200)
+				fmt.Fprintf(res, `{"total_count": 2, "limit": 2, "offset": 0, "managed_keys": [%s, %s]}`,
+					mockVersionedKey("v1", 1, "active"), mockVersionedKey("k1", 2, "active"))
+			case "GET /api/v4/managed_keys/k1/associated_resources":
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"total_count": 1, "limit": 1, "offset": 0, "associated_resources": [{"id": "r1", "key_id_in_keystore": "kid", "name": "bucket", "type": "com_ibm_cloud_kms_registration", "com_ibm_cloud_kms_registration": {"prevents_key_deletion": true, "service_name": "cloud-object-storage", "service_instance_name": "cos-prod", "crn": "crn:v1:bluemix:public:cloud-object-storage:global:a/1::bucket:prod", "description": "prod bucket"}}]}`)
+			case "POST /api/v4/managed_keys/k1/rotate":
+				Expect(req.Header.Get("If-Match")).To(Equal("etag-k1"))
+				res.WriteHeader(200)
+				fmt.Fprint(res, mockVersionedKey("k1", 3, "active"))
+			case "GET /api/v4/managed_keys/v1":
+				res.Header().Set("ETag", "etag-v1")
+				res.WriteHeader(200)
+				fmt.Fprint(res, mockVersionedKey("v1", 1, "active"))
+			case "POST /api/v4/managed_keys/v1/deactivate":
+				Expect(req.Header.Get("If-Match")).To(Equal("etag-v1"))
+				res.WriteHeader(deactivateStatus)
+				fmt.Fprint(res, mockVersionedKey("v1", 1, "deactivated"))
+			default:
+				Fail(fmt.Sprintf("unexpected request %s %s", req.Method, req.URL.EscapedPath()))
+			}
+		}))
+	})
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	newService := func() *ukov4.UkoV4 {
+		ukoService, serviceErr := ukov4.NewUkoV4(&ukov4.UkoV4Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+		return ukoService
+	}
+
+	It(`Invoke RespondToKeyCompromise with invalid options (negative test)`, func() {
+		ukoService := newService()
+		report, err := ukoService.RespondToKeyCompromise(nil)
+		Expect(err).ToNot(BeNil())
+		Expect(report).To(BeNil())
+
+		report, err = ukoService.RespondToKeyCompromise(new(ukov4.RespondToKeyCompromiseOptions))
+		Expect(err).ToNot(BeNil())
+		Expect(report).To(BeNil())
+	})
+	It(`Snapshot, rotate and deactivate previous versions`, func() {
+		ukoService := newService()
+		options := ukoService.NewRespondToKeyCompromiseOptions("k1").SetReason("leaked in CI logs")
+
+		report, err := ukoService.RespondToKeyCompromise(options)
+		Expect(err).To(BeNil())
+		Expect(report.Versions).To(HaveLen(2))
+		Expect(report.AffectedRegistrations).To(HaveLen(1))
+		Expect(*report.RotatedKey.Version).To(Equal(int64(3)))
+		Expect(report.Actions).To(HaveLen(2))
+		Expect(*report.Actions[0].Action).To(Equal(ukov4.KeyIncidentAction_Action_Rotate))
+		Expect(*report.Actions[1].Action).To(Equal(ukov4.KeyIncidentAction_Action_Deactivate))
+		Expect(*report.Actions[1].KeyID).To(Equal("v1"))
+		Expect(requests).ToNot(ContainElement("POST /api/v4/managed_keys/k1/deactivate"))
+
+		var jsonOut bytes.Buffer
+		Expect(report.WriteJSON(&jsonOut)).To(Succeed())
+		var decoded map[string]interface{}
+		Expect(json.Unmarshal(jsonOut.Bytes(), &decoded)).To(Succeed())
+		Expect(decoded["reason"]).To(Equal("leaked in CI logs"))
+
+		var markdown bytes.Buffer
+		Expect(report.WriteMarkdown(&markdown)).To(Succeed())
+		Expect(markdown.String()).To(ContainSubstring("crn:v1:bluemix:public:cloud-object-storage:global:a/1::bucket:prod"))
+	})
+	It(`Only take the snapshot in dry-run mode`, func() {
+		ukoService := newService()
+		report, err := ukoService.RespondToKeyCompromise(ukoService.NewRespondToKeyCompromiseOptions("k1").SetDryRun(true))
+		Expect(err).To(BeNil())
+		Expect(report.RotatedKey).To(BeNil())
+		Expect(report.Actions).To(BeEmpty())
+		Expect(requests).ToNot(ContainElement("POST /api/v4/managed_keys/k1/rotate"))
+	})
+	It(`Report failed deactivations`, func() {
+		ukoService := newService()
+		deactivateStatus = 500
+		report, err := ukoService.RespondToKeyCompromise(ukoService.NewRespondToKeyCompromiseOptions("k1"))
+		Expect(err).ToNot(BeNil())
+		Expect(report).ToNot(BeNil())
+		Expect(*report.Actions[1].Succeeded).To(BeFalse())
+		Expect(report.Actions[1].Error).ToNot(BeNil())
+	})
+})
+
+func mockVersionedKey(id string, version int64, state string) string {
+	return fmt.Sprintf(`{"id": "%s", "version": %d, "label": "KEY", "state": "%s", "algorithm": "aes", "size": "256", "activation_date": "2023-01-01", "referenced_keystores": [], "instances": [], "status_in_keystores": [{"keystore": {"id": "ks1", "name": "aws", "type": "aws_kms"}, "status": "active", "keystore_sync_flag": "ok", "keystore_sync_flag_detail": "active_key_is_active_in_keystore"}]}`,
+		id, version, state)
+}
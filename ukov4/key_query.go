@@ -0,0 +1,753 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/go-openapi/strfmt"
+)
+
+// QueryManagedKeys : List managed keys matching a query
+// Compiles the query with ParseManagedKeyQuery, lists the managed keys using the server-side filters of the query and
+// returns those that also match its client-side predicates.
+func (uko *UkoV4) QueryManagedKeys(queryManagedKeysOptions *QueryManagedKeysOptions) (result []ManagedKey, err error) {
+	return uko.QueryManagedKeysWithContext(context.Background(), queryManagedKeysOptions)
+}
+
+// QueryManagedKeysWithContext is an alternate form of the QueryManagedKeys method which supports a Context parameter
+func (uko *UkoV4) QueryManagedKeysWithContext(ctx context.Context, queryManagedKeysOptions *QueryManagedKeysOptions) (result []ManagedKey, err error) {
+	err = core.ValidateNotNil(queryManagedKeysOptions, "queryManagedKeysOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(queryManagedKeysOptions, "queryManagedKeysOptions")
+	if err != nil {
+		return
+	}
+
+	query, err := ParseManagedKeyQuery(*queryManagedKeysOptions.Query)
+	if err != nil {
+		return
+	}
+	listManagedKeysOptions := query.ListManagedKeysOptions()
+	listManagedKeysOptions.Headers = queryManagedKeysOptions.Headers
+	pager, err := uko.NewManagedKeysPager(listManagedKeysOptions)
+	if err != nil {
+		return
+	}
+	keys, err := pager.GetAllWithContext(ctx)
+	if err != nil {
+		return
+	}
+	result = []ManagedKey{}
+	for i := range keys {
+		if query.Matches(&keys[i]) {
+			result = append(result, keys[i])
+		}
+	}
+	return
+}
+
+// QueryManagedKeysOptions : The QueryManagedKeys options.
+type QueryManagedKeysOptions struct {
+	// The query, for example `state in (active, pre_activation) and expiration_date < 2027-01-01`.
+	Query *string `json:"query" validate:"required,ne="`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewQueryManagedKeysOptions : Instantiate QueryManagedKeysOptions
+func (*UkoV4) NewQueryManagedKeysOptions(query string) *QueryManagedKeysOptions {
+	return &QueryManagedKeysOptions{
+		Query: core.StringPtr(query),
+	}
+}
+
+// SetQuery : Allow user to set Query
+func (_options *QueryManagedKeysOptions) SetQuery(query string) *QueryManagedKeysOptions {
+	_options.Query = core.StringPtr(query)
+	return _options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *QueryManagedKeysOptions) SetHeaders(param map[string]string) *QueryManagedKeysOptions {
+	options.Headers = param
+	return options
+}
+
+// ManagedKeyQuery : A compiled managed key query.
+//
+// A query is a list of predicates joined with `and`. Each predicate compares a field with a value using one of the
+// operators =, !=, <, <=, >, >= or in, for example `state in (active, pre_activation) and algorithm = aes and
+// expiration_date < 2027-01-01 and template.name = "prod-aes"`. Values containing spaces or operator characters must be
+// double-quoted. The supported fields are algorithm, state, label, size, vault.id, activation_date, expiration_date,
+// deactivation_date, created_at, updated_at, rotated_at, referenced_keystores.type, referenced_keystores.name,
+// instances.keystore.type, template.id, template.name, template.type, managing_systems, and `tags.<name>` and
+// `label_tags.<name>` for the value of a tag.
+//
+// Predicates that the service can evaluate are sent as ListManagedKeysOptions query parameters; the remaining ones, such
+// as tag matching, inequality or a second predicate on the same parameter, are evaluated by Matches.
+type ManagedKeyQuery struct {
+	// The query as written.
+	Query string
+
+	options    *ListManagedKeysOptions
+	clientSide []*keyQueryPredicate
+}
+
+// ManagedKeyQueryError : The error returned for a query that cannot be compiled.
+type ManagedKeyQueryError struct {
+	// The query as written.
+	Query string
+
+	// The byte offset of the problem within the query.
+	Position int
+
+	// A description of the problem.
+	Message string
+}
+
+// Error implements the error interface.
+func (e *ManagedKeyQueryError) Error() string {
+	return fmt.Sprintf("invalid managed key query '%s' at position %d: %s", e.Query, e.Position, e.Message)
+}
+
+// ParseManagedKeyQuery compiles a managed key query. See ManagedKeyQuery for the syntax.
+func ParseManagedKeyQuery(query string) (result *ManagedKeyQuery, err error) {
+	parser := &keyQueryParser{query: query}
+	err = parser.tokenize()
+	if err != nil {
+		return
+	}
+	result = &ManagedKeyQuery{Query: query, options: &ListManagedKeysOptions{}}
+	for {
+		var predicate *keyQueryPredicate
+		predicate, err = parser.parsePredicate()
+		if err != nil {
+			return nil, err
+		}
+		if !predicate.compile(result.options) {
+			if predicate.field.values == nil {
+				return nil, parser.error(predicate.position,
+					fmt.Sprintf("'%s' cannot be evaluated with the other predicates of the query", predicate))
+			}
+			result.clientSide = append(result.clientSide, predicate)
+		} else if predicate.checkClientSide() {
+			if predicate.field.values == nil {
+				return nil, parser.error(predicate.position,
+					fmt.Sprintf("'%s' cannot be evaluated exactly by the service", predicate))
+			}
+			result.clientSide = append(result.clientSide, predicate)
+		}
+
+		token := parser.next()
+		if token.kind == keyQueryTokenEnd {
+			return
+		}
+		if !token.isKeyword("and") {
+			return nil, parser.error(token.position, fmt.Sprintf("expected 'and' but found '%s'", token.text))
+		}
+	}
+}
+
+// ListManagedKeysOptions returns new ListManagedKeysOptions with the server-side filters of the query.
+func (query *ManagedKeyQuery) ListManagedKeysOptions() *ListManagedKeysOptions {
+	options := *query.options
+	return &options
+}
+
+// ClientSidePredicates returns the predicates of the query that are evaluated by Matches rather than by the service.
+func (query *ManagedKeyQuery) ClientSidePredicates() (predicates []string) {
+	for _, predicate := range query.clientSide {
+		predicates = append(predicates, predicate.String())
+	}
+	return
+}
+
+// Matches returns true if the managed key satisfies the client-side predicates of the query. Keys returned by
+// ListManagedKeys with the query's ListManagedKeysOptions already satisfy its server-side predicates.
+func (query *ManagedKeyQuery) Matches(key *ManagedKey) bool {
+	for _, predicate := range query.clientSide {
+		if !predicate.matches(key) {
+			return false
+		}
+	}
+	return true
+}
+
+// Kinds of values of managed key query fields.
+const (
+	keyQueryString = iota
+	keyQueryNumber
+	keyQueryDate
+	keyQueryDateTime
+)
+
+// keyQueryField describes a field of the managed key query language: the query parameters that filter it on the
+// service, and how to read its values from a managed key. Any of these may be missing.
+type keyQueryField struct {
+	kind int
+
+	// The query parameters for equality (or membership, if the parameter is a list) and for bounds.
+	eq, min, max string
+
+	// Whether the bounds are exclusive ("after" and "before") rather than inclusive ("at or after", "at or before").
+	exclusive bool
+
+	// Whether the equality parameter matches prefixes, so that equality must also be checked client-side.
+	prefix bool
+
+	values func(key *ManagedKey) []string
+}
+
+var keyQueryFields = map[string]keyQueryField{
+	"algorithm": {eq: "algorithm", values: func(key *ManagedKey) []string { return keyQueryStrings(key.Algorithm) }},
+	"state":     {eq: "state", values: func(key *ManagedKey) []string { return keyQueryStrings(key.State) }},
+	"label":     {eq: "label", values: func(key *ManagedKey) []string { return keyQueryStrings(key.Label) }},
+	"size": {kind: keyQueryNumber, eq: "size", min: "size_min", max: "size_max",
+		values: func(key *ManagedKey) []string { return keyQueryStrings(key.Size) }},
+	"vault.id": {eq: "vault.id", values: func(key *ManagedKey) []string {
+		if key.Vault == nil {
+			return nil
+		}
+		return keyQueryStrings(key.Vault.ID)
+	}},
+	"activation_date": {kind: keyQueryDate, eq: "activation_date", min: "activation_date_min", max: "activation_date_max",
+		values: func(key *ManagedKey) []string {
+			return keyQueryStrings(core.StringPtr(dateNilMapper(key.ActivationDate)))
+		}},
+	"expiration_date": {kind: keyQueryDate, eq: "expiration_date", min: "expiration_date_min", max: "expiration_date_max",
+		values: func(key *ManagedKey) []string {
+			return keyQueryStrings(core.StringPtr(dateNilMapper(key.ExpirationDate)))
+		}},
+	// The deactivation date is the service's alias for the expiration date.
+	"deactivation_date": {kind: keyQueryDate, eq: "deactivation_date", min: "deactivation_date_min", max: "deactivation_date_max",
+		values: func(key *ManagedKey) []string {
+			return keyQueryStrings(core.StringPtr(dateNilMapper(key.ExpirationDate)))
+		}},
+	"created_at": {kind: keyQueryDateTime, eq: "created_at", min: "created_at_min", max: "created_at_max",
+		values: func(key *ManagedKey) []string { return keyQueryDateTimeValues(key.CreatedAt) }},
+	"updated_at": {kind: keyQueryDateTime, eq: "updated_at", min: "updated_at_min", max: "updated_at_max", exclusive: true,
+		values: func(key *ManagedKey) []string { return keyQueryDateTimeValues(key.UpdatedAt) }},
+	"rotated_at": {kind: keyQueryDateTime, min: "rotated_at_min", max: "rotated_at_max", exclusive: true,
+		values: func(key *ManagedKey) []string { return keyQueryDateTimeValues(key.RotatedAt) }},
+	"referenced_keystores.type": {eq: "referenced_keystores[].type", values: func(key *ManagedKey) (values []string) {
+		for _, keystore := range key.ReferencedKeystores {
+			values = append(values, keyQueryStrings(keystore.Type)...)
+		}
+		return
+	}},
+	"referenced_keystores.name": {eq: "referenced_keystores[].name", values: func(key *ManagedKey) (values []string) {
+		for _, keystore := range key.ReferencedKeystores {
+			values = append(values, keyQueryStrings(keystore.Name)...)
+		}
+		return
+	}},
+	"instances.keystore.type": {eq: "instances[].keystore.type", values: func(key *ManagedKey) (values []string) {
+		for _, instance := range key.Instances {
+			if keystore := keyInstanceKeystore(instance); keystore != nil {
+				values = append(values, keyQueryStrings(keystore.Type)...)
+			}
+		}
+		return
+	}},
+	"template.id": {eq: "template.id", values: func(key *ManagedKey) []string {
+		if key.Template == nil {
+			return nil
+		}
+		return keyQueryStrings(key.Template.ID)
+	}},
+	"template.name": {eq: "template.name", prefix: true, values: func(key *ManagedKey) []string {
+		if key.Template == nil {
+			return nil
+		}
+		return keyQueryStrings(key.Template.Name)
+	}},
+	"template.type": {eq: "template.type[]", values: func(key *ManagedKey) []string {
+		if key.Template == nil {
+			return nil
+		}
+		return key.Template.Type
+	}},
+	"managing_systems": {eq: "managing_systems", values: func(key *ManagedKey) []string { return key.ManagingSystems }},
+}
+
+// keyQueryTagField returns the field for the value of a tag, which is always evaluated client-side.
+func keyQueryTagField(name string, labelTags bool) keyQueryField {
+	return keyQueryField{values: func(key *ManagedKey) (values []string) {
+		tags := key.Tags
+		if labelTags {
+			tags = key.LabelTags
+		}
+		for _, tag := range tags {
+			if core.StringNilMapper(tag.Name) == name {
+				values = append(values, keyQueryStrings(tag.Value)...)
+			}
+		}
+		return
+	}}
+}
+
+func keyQueryStrings(s *string) []string {
+	if s == nil || *s == "" {
+		return nil
+	}
+	return []string{*s}
+}
+
+func keyQueryDateTimeValues(t *strfmt.DateTime) []string {
+	if t == nil {
+		return nil
+	}
+	return []string{t.String()}
+}
+
+// keyQueryParam returns a pointer to the field of the options for the specified query parameter, either a **string or
+// a *[]string.
+func keyQueryParam(options *ListManagedKeysOptions, name string) interface{} {
+	switch name {
+	case "algorithm":
+		return &options.Algorithm
+	case "state":
+		return &options.State
+	case "label":
+		return &options.Label
+	case "size":
+		return &options.Size
+	case "size_min":
+		return &options.SizeMin
+	case "size_max":
+		return &options.SizeMax
+	case "vault.id":
+		return &options.VaultID
+	case "activation_date":
+		return &options.ActivationDate
+	case "activation_date_min":
+		return &options.ActivationDateMin
+	case "activation_date_max":
+		return &options.ActivationDateMax
+	case "expiration_date":
+		return &options.ExpirationDate
+	case "expiration_date_min":
+		return &options.ExpirationDateMin
+	case "expiration_date_max":
+		return &options.ExpirationDateMax
+	case "deactivation_date":
+		return &options.DeactivationDate
+	case "deactivation_date_min":
+		return &options.DeactivationDateMin
+	case "deactivation_date_max":
+		return &options.DeactivationDateMax
+	case "created_at":
+		return &options.CreatedAt
+	case "created_at_min":
+		return &options.CreatedAtMin
+	case "created_at_max":
+		return &options.CreatedAtMax
+	case "updated_at":
+		return &options.UpdatedAt
+	case "updated_at_min":
+		return &options.UpdatedAtMin
+	case "updated_at_max":
+		return &options.UpdatedAtMax
+	case "rotated_at_min":
+		return &options.RotatedAtMin
+	case "rotated_at_max":
+		return &options.RotatedAtMax
+	case "referenced_keystores[].type":
+		return &options.ReferencedKeystoresType
+	case "referenced_keystores[].name":
+		return &options.ReferencedKeystoresName
+	case "instances[].keystore.type":
+		return &options.InstancesKeystoreType
+	case "template.id":
+		return &options.TemplateID
+	case "template.name":
+		return &options.TemplateName
+	case "template.type[]":
+		return &options.TemplateType
+	case "managing_systems":
+		return &options.ManagingSystems
+	}
+	return nil
+}
+
+func isKeyQueryParamSet(options *ListManagedKeysOptions, name string) bool {
+	switch param := keyQueryParam(options, name).(type) {
+	case **string:
+		return *param != nil
+	case *[]string:
+		return *param != nil
+	}
+	return false
+}
+
+// isKeyQueryConflict returns true if the query parameter, which is not set yet, cannot be used with any query parameter
+// already set.
+func isKeyQueryConflict(options *ListManagedKeysOptions, name string) bool {
+	return findFilterConflict(listManagedKeysConflicts, func(param string) bool {
+		return param == name || isKeyQueryParamSet(options, param)
+	}, name) != nil
+}
+
+// keyQueryPredicate is a single comparison of a managed key query.
+type keyQueryPredicate struct {
+	name     string
+	field    keyQueryField
+	op       string
+	values   []string
+	position int
+}
+
+// String returns the predicate in query syntax.
+func (predicate *keyQueryPredicate) String() string {
+	quoted := make([]string, len(predicate.values))
+	for i, value := range predicate.values {
+		quoted[i] = value
+		if strings.IndexFunc(value, func(r rune) bool { return !isKeyQueryWordRune(r) }) >= 0 || value == "" {
+			quoted[i] = strconv.Quote(value)
+		}
+	}
+	if predicate.op == "in" {
+		return fmt.Sprintf("%s in (%s)", predicate.name, strings.Join(quoted, ", "))
+	}
+	return fmt.Sprintf("%s %s %s", predicate.name, predicate.op, quoted[0])
+}
+
+// compile sets the query parameter of the options that evaluates the predicate, returning false if there is no such
+// parameter or it is already in use.
+func (predicate *keyQueryPredicate) compile(options *ListManagedKeysOptions) bool {
+	var name string
+	switch predicate.op {
+	case "=", "in":
+		name = predicate.field.eq
+	case ">", ">=":
+		name = predicate.field.min
+	case "<", "<=":
+		name = predicate.field.max
+	}
+	if name == "" || isKeyQueryParamSet(options, name) || isKeyQueryConflict(options, name) {
+		return false
+	}
+	switch param := keyQueryParam(options, name).(type) {
+	case **string:
+		if len(predicate.values) != 1 {
+			return false
+		}
+		value := predicate.values[0]
+		if predicate.field.exclusive && (predicate.op == ">=" || predicate.op == "<=") {
+			// Widen the exclusive bound so that keys at the value itself are returned; checkClientSide then applies
+			// the inclusive bound.
+			t, err := parseKeyQueryTime(value)
+			if err != nil {
+				return false
+			}
+			if predicate.op == ">=" {
+				t = t.Add(-time.Second)
+			} else {
+				t = t.Add(time.Second)
+			}
+			value = t.UTC().Format(time.RFC3339Nano)
+		}
+		*param = core.StringPtr(value)
+	case *[]string:
+		*param = append([]string{}, predicate.values...)
+	}
+	return true
+}
+
+// checkClientSide returns true if a predicate compiled into a query parameter must also be evaluated client-side,
+// because the query parameter is less strict than the predicate: an inclusive bound for '<' or '>', a widened
+// exclusive bound for '<=' or '>=', or a prefix match for '='.
+func (predicate *keyQueryPredicate) checkClientSide() bool {
+	switch predicate.op {
+	case "<", ">":
+		return !predicate.field.exclusive
+	case "<=", ">=":
+		return predicate.field.exclusive
+	}
+	return predicate.field.prefix
+}
+
+// matches returns true if the managed key satisfies the predicate. A key without a value for the field only satisfies
+// '!='.
+func (predicate *keyQueryPredicate) matches(key *ManagedKey) bool {
+	actual := predicate.field.values(key)
+	if predicate.op == "!=" {
+		for _, value := range actual {
+			if predicate.compare(value, predicate.values[0]) == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	for _, value := range actual {
+		for _, expected := range predicate.values {
+			c := predicate.compare(value, expected)
+			switch predicate.op {
+			case "=", "in":
+				if c == 0 {
+					return true
+				}
+			case "<":
+				if c < 0 {
+					return true
+				}
+			case "<=":
+				if c <= 0 {
+					return true
+				}
+			case ">":
+				if c > 0 {
+					return true
+				}
+			case ">=":
+				if c >= 0 {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// compare compares two values of the predicate's field, returning -1, 0 or 1. Values that cannot be parsed are
+// compared as strings.
+func (predicate *keyQueryPredicate) compare(a string, b string) int {
+	switch predicate.field.kind {
+	case keyQueryNumber:
+		x, errX := strconv.ParseInt(a, 10, 64)
+		y, errY := strconv.ParseInt(b, 10, 64)
+		if errX == nil && errY == nil {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	case keyQueryDateTime:
+		x, errX := parseKeyQueryTime(a)
+		y, errY := parseKeyQueryTime(b)
+		if errX == nil && errY == nil {
+			switch {
+			case x.Before(y):
+				return -1
+			case x.After(y):
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+// parseKeyQueryTime parses a date-time in RFC 3339 format, or a date which stands for midnight UTC.
+func parseKeyQueryTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
+
+// validate checks the operator and values of the predicate against the kind of its field.
+func (predicate *keyQueryPredicate) validate() string {
+	if predicate.field.kind == keyQueryString && predicate.op != "=" && predicate.op != "!=" && predicate.op != "in" {
+		return fmt.Sprintf("operator '%s' cannot be used with '%s'", predicate.op, predicate.name)
+	}
+	for _, value := range predicate.values {
+		var err error
+		switch predicate.field.kind {
+		case keyQueryNumber:
+			_, err = strconv.ParseInt(value, 10, 64)
+		case keyQueryDate:
+			_, err = time.Parse("2006-01-02", value)
+		case keyQueryDateTime:
+			_, err = parseKeyQueryTime(value)
+		}
+		if err != nil {
+			return fmt.Sprintf("invalid value '%s' for '%s'", value, predicate.name)
+		}
+	}
+	return ""
+}
+
+// Kinds of managed key query tokens.
+const (
+	keyQueryTokenEnd = iota
+	keyQueryTokenWord
+	keyQueryTokenString
+	keyQueryTokenSymbol
+)
+
+type keyQueryToken struct {
+	kind     int
+	text     string
+	position int
+}
+
+func (token keyQueryToken) isKeyword(keyword string) bool {
+	return token.kind == keyQueryTokenWord && strings.EqualFold(token.text, keyword)
+}
+
+func (token keyQueryToken) isSymbol(symbol string) bool {
+	return token.kind == keyQueryTokenSymbol && token.text == symbol
+}
+
+func isKeyQueryWordRune(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' ||
+		r == '_' || r == '.' || r == '-' || r == ':' || r == '+'
+}
+
+type keyQueryParser struct {
+	query  string
+	tokens []keyQueryToken
+	pos    int
+}
+
+func (parser *keyQueryParser) error(position int, message string) error {
+	return &ManagedKeyQueryError{Query: parser.query, Position: position, Message: message}
+}
+
+func (parser *keyQueryParser) tokenize() error {
+	query := parser.query
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '"':
+			j := i + 1
+			for ; j < len(query) && query[j] != '"'; j++ {
+				if query[j] == '\\' {
+					j++
+				}
+			}
+			if j >= len(query) {
+				return parser.error(i, "unterminated string")
+			}
+			text, err := strconv.Unquote(query[i : j+1])
+			if err != nil {
+				return parser.error(i, "invalid string")
+			}
+			parser.tokens = append(parser.tokens, keyQueryToken{keyQueryTokenString, text, i})
+			i = j + 1
+		case strings.HasPrefix(query[i:], "!=") || strings.HasPrefix(query[i:], "<=") || strings.HasPrefix(query[i:], ">="):
+			parser.tokens = append(parser.tokens, keyQueryToken{keyQueryTokenSymbol, query[i : i+2], i})
+			i += 2
+		case strings.IndexByte("=<>(),", c) >= 0:
+			parser.tokens = append(parser.tokens, keyQueryToken{keyQueryTokenSymbol, query[i : i+1], i})
+			i++
+		case isKeyQueryWordRune(rune(c)):
+			j := i
+			for j < len(query) && isKeyQueryWordRune(rune(query[j])) {
+				j++
+			}
+			parser.tokens = append(parser.tokens, keyQueryToken{keyQueryTokenWord, query[i:j], i})
+			i = j
+		default:
+			return parser.error(i, fmt.Sprintf("unexpected character '%c'", c))
+		}
+	}
+	parser.tokens = append(parser.tokens, keyQueryToken{keyQueryTokenEnd, "end of query", len(query)})
+	return nil
+}
+
+func (parser *keyQueryParser) next() keyQueryToken {
+	token := parser.tokens[parser.pos]
+	if token.kind != keyQueryTokenEnd {
+		parser.pos++
+	}
+	return token
+}
+
+func (parser *keyQueryParser) value() (string, error) {
+	token := parser.next()
+	if token.kind != keyQueryTokenWord && token.kind != keyQueryTokenString {
+		return "", parser.error(token.position, fmt.Sprintf("expected a value but found '%s'", token.text))
+	}
+	return token.text, nil
+}
+
+func (parser *keyQueryParser) parsePredicate() (predicate *keyQueryPredicate, err error) {
+	token := parser.next()
+	if token.kind != keyQueryTokenWord {
+		return nil, parser.error(token.position, fmt.Sprintf("expected a field but found '%s'", token.text))
+	}
+	if token.isKeyword("or") || token.isKeyword("not") {
+		return nil, parser.error(token.position, fmt.Sprintf("'%s' is not supported", token.text))
+	}
+	predicate = &keyQueryPredicate{name: token.text, position: token.position}
+	field, ok := keyQueryFields[token.text]
+	switch {
+	case ok:
+		predicate.field = field
+	case strings.HasPrefix(token.text, "tags.") && len(token.text) > len("tags."):
+		predicate.field = keyQueryTagField(strings.TrimPrefix(token.text, "tags."), false)
+	case strings.HasPrefix(token.text, "label_tags.") && len(token.text) > len("label_tags."):
+		predicate.field = keyQueryTagField(strings.TrimPrefix(token.text, "label_tags."), true)
+	default:
+		return nil, parser.error(token.position, fmt.Sprintf("unknown field '%s'", token.text))
+	}
+
+	token = parser.next()
+	switch {
+	case token.isKeyword("in"):
+		predicate.op = "in"
+		if open := parser.next(); !open.isSymbol("(") {
+			return nil, parser.error(open.position, fmt.Sprintf("expected '(' but found '%s'", open.text))
+		}
+		for {
+			var value string
+			value, err = parser.value()
+			if err != nil {
+				return nil, err
+			}
+			predicate.values = append(predicate.values, value)
+			separator := parser.next()
+			if separator.isSymbol(")") {
+				break
+			}
+			if !separator.isSymbol(",") {
+				return nil, parser.error(separator.position, fmt.Sprintf("expected ',' or ')' but found '%s'", separator.text))
+			}
+		}
+	case token.kind == keyQueryTokenSymbol && token.text != "(" && token.text != ")" && token.text != ",":
+		predicate.op = token.text
+		var value string
+		value, err = parser.value()
+		if err != nil {
+			return nil, err
+		}
+		predicate.values = []string{value}
+	default:
+		return nil, parser.error(token.position, fmt.Sprintf("expected an operator but found '%s'", token.text))
+	}
+
+	if message := predicate.validate(); message != "" {
+		return nil, parser.error(predicate.position, message)
+	}
+	return
+}
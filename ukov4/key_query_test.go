@@ -0,0 +1,146 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-hpcs-uko-sdk/ukov4"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`ManagedKeyQuery`, func() {
+	It(`Compile server-side filters and client-side predicates`, func() {
+		query, err := ukov4.ParseManagedKeyQuery(`state in (active, pre_activation) and algorithm = aes and expiration_date < 2027-01-01 and template.name = "prod-aes" and tags.env != dev`)
+		Expect(err).To(BeNil())
+
+		options := query.ListManagedKeysOptions()
+		Expect(options.State).To(Equal([]string{"active", "pre_activation"}))
+		Expect(options.Algorithm).To(Equal([]string{"aes"}))
+		Expect(*options.ExpirationDateMax).To(Equal("2027-01-01"))
+		Expect(*options.TemplateName).To(Equal("prod-aes"))
+		Expect(query.ClientSidePredicates()).To(Equal([]string{
+			"expiration_date < 2027-01-01", "template.name = prod-aes", "tags.env != dev",
+		}))
+
+		key := &ukov4.ManagedKey{
+			Template:       &ukov4.TemplateReference{Name: core.StringPtr("prod-aes")},
+			ExpirationDate: CreateMockDate("2026-06-30"),
+			Tags:           []ukov4.Tag{{Name: core.StringPtr("env"), Value: core.StringPtr("prod")}},
+		}
+		Expect(query.Matches(key)).To(BeTrue())
+		key.Template.Name = core.StringPtr("prod-aes-2")
+		Expect(query.Matches(key)).To(BeFalse())
+		key.Template.Name = core.StringPtr("prod-aes")
+		key.ExpirationDate = CreateMockDate("2027-01-01")
+		Expect(query.Matches(key)).To(BeFalse())
+	})
+	It(`Fall back to client-side filtering for conflicting parameters`, func() {
+		query, err := ukov4.ParseManagedKeyQuery(`expiration_date >= 2026-01-01 and expiration_date <= 2026-12-31 and size > 128 and size = 256`)
+		Expect(err).To(BeNil())
+		options := query.ListManagedKeysOptions()
+		Expect(*options.ExpirationDateMin).To(Equal("2026-01-01"))
+		Expect(*options.ExpirationDateMax).To(Equal("2026-12-31"))
+		Expect(*options.SizeMin).To(Equal("128"))
+		Expect(options.Size).To(BeNil())
+		Expect(query.ClientSidePredicates()).To(Equal([]string{"size > 128", "size = 256"}))
+	})
+	It(`Recheck strict date bounds client-side`, func() {
+		query, err := ukov4.ParseManagedKeyQuery(`deactivation_date < 2027-01-01`)
+		Expect(err).To(BeNil())
+		Expect(*query.ListManagedKeysOptions().DeactivationDateMax).To(Equal("2027-01-01"))
+		Expect(query.ClientSidePredicates()).To(Equal([]string{"deactivation_date < 2027-01-01"}))
+		key := &ukov4.ManagedKey{ExpirationDate: CreateMockDate("2027-01-01")}
+		Expect(query.Matches(key)).To(BeFalse())
+		key.ExpirationDate = CreateMockDate("2026-12-31")
+		Expect(query.Matches(key)).To(BeTrue())
+
+		query, err = ukov4.ParseManagedKeyQuery(`deactivation_date = 2026-01-01 and deactivation_date != 2026-02-01`)
+		Expect(err).To(BeNil())
+		Expect(query.ClientSidePredicates()).To(Equal([]string{"deactivation_date != 2026-02-01"}))
+	})
+	It(`Honour the exclusive rotated_at bounds of the service`, func() {
+		rotatedAt := CreateMockDateTime("2026-03-01T10:00:00Z")
+		key := &ukov4.ManagedKey{RotatedAt: rotatedAt}
+
+		query, err := ukov4.ParseManagedKeyQuery(`rotated_at > 2026-03-01T10:00:00Z`)
+		Expect(err).To(BeNil())
+		Expect(*query.ListManagedKeysOptions().RotatedAtMin).To(Equal("2026-03-01T10:00:00Z"))
+		Expect(query.ClientSidePredicates()).To(BeEmpty())
+
+		query, err = ukov4.ParseManagedKeyQuery(`rotated_at >= 2026-03-01T10:00:00Z and rotated_at <= 2026-03-01T10:00:00Z`)
+		Expect(err).To(BeNil())
+		options := query.ListManagedKeysOptions()
+		Expect(*options.RotatedAtMin).To(Equal("2026-03-01T09:59:59Z"))
+		Expect(*options.RotatedAtMax).To(Equal("2026-03-01T10:00:01Z"))
+		Expect(query.ClientSidePredicates()).To(Equal([]string{
+			"rotated_at >= 2026-03-01T10:00:00Z", "rotated_at <= 2026-03-01T10:00:00Z",
+		}))
+		Expect(query.Matches(key)).To(BeTrue())
+		key.RotatedAt = CreateMockDateTime("2026-03-01T10:00:00.5Z")
+		Expect(query.Matches(key)).To(BeFalse())
+	})
+	It(`Reject invalid queries (negative test)`, func() {
+		for _, text := range []string{
+			``, `state`, `state = `, `color = red`, `state < active`, `size = big`, `expiration_date > tomorrow`,
+			`state = active or state = destroyed`, `state in (active`, `label = "unterminated`, `state = active state = destroyed`,
+			`rotated_at >= yesterday`,
+		} {
+			_, err := ukov4.ParseManagedKeyQuery(text)
+			Expect(err).To(BeAssignableToTypeOf(&ukov4.ManagedKeyQueryError{}), text)
+		}
+	})
+
+	Describe(`QueryManagedKeys`, func() {
+		var testServer *httptest.Server
+		BeforeEach(func() {
+			testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				defer GinkgoRecover()
+
+				Expect(req.URL.EscapedPath()).To(Equal("/api/v4/managed_keys"))
+				Expect(req.URL.Query().Get("state")).To(Equal("active"))
+				res.Header().Set("Content-type", "application/json")
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"total_count": 2, "limit": 2, "offset": 0, "managed_keys": [`+
+					`{"id": "k1", "label": "A", "state": "active", "algorithm": "aes", "label_tags": [{"name": "app", "value": "billing"}], "referenced_keystores": [], "instances": [], "status_in_keystores": []},`+
+					`{"id": "k2", "label": "B", "state": "active", "algorithm": "aes", "label_tags": [{"name": "app", "value": "hr"}], "referenced_keystores": [], "instances": [], "status_in_keystores": []}]}`)
+			}))
+		})
+		AfterEach(func() {
+			testServer.Close()
+		})
+		It(`Apply client-side predicates to the listed keys`, func() {
+			ukoService, serviceErr := ukov4.NewUkoV4(&ukov4.UkoV4Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+
+			_, err := ukoService.QueryManagedKeys(nil)
+			Expect(err).ToNot(BeNil())
+
+			keys, err := ukoService.QueryManagedKeys(ukoService.NewQueryManagedKeysOptions(`state = active and label_tags.app = billing`))
+			Expect(err).To(BeNil())
+			Expect(keys).To(HaveLen(1))
+			Expect(*keys[0].ID).To(Equal("k1"))
+		})
+	})
+})
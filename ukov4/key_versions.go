@@ -0,0 +1,308 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/go-openapi/strfmt"
+)
+
+// GetManagedKeyVersionHistory : Retrieve the version timeline of a managed key
+// Lists all versions of the managed key and returns them ordered from the oldest to the newest version.
+func (uko *UkoV4) GetManagedKeyVersionHistory(getManagedKeyVersionHistoryOptions *GetManagedKeyVersionHistoryOptions) (result *ManagedKeyVersionHistory, err error) {
+	return uko.GetManagedKeyVersionHistoryWithContext(context.Background(), getManagedKeyVersionHistoryOptions)
+}
+
+// GetManagedKeyVersionHistoryWithContext is an alternate form of the GetManagedKeyVersionHistory method which supports a Context parameter
+func (uko *UkoV4) GetManagedKeyVersionHistoryWithContext(ctx context.Context, getManagedKeyVersionHistoryOptions *GetManagedKeyVersionHistoryOptions) (result *ManagedKeyVersionHistory, err error) {
+	err = core.ValidateNotNil(getManagedKeyVersionHistoryOptions, "getManagedKeyVersionHistoryOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(getManagedKeyVersionHistoryOptions, "getManagedKeyVersionHistoryOptions")
+	if err != nil {
+		return
+	}
+
+	pager, err := uko.NewManagedKeyVersionsPager(&ListManagedKeyVersionsOptions{ID: getManagedKeyVersionHistoryOptions.ID})
+	if err != nil {
+		return
+	}
+	keys, err := pager.GetAllWithContext(ctx)
+	if err != nil {
+		return
+	}
+
+	result = &ManagedKeyVersionHistory{
+		KeyID:    getManagedKeyVersionHistoryOptions.ID,
+		Versions: make([]ManagedKeyVersion, 0, len(keys)),
+	}
+	for i := range keys {
+		result.Versions = append(result.Versions, NewManagedKeyVersion(&keys[i]))
+	}
+	sort.SliceStable(result.Versions, func(i, j int) bool {
+		return versionNumber(result.Versions[i].Version) < versionNumber(result.Versions[j].Version)
+	})
+	return
+}
+
+// versionNumber returns the specified version number, ordering unknown versions first.
+func versionNumber(version *int64) int64 {
+	if version == nil {
+		return -1
+	}
+	return *version
+}
+
+// GetManagedKeyVersionHistoryOptions : The GetManagedKeyVersionHistory options.
+type GetManagedKeyVersionHistoryOptions struct {
+	// UUID of the key.
+	ID *string `json:"id" validate:"required,ne="`
+}
+
+// NewGetManagedKeyVersionHistoryOptions : Instantiate GetManagedKeyVersionHistoryOptions
+func (*UkoV4) NewGetManagedKeyVersionHistoryOptions(id string) *GetManagedKeyVersionHistoryOptions {
+	return &GetManagedKeyVersionHistoryOptions{
+		ID: core.StringPtr(id),
+	}
+}
+
+// SetID : Allow user to set ID
+func (_options *GetManagedKeyVersionHistoryOptions) SetID(id string) *GetManagedKeyVersionHistoryOptions {
+	_options.ID = core.StringPtr(id)
+	return _options
+}
+
+// ManagedKeyVersionHistory : The versions of a managed key, ordered from the oldest to the newest.
+type ManagedKeyVersionHistory struct {
+	// UUID of the key.
+	KeyID *string `json:"key_id"`
+
+	// The versions of the key.
+	Versions []ManagedKeyVersion `json:"versions"`
+}
+
+// GetVersion returns the entry for the specified version number, or nil if there is none.
+func (history *ManagedKeyVersionHistory) GetVersion(version int64) *ManagedKeyVersion {
+	for i := range history.Versions {
+		if history.Versions[i].Version != nil && *history.Versions[i].Version == version {
+			return &history.Versions[i]
+		}
+	}
+	return nil
+}
+
+// DiffVersions reports the field-level changes between versions a and b of the key.
+func (history *ManagedKeyVersionHistory) DiffVersions(a int64, b int64) (changes []ManagedKeyVersionChange, err error) {
+	versionA := history.GetVersion(a)
+	if versionA == nil {
+		err = fmt.Errorf("version %d of managed key '%s' not found", a, core.StringNilMapper(history.KeyID))
+		return
+	}
+	versionB := history.GetVersion(b)
+	if versionB == nil {
+		err = fmt.Errorf("version %d of managed key '%s' not found", b, core.StringNilMapper(history.KeyID))
+		return
+	}
+	return DiffManagedKeyVersions(versionA, versionB)
+}
+
+// ManagedKeyVersion : A single entry in the version timeline of a managed key.
+type ManagedKeyVersion struct {
+	// The v4 UUID of this version of the key.
+	ID *string `json:"id,omitempty"`
+
+	// The version number.
+	Version *int64 `json:"version,omitempty"`
+
+	// The state of the key.
+	State *string `json:"state,omitempty"`
+
+	// First day when the key is active.
+	ActivationDate *strfmt.Date `json:"activation_date,omitempty"`
+
+	// Last day when the key is active.
+	ExpirationDate *strfmt.Date `json:"expiration_date,omitempty"`
+
+	// Date and time when the key was created.
+	CreatedAt *strfmt.DateTime `json:"created_at,omitempty"`
+
+	// Date and time when the key was last updated.
+	UpdatedAt *strfmt.DateTime `json:"updated_at,omitempty"`
+
+	// Date and time when the key was rotated.
+	RotatedAt *strfmt.DateTime `json:"rotated_at,omitempty"`
+
+	// A list of verification patterns of the key (e.g. public key hash for RSA keys).
+	VerificationPatterns []KeyVerificationPattern `json:"verification_patterns,omitempty"`
+
+	// key instances.
+	Instances []KeyInstanceIntf `json:"instances,omitempty"`
+}
+
+// NewManagedKeyVersion returns the version timeline entry of the specified managed key.
+func NewManagedKeyVersion(key *ManagedKey) ManagedKeyVersion {
+	return ManagedKeyVersion{
+		ID:                   key.ID,
+		Version:              key.Version,
+		State:                key.State,
+		ActivationDate:       key.ActivationDate,
+		ExpirationDate:       key.ExpirationDate,
+		CreatedAt:            key.CreatedAt,
+		UpdatedAt:            key.UpdatedAt,
+		RotatedAt:            key.RotatedAt,
+		VerificationPatterns: key.VerificationPatterns,
+		Instances:            key.Instances,
+	}
+}
+
+// ManagedKeyVersionChange : A field that differs between two versions of a managed key.
+type ManagedKeyVersionChange struct {
+	// The path of the field: its JSON name or, for a field of a verification pattern or key instance, a path such as
+	// "verification_patterns[<method>].value" or "instances[<id>].label_in_keystore".
+	Field string `json:"field"`
+
+	// The JSON value of the field in the first version, or "" if it is not set.
+	Old string `json:"old"`
+
+	// The JSON value of the field in the second version, or "" if it is not set.
+	New string `json:"new"`
+}
+
+// DiffManagedKeyVersions reports the field-level changes between two versions of a managed key. Fields are compared by
+// their JSON representation and reported in the order they are declared in ManagedKeyVersion. Verification patterns
+// are matched by method and key instances by ID, and each of their fields is compared on its own.
+func DiffManagedKeyVersions(a *ManagedKeyVersion, b *ManagedKeyVersion) (changes []ManagedKeyVersionChange, err error) {
+	fieldsA, pathsA, err := versionFields(a)
+	if err != nil {
+		return
+	}
+	fieldsB, pathsB, err := versionFields(b)
+	if err != nil {
+		return
+	}
+	paths := pathsA
+	for _, path := range pathsB {
+		if _, ok := fieldsA[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.SliceStable(paths, func(i, j int) bool {
+		return managedKeyVersionFieldIndex(paths[i]) < managedKeyVersionFieldIndex(paths[j])
+	})
+	for _, path := range paths {
+		if fieldsA[path] != fieldsB[path] {
+			changes = append(changes, ManagedKeyVersionChange{
+				Field: path,
+				Old:   fieldsA[path],
+				New:   fieldsB[path],
+			})
+		}
+	}
+	return
+}
+
+// managedKeyVersionFields lists the fields compared by DiffManagedKeyVersions, in declaration order.
+var managedKeyVersionFields = []string{
+	"id", "version", "state", "activation_date", "expiration_date", "created_at", "updated_at", "rotated_at",
+	"verification_patterns", "instances",
+}
+
+// managedKeyVersionListKeys maps the list fields of a version to the field that identifies their entries.
+var managedKeyVersionListKeys = map[string]string{
+	"verification_patterns": "method",
+	"instances":             "id",
+}
+
+// managedKeyVersionFieldIndex returns the position in managedKeyVersionFields of the field a path starts with.
+func managedKeyVersionFieldIndex(path string) int {
+	field := path
+	if i := strings.IndexAny(path, "[."); i >= 0 {
+		field = path[:i]
+	}
+	for i, name := range managedKeyVersionFields {
+		if name == field {
+			return i
+		}
+	}
+	return len(managedKeyVersionFields)
+}
+
+// versionFields returns the JSON representation of each set field of the version by path, and the paths in order.
+// Entries of the list fields are keyed by their identifying field, or by "#<index>" if it is not set, and nested
+// objects are flattened into one path per field.
+func versionFields(version *ManagedKeyVersion) (fields map[string]string, paths []string, err error) {
+	buffer, err := json.Marshal(version)
+	if err != nil {
+		return
+	}
+	var raw map[string]json.RawMessage
+	err = json.Unmarshal(buffer, &raw)
+	if err != nil {
+		return
+	}
+	fields = make(map[string]string, len(raw))
+	add := func(path string, value json.RawMessage) {
+		fields[path] = string(value)
+		paths = append(paths, path)
+	}
+	for _, name := range managedKeyVersionFields {
+		value, ok := raw[name]
+		if !ok {
+			continue
+		}
+		keyField, isList := managedKeyVersionListKeys[name]
+		var items []map[string]json.RawMessage
+		if !isList || json.Unmarshal(value, &items) != nil {
+			add(name, value)
+			continue
+		}
+		for i, item := range items {
+			key := fmt.Sprintf("#%d", i)
+			var id string
+			if json.Unmarshal(item[keyField], &id) == nil && id != "" {
+				key = id
+			}
+			delete(item, keyField)
+			flattenJSONFields(fmt.Sprintf("%s[%s]", name, key), item, add)
+		}
+	}
+	return
+}
+
+// flattenJSONFields calls add for each field of a JSON object, recursing into nested objects, in field name order.
+func flattenJSONFields(path string, object map[string]json.RawMessage, add func(path string, value json.RawMessage)) {
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var nested map[string]json.RawMessage
+		if json.Unmarshal(object[name], &nested) == nil && nested != nil {
+			flattenJSONFields(path+"."+name, nested, add)
+			continue
+		}
+		add(path+"."+name, object[name])
+	}
+}
@@ -0,0 +1,95 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-hpcs-uko-sdk/ukov4"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`GetManagedKeyVersionHistory`, func() {
+	var testServer *httptest.Server
+
+	BeforeEach(func() {
+		testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+
+			Expect(req.Method).To(Equal("GET"))
+			Expect(req.URL.EscapedPath()).To(Equal("/api/v4/managed_keys/k1/versions"))
+			res.Header().Set("Content-type", "application/json")
+			res.WriteHeader(200)
+			fmt.Fprint(res, `{"total_count": 2, "limit": 2, "offset": 0, "managed_keys": [`+
+				`{"id": "k1", "version": 2, "label": "KEY", "state": "active", "algorithm": "aes", "activation_date": "2023-02-01", "verification_patterns": [{"method": "enc-zero", "value": "bmV3"}, {"method": "sha-256", "value": "c2hh"}], "instances": [{"id": "i1", "label_in_keystore": "KEY-2", "type": "secret_key", "keystore": {"group": "eu", "type": "aws_kms"}}, {"id": "i2", "label_in_keystore": "KEY", "type": "secret_key", "keystore": {"group": "us", "type": "aws_kms"}}], "status_in_keystores": [], "referenced_keystores": []},`+
+				`{"id": "v1", "version": 1, "label": "KEY", "state": "deactivated", "algorithm": "aes", "activation_date": "2023-01-01", "verification_patterns": [{"method": "enc-zero", "value": "b2xk"}], "instances": [{"id": "i1", "label_in_keystore": "KEY", "type": "secret_key", "keystore": {"group": "eu", "type": "aws_kms"}}], "status_in_keystores": [], "referenced_keystores": []}]}`)
+		}))
+	})
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	It(`Return an ordered timeline and diff its versions`, func() {
+		ukoService, serviceErr := ukov4.NewUkoV4(&ukov4.UkoV4Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		_, err := ukoService.GetManagedKeyVersionHistory(nil)
+		Expect(err).ToNot(BeNil())
+
+		history, err := ukoService.GetManagedKeyVersionHistory(ukoService.NewGetManagedKeyVersionHistoryOptions("k1"))
+		Expect(err).To(BeNil())
+		Expect(history.Versions).To(HaveLen(2))
+		Expect(*history.Versions[0].Version).To(Equal(int64(1)))
+		Expect(*history.Versions[1].Version).To(Equal(int64(2)))
+
+		changes, err := history.DiffVersions(1, 2)
+		Expect(err).To(BeNil())
+		fields := []string{}
+		for _, change := range changes {
+			fields = append(fields, change.Field)
+		}
+		Expect(fields).To(Equal([]string{
+			"id", "version", "state", "activation_date",
+			"verification_patterns[enc-zero].value", "verification_patterns[sha-256].value",
+			"instances[i1].label_in_keystore",
+			"instances[i2].keystore.group", "instances[i2].keystore.type", "instances[i2].label_in_keystore",
+			"instances[i2].type",
+		}))
+		Expect(changes[2].Old).To(Equal(`"deactivated"`))
+		Expect(changes[2].New).To(Equal(`"active"`))
+		Expect(changes[5]).To(Equal(ukov4.ManagedKeyVersionChange{
+			Field: "verification_patterns[sha-256].value", Old: "", New: `"c2hh"`}))
+		Expect(changes[6]).To(Equal(ukov4.ManagedKeyVersionChange{
+			Field: "instances[i1].label_in_keystore", Old: `"KEY"`, New: `"KEY-2"`}))
+
+		_, err = history.DiffVersions(1, 3)
+		Expect(err).ToNot(BeNil())
+	})
+	It(`Report no changes between identical versions`, func() {
+		version := ukov4.NewManagedKeyVersion(&ukov4.ManagedKey{Version: core.Int64Ptr(1), State: core.StringPtr("active")})
+		changes, err := ukov4.DiffManagedKeyVersions(&version, &version)
+		Expect(err).To(BeNil())
+		Expect(changes).To(BeEmpty())
+	})
+})
@@ -0,0 +1,609 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4
+
+import (
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+)
+
+// IbmCloudKmsKeystoreCreate : The creation request of an IBM Cloud KMS keystore of the key_protect or hpcs variant.
+type IbmCloudKmsKeystoreCreate = KeystoreCreationRequestKeystoreTypeIbmCloudKmsInternalExternalCreateKeystoreTypeIbmCloudKmsInternalExternalCreateKeystoreTypeIbmCloudKmsCreate
+
+// IbmCloudKmsInternalKeystoreCreate : The creation request of an IBM Cloud KMS keystore of the internal variant.
+type IbmCloudKmsInternalKeystoreCreate = KeystoreCreationRequestKeystoreTypeIbmCloudKmsInternalExternalCreateKeystoreTypeIbmCloudKmsInternalExternalCreateKeystoreTypeIbmCloudKmsInternalCreate
+
+var awsRegionPattern = regexp.MustCompile(`^[a-z]{2}(-gov|-iso[a-z]?)?-[a-z]+-[0-9]+$`)
+
+// KeystoreBuilderError : The error returned by the Build method of a keystore builder when the keystore is incomplete
+// or invalid.
+type KeystoreBuilderError struct {
+	// Type of keystore.
+	Type string
+
+	// The problems found, in the order of the properties of the keystore.
+	Problems []KeystoreBuilderProblem
+}
+
+// KeystoreBuilderProblem : A property of a keystore that is missing or invalid.
+type KeystoreBuilderProblem struct {
+	// The JSON name of the property.
+	Field string
+
+	// A description of the problem.
+	Message string
+}
+
+// Error implements the error interface.
+func (e *KeystoreBuilderError) Error() string {
+	problems := make([]string, len(e.Problems))
+	for i, problem := range e.Problems {
+		problems[i] = fmt.Sprintf("%s %s", problem.Field, problem.Message)
+	}
+	return fmt.Sprintf("invalid %s keystore: %s", e.Type, strings.Join(problems, "; "))
+}
+
+// keystoreBuilder holds the properties shared by all keystore types and the problems found while building.
+type keystoreBuilder struct {
+	keystoreType string
+	vaultID      string
+	name         string
+	description  *string
+	tlsProxy     *string
+	groups       []string
+	problems     []KeystoreBuilderProblem
+}
+
+func (builder *keystoreBuilder) problem(field string, format string, args ...interface{}) {
+	builder.problems = append(builder.problems, KeystoreBuilderProblem{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// require records a problem if the value is not set.
+func (builder *keystoreBuilder) require(field string, value *string) {
+	if value == nil || *value == "" {
+		builder.problem(field, "is required")
+	}
+}
+
+// validateBase resets the problems and checks the vault and, if required, the name.
+func (builder *keystoreBuilder) validateBase(nameRequired bool) {
+	builder.problems = nil
+	if builder.vaultID == "" {
+		builder.problem("vault", "is required")
+	}
+	if nameRequired && builder.name == "" {
+		builder.problem("name", "is required")
+	}
+}
+
+func (builder *keystoreBuilder) err() error {
+	if len(builder.problems) == 0 {
+		return nil
+	}
+	return &KeystoreBuilderError{Type: builder.keystoreType, Problems: builder.problems}
+}
+
+func (builder *keystoreBuilder) vault() *VaultReferenceInCreationRequest {
+	return &VaultReferenceInCreationRequest{ID: core.StringPtr(builder.vaultID)}
+}
+
+func (builder *keystoreBuilder) namePtr() *string {
+	if builder.name == "" {
+		return nil
+	}
+	return core.StringPtr(builder.name)
+}
+
+// AwsKmsKeystoreBuilder : Builds the creation request of an AWS KMS keystore.
+type AwsKmsKeystoreBuilder struct {
+	keystoreBuilder
+	region          *string
+	accessKeyID     *string
+	secretAccessKey *string
+}
+
+// NewAwsKmsKeystore returns a builder for an AWS KMS keystore in the specified vault.
+func NewAwsKmsKeystore(vaultID string, name string) *AwsKmsKeystoreBuilder {
+	return &AwsKmsKeystoreBuilder{keystoreBuilder: keystoreBuilder{
+		keystoreType: KeystoreCreationRequest_Type_AwsKms, vaultID: vaultID, name: name}}
+}
+
+// Description sets the description of the keystore.
+func (builder *AwsKmsKeystoreBuilder) Description(description string) *AwsKmsKeystoreBuilder {
+	builder.description = core.StringPtr(description)
+	return builder
+}
+
+// TlsProxy sets the TLS proxy used to connect to the keystore.
+func (builder *AwsKmsKeystoreBuilder) TlsProxy(tlsProxy string) *AwsKmsKeystoreBuilder {
+	builder.tlsProxy = core.StringPtr(tlsProxy)
+	return builder
+}
+
+// Groups sets the groups the keystore belongs to.
+func (builder *AwsKmsKeystoreBuilder) Groups(groups ...string) *AwsKmsKeystoreBuilder {
+	builder.groups = groups
+	return builder
+}
+
+// Region sets the AWS region, such as "eu-central-1".
+func (builder *AwsKmsKeystoreBuilder) Region(region string) *AwsKmsKeystoreBuilder {
+	builder.region = core.StringPtr(region)
+	return builder
+}
+
+// Credentials sets the access key used to connect to AWS KMS.
+func (builder *AwsKmsKeystoreBuilder) Credentials(accessKeyID string, secretAccessKey string) *AwsKmsKeystoreBuilder {
+	builder.accessKeyID = core.StringPtr(accessKeyID)
+	builder.secretAccessKey = core.StringPtr(secretAccessKey)
+	return builder
+}
+
+// Build validates the keystore and returns its creation request, or a *KeystoreBuilderError.
+func (builder *AwsKmsKeystoreBuilder) Build() (*KeystoreCreationRequestKeystoreTypeAwsKmsCreate, error) {
+	builder.validateBase(true)
+	builder.require("aws_region", builder.region)
+	if builder.region != nil && *builder.region != "" && !awsRegionPattern.MatchString(*builder.region) {
+		builder.problem("aws_region", "'%s' is not an AWS region", *builder.region)
+	}
+	builder.require("aws_access_key_id", builder.accessKeyID)
+	builder.require("aws_secret_access_key", builder.secretAccessKey)
+	if err := builder.err(); err != nil {
+		return nil, err
+	}
+	return &KeystoreCreationRequestKeystoreTypeAwsKmsCreate{
+		Type:               core.StringPtr(builder.keystoreType),
+		Vault:              builder.vault(),
+		Name:               builder.namePtr(),
+		Description:        builder.description,
+		TlsProxy:           builder.tlsProxy,
+		Groups:             builder.groups,
+		AwsRegion:          builder.region,
+		AwsAccessKeyID:     builder.accessKeyID,
+		AwsSecretAccessKey: builder.secretAccessKey,
+	}, nil
+}
+
+// AzureKeystoreBuilder : Builds the creation request of an Azure Key Vault keystore.
+type AzureKeystoreBuilder struct {
+	keystoreBuilder
+	serviceName    *string
+	resourceGroup  *string
+	location       *AzureLocation
+	clientID       *string
+	password       *string
+	tenant         *string
+	subscriptionID *string
+	environment    AzureEnvironment
+}
+
+// NewAzureKeystore returns a builder for an Azure Key Vault keystore in the specified vault. The name is optional; the
+// environment defaults to AzureEnvironment_Azure.
+func NewAzureKeystore(vaultID string, name string) *AzureKeystoreBuilder {
+	return &AzureKeystoreBuilder{
+		keystoreBuilder: keystoreBuilder{
+			keystoreType: KeystoreCreationRequest_Type_AzureKeyVault, vaultID: vaultID, name: name},
+		environment: AzureEnvironment_Azure,
+	}
+}
+
+// Description sets the description of the keystore.
+func (builder *AzureKeystoreBuilder) Description(description string) *AzureKeystoreBuilder {
+	builder.description = core.StringPtr(description)
+	return builder
+}
+
+// TlsProxy sets the TLS proxy used to connect to the keystore.
+func (builder *AzureKeystoreBuilder) TlsProxy(tlsProxy string) *AzureKeystoreBuilder {
+	builder.tlsProxy = core.StringPtr(tlsProxy)
+	return builder
+}
+
+// Groups sets the groups the keystore belongs to.
+func (builder *AzureKeystoreBuilder) Groups(groups ...string) *AzureKeystoreBuilder {
+	builder.groups = groups
+	return builder
+}
+
+// ServiceName sets the service name of the key vault instance.
+func (builder *AzureKeystoreBuilder) ServiceName(serviceName string) *AzureKeystoreBuilder {
+	builder.serviceName = core.StringPtr(serviceName)
+	return builder
+}
+
+// ResourceGroup sets the Azure resource group.
+func (builder *AzureKeystoreBuilder) ResourceGroup(resourceGroup string) *AzureKeystoreBuilder {
+	builder.resourceGroup = core.StringPtr(resourceGroup)
+	return builder
+}
+
+// Location sets the location of the key vault.
+func (builder *AzureKeystoreBuilder) Location(location AzureLocation) *AzureKeystoreBuilder {
+	builder.location = &location
+	return builder
+}
+
+// ServicePrincipal sets the service principal used to connect to the key vault.
+func (builder *AzureKeystoreBuilder) ServicePrincipal(clientID string, password string) *AzureKeystoreBuilder {
+	builder.clientID = core.StringPtr(clientID)
+	builder.password = core.StringPtr(password)
+	return builder
+}
+
+// Tenant sets the Azure tenant the key vault is associated with.
+func (builder *AzureKeystoreBuilder) Tenant(tenant string) *AzureKeystoreBuilder {
+	builder.tenant = core.StringPtr(tenant)
+	return builder
+}
+
+// SubscriptionID sets the Azure subscription ID.
+func (builder *AzureKeystoreBuilder) SubscriptionID(subscriptionID string) *AzureKeystoreBuilder {
+	builder.subscriptionID = core.StringPtr(subscriptionID)
+	return builder
+}
+
+// Environment sets the Azure environment.
+func (builder *AzureKeystoreBuilder) Environment(environment AzureEnvironment) *AzureKeystoreBuilder {
+	builder.environment = environment
+	return builder
+}
+
+// Build validates the keystore and returns its creation request, or a *KeystoreBuilderError.
+func (builder *AzureKeystoreBuilder) Build() (*KeystoreCreationRequestKeystoreTypeAzureCreate, error) {
+	builder.validateBase(false)
+	builder.require("azure_service_name", builder.serviceName)
+	builder.require("azure_resource_group", builder.resourceGroup)
+	if builder.location == nil {
+		builder.problem("azure_location", "is required")
+	} else if !builder.location.Valid() {
+		builder.problem("azure_location", "'%s' is not an Azure location", *builder.location)
+	}
+	builder.require("azure_service_principal_client_id", builder.clientID)
+	builder.require("azure_service_principal_password", builder.password)
+	builder.require("azure_tenant", builder.tenant)
+	builder.require("azure_subscription_id", builder.subscriptionID)
+	if !builder.environment.Valid() {
+		builder.problem("azure_environment", "'%s' is not an Azure environment", builder.environment)
+	}
+	if err := builder.err(); err != nil {
+		return nil, err
+	}
+	return &KeystoreCreationRequestKeystoreTypeAzureCreate{
+		Type:                          core.StringPtr(builder.keystoreType),
+		Vault:                         builder.vault(),
+		Name:                          builder.namePtr(),
+		Description:                   builder.description,
+		TlsProxy:                      builder.tlsProxy,
+		Groups:                        builder.groups,
+		AzureServiceName:              builder.serviceName,
+		AzureResourceGroup:            builder.resourceGroup,
+		AzureLocation:                 builder.location.Ptr(),
+		AzureServicePrincipalClientID: builder.clientID,
+		AzureServicePrincipalPassword: builder.password,
+		AzureTenant:                   builder.tenant,
+		AzureSubscriptionID:           builder.subscriptionID,
+		AzureEnvironment:              builder.environment.Ptr(),
+	}, nil
+}
+
+// GoogleKmsKeystoreBuilder : Builds the creation request of a Google Cloud KMS keystore.
+type GoogleKmsKeystoreBuilder struct {
+	keystoreBuilder
+	credentials  *string
+	location     *string
+	projectID    *string
+	privateKeyID *string
+	keyRing      *string
+}
+
+// NewGoogleKmsKeystore returns a builder for a Google Cloud KMS keystore in the specified vault.
+func NewGoogleKmsKeystore(vaultID string, name string) *GoogleKmsKeystoreBuilder {
+	return &GoogleKmsKeystoreBuilder{keystoreBuilder: keystoreBuilder{
+		keystoreType: KeystoreCreationRequest_Type_GoogleKms, vaultID: vaultID, name: name}}
+}
+
+// Description sets the description of the keystore.
+func (builder *GoogleKmsKeystoreBuilder) Description(description string) *GoogleKmsKeystoreBuilder {
+	builder.description = core.StringPtr(description)
+	return builder
+}
+
+// TlsProxy sets the TLS proxy used to connect to the keystore.
+func (builder *GoogleKmsKeystoreBuilder) TlsProxy(tlsProxy string) *GoogleKmsKeystoreBuilder {
+	builder.tlsProxy = core.StringPtr(tlsProxy)
+	return builder
+}
+
+// Groups sets the groups the keystore belongs to.
+func (builder *GoogleKmsKeystoreBuilder) Groups(groups ...string) *GoogleKmsKeystoreBuilder {
+	builder.groups = groups
+	return builder
+}
+
+// Credentials sets the service account key, as the Base64 encoded JSON key file.
+func (builder *GoogleKmsKeystoreBuilder) Credentials(credentials string) *GoogleKmsKeystoreBuilder {
+	builder.credentials = core.StringPtr(credentials)
+	return builder
+}
+
+// Location sets the location of the key ring, such as "europe-west3".
+func (builder *GoogleKmsKeystoreBuilder) Location(location string) *GoogleKmsKeystoreBuilder {
+	builder.location = core.StringPtr(location)
+	return builder
+}
+
+// ProjectID sets the Google Cloud project ID.
+func (builder *GoogleKmsKeystoreBuilder) ProjectID(projectID string) *GoogleKmsKeystoreBuilder {
+	builder.projectID = core.StringPtr(projectID)
+	return builder
+}
+
+// PrivateKeyID sets the ID of the private key of the service account key.
+func (builder *GoogleKmsKeystoreBuilder) PrivateKeyID(privateKeyID string) *GoogleKmsKeystoreBuilder {
+	builder.privateKeyID = core.StringPtr(privateKeyID)
+	return builder
+}
+
+// KeyRing sets the key ring the keys are created in.
+func (builder *GoogleKmsKeystoreBuilder) KeyRing(keyRing string) *GoogleKmsKeystoreBuilder {
+	builder.keyRing = core.StringPtr(keyRing)
+	return builder
+}
+
+// Build validates the keystore and returns its creation request, or a *KeystoreBuilderError.
+func (builder *GoogleKmsKeystoreBuilder) Build() (*KeystoreCreationRequestKeystoreTypeGoogleKmsCreate, error) {
+	builder.validateBase(true)
+	builder.require("google_credentials", builder.credentials)
+	if err := builder.err(); err != nil {
+		return nil, err
+	}
+	return &KeystoreCreationRequestKeystoreTypeGoogleKmsCreate{
+		Type:               core.StringPtr(builder.keystoreType),
+		Vault:              builder.vault(),
+		Name:               builder.namePtr(),
+		Description:        builder.description,
+		TlsProxy:           builder.tlsProxy,
+		Groups:             builder.groups,
+		GoogleCredentials:  builder.credentials,
+		GoogleLocation:     builder.location,
+		GoogleProjectID:    builder.projectID,
+		GooglePrivateKeyID: builder.privateKeyID,
+		GoogleKeyRing:      builder.keyRing,
+	}, nil
+}
+
+// IbmCloudKmsKeystoreBuilder : Builds the creation request of an IBM Cloud KMS keystore.
+type IbmCloudKmsKeystoreBuilder struct {
+	keystoreBuilder
+	variant     IbmVariant
+	apiEndpoint *string
+	iamEndpoint *string
+	apiKey      *string
+	instanceID  *string
+	keyRing     *string
+}
+
+// NewIbmCloudKmsKeystore returns a builder for an IBM Cloud KMS keystore of the specified variant in the specified
+// vault. Internal keystores only take the common properties; key_protect and hpcs keystores also need the endpoints,
+// the API key and the instance ID.
+func NewIbmCloudKmsKeystore(vaultID string, name string, variant IbmVariant) *IbmCloudKmsKeystoreBuilder {
+	return &IbmCloudKmsKeystoreBuilder{
+		keystoreBuilder: keystoreBuilder{
+			keystoreType: KeystoreCreationRequest_Type_IbmCloudKms, vaultID: vaultID, name: name},
+		variant: variant,
+	}
+}
+
+// Description sets the description of the keystore.
+func (builder *IbmCloudKmsKeystoreBuilder) Description(description string) *IbmCloudKmsKeystoreBuilder {
+	builder.description = core.StringPtr(description)
+	return builder
+}
+
+// TlsProxy sets the TLS proxy used to connect to the keystore.
+func (builder *IbmCloudKmsKeystoreBuilder) TlsProxy(tlsProxy string) *IbmCloudKmsKeystoreBuilder {
+	builder.tlsProxy = core.StringPtr(tlsProxy)
+	return builder
+}
+
+// Groups sets the groups the keystore belongs to.
+func (builder *IbmCloudKmsKeystoreBuilder) Groups(groups ...string) *IbmCloudKmsKeystoreBuilder {
+	builder.groups = groups
+	return builder
+}
+
+// ApiEndpoint sets the API endpoint of the key_protect or hpcs instance.
+func (builder *IbmCloudKmsKeystoreBuilder) ApiEndpoint(apiEndpoint string) *IbmCloudKmsKeystoreBuilder {
+	builder.apiEndpoint = core.StringPtr(apiEndpoint)
+	return builder
+}
+
+// IamEndpoint sets the endpoint of the IAM service.
+func (builder *IbmCloudKmsKeystoreBuilder) IamEndpoint(iamEndpoint string) *IbmCloudKmsKeystoreBuilder {
+	builder.iamEndpoint = core.StringPtr(iamEndpoint)
+	return builder
+}
+
+// ApiKey sets the IBM Cloud API key used to connect to the instance.
+func (builder *IbmCloudKmsKeystoreBuilder) ApiKey(apiKey string) *IbmCloudKmsKeystoreBuilder {
+	builder.apiKey = core.StringPtr(apiKey)
+	return builder
+}
+
+// InstanceID sets the ID of the key_protect or hpcs instance.
+func (builder *IbmCloudKmsKeystoreBuilder) InstanceID(instanceID string) *IbmCloudKmsKeystoreBuilder {
+	builder.instanceID = core.StringPtr(instanceID)
+	return builder
+}
+
+// KeyRing sets the key ring the keys are created in.
+func (builder *IbmCloudKmsKeystoreBuilder) KeyRing(keyRing string) *IbmCloudKmsKeystoreBuilder {
+	builder.keyRing = core.StringPtr(keyRing)
+	return builder
+}
+
+// Build validates the keystore and returns its creation request, or a *KeystoreBuilderError. The request is an
+// *IbmCloudKmsInternalKeystoreCreate for the internal variant and an *IbmCloudKmsKeystoreCreate otherwise.
+func (builder *IbmCloudKmsKeystoreBuilder) Build() (KeystoreCreationRequestIntf, error) {
+	builder.validateBase(true)
+	external := map[string]*string{
+		"ibm_api_endpoint": builder.apiEndpoint,
+		"ibm_iam_endpoint": builder.iamEndpoint,
+		"ibm_api_key":      builder.apiKey,
+		"ibm_instance_id":  builder.instanceID,
+		"ibm_key_ring":     builder.keyRing,
+	}
+	fields := []string{"ibm_api_endpoint", "ibm_iam_endpoint", "ibm_api_key", "ibm_instance_id", "ibm_key_ring"}
+	switch {
+	case !builder.variant.Valid():
+		builder.problem("ibm_variant", "'%s' is not an IBM Cloud KMS variant", builder.variant)
+	case builder.variant == IbmVariant_Internal:
+		for _, field := range fields {
+			if external[field] != nil {
+				builder.problem(field, "does not apply to internal keystores")
+			}
+		}
+	default:
+		for _, field := range fields[:4] {
+			builder.require(field, external[field])
+		}
+	}
+	if err := builder.err(); err != nil {
+		return nil, err
+	}
+
+	if builder.variant == IbmVariant_Internal {
+		return &IbmCloudKmsInternalKeystoreCreate{
+			Type:        core.StringPtr(builder.keystoreType),
+			Vault:       builder.vault(),
+			IbmVariant:  builder.variant.Ptr(),
+			Name:        builder.namePtr(),
+			Description: builder.description,
+			TlsProxy:    builder.tlsProxy,
+			Groups:      builder.groups,
+		}, nil
+	}
+	return &IbmCloudKmsKeystoreCreate{
+		Type:           core.StringPtr(builder.keystoreType),
+		Vault:          builder.vault(),
+		Name:           builder.namePtr(),
+		Description:    builder.description,
+		TlsProxy:       builder.tlsProxy,
+		Groups:         builder.groups,
+		IbmApiEndpoint: builder.apiEndpoint,
+		IbmIamEndpoint: builder.iamEndpoint,
+		IbmApiKey:      builder.apiKey,
+		IbmInstanceID:  builder.instanceID,
+		IbmVariant:     builder.variant.Ptr(),
+		IbmKeyRing:     builder.keyRing,
+	}, nil
+}
+
+// CcaKeystoreBuilder : Builds the creation request of a CCA (EKMF) keystore.
+type CcaKeystoreBuilder struct {
+	keystoreBuilder
+	host          *string
+	port          *int64
+	publicKeyHash *string
+	useTls        *bool
+	trustedIssuer *string
+}
+
+// NewCcaKeystore returns a builder for a CCA keystore in the specified vault.
+func NewCcaKeystore(vaultID string, name string) *CcaKeystoreBuilder {
+	return &CcaKeystoreBuilder{keystoreBuilder: keystoreBuilder{
+		keystoreType: KeystoreCreationRequest_Type_Cca, vaultID: vaultID, name: name}}
+}
+
+// Description sets the description of the keystore.
+func (builder *CcaKeystoreBuilder) Description(description string) *CcaKeystoreBuilder {
+	builder.description = core.StringPtr(description)
+	return builder
+}
+
+// TlsProxy sets the TLS proxy used to connect to the keystore.
+func (builder *CcaKeystoreBuilder) TlsProxy(tlsProxy string) *CcaKeystoreBuilder {
+	builder.tlsProxy = core.StringPtr(tlsProxy)
+	return builder
+}
+
+// Groups sets the groups the keystore belongs to.
+func (builder *CcaKeystoreBuilder) Groups(groups ...string) *CcaKeystoreBuilder {
+	builder.groups = groups
+	return builder
+}
+
+// Endpoint sets the host and port of the EKMF agent.
+func (builder *CcaKeystoreBuilder) Endpoint(host string, port int64) *CcaKeystoreBuilder {
+	builder.host = core.StringPtr(host)
+	builder.port = core.Int64Ptr(port)
+	return builder
+}
+
+// PublicKeyHash sets the hex encoded hash of the signature key.
+func (builder *CcaKeystoreBuilder) PublicKeyHash(publicKeyHash string) *CcaKeystoreBuilder {
+	builder.publicKeyHash = core.StringPtr(publicKeyHash)
+	return builder
+}
+
+// TLS enables TLS for the connection to the EKMF agent. The trusted issuer is the Base64 encoded PEM certificate of
+// the issuer of the agent's certificate; it is omitted if empty.
+func (builder *CcaKeystoreBuilder) TLS(trustedIssuer string) *CcaKeystoreBuilder {
+	builder.useTls = core.BoolPtr(true)
+	builder.trustedIssuer = nil
+	if trustedIssuer != "" {
+		builder.trustedIssuer = core.StringPtr(trustedIssuer)
+	}
+	return builder
+}
+
+// Build validates the keystore and returns its creation request, or a *KeystoreBuilderError.
+func (builder *CcaKeystoreBuilder) Build() (*KeystoreCreationRequestKeystoreTypeCcaCreate, error) {
+	builder.validateBase(true)
+	builder.require("cca_host", builder.host)
+	if builder.port == nil {
+		builder.problem("cca_port", "is required")
+	} else if *builder.port < 1 || *builder.port > 65535 {
+		builder.problem("cca_port", "must be between 1 and 65535")
+	}
+	builder.require("cca_public_key_hash", builder.publicKeyHash)
+	if builder.publicKeyHash != nil && *builder.publicKeyHash != "" {
+		if _, err := hex.DecodeString(*builder.publicKeyHash); err != nil {
+			builder.problem("cca_public_key_hash", "must be hex encoded")
+		}
+	}
+	if err := builder.err(); err != nil {
+		return nil, err
+	}
+	return &KeystoreCreationRequestKeystoreTypeCcaCreate{
+		Type:             core.StringPtr(builder.keystoreType),
+		Vault:            builder.vault(),
+		Name:             builder.namePtr(),
+		Description:      builder.description,
+		TlsProxy:         builder.tlsProxy,
+		Groups:           builder.groups,
+		CcaUseTls:        builder.useTls,
+		CcaTrustedIssuer: builder.trustedIssuer,
+		CcaHost:          builder.host,
+		CcaPort:          builder.port,
+		CcaPublicKeyHash: builder.publicKeyHash,
+	}, nil
+}
@@ -0,0 +1,263 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+)
+
+// KeystoreFieldSources maps the JSON name of each property loaded by LoadAwsKmsKeystore or LoadAzureKeystore to a
+// description of where it was found, such as "environment variable AWS_REGION".
+type KeystoreFieldSources map[string]string
+
+// AwsCredentialSourceOptions : The LoadAwsKmsKeystore options.
+type AwsCredentialSourceOptions struct {
+	// The profile to read from the shared files. Defaults to $AWS_PROFILE, or "default". Like the --profile option of
+	// the AWS CLI, setting it takes precedence over the access key in the environment variables.
+	Profile string
+
+	// The shared credentials file. Defaults to $AWS_SHARED_CREDENTIALS_FILE, or ~/.aws/credentials.
+	CredentialsFile string
+
+	// The shared config file. Defaults to $AWS_CONFIG_FILE, or ~/.aws/config.
+	ConfigFile string
+
+	// Ignore the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_REGION and AWS_DEFAULT_REGION environment variables.
+	IgnoreEnvironment bool
+}
+
+// LoadAwsKmsKeystore returns a builder for an AWS KMS keystore whose region and access key are loaded the way the AWS
+// CLI does: from the environment variables first, then from the profile in the shared credentials file, then from the
+// profile in the shared config file. The access key in the environment variables is skipped if the options name a
+// profile. The access key ID and secret access key are always loaded together, from the first source that has both.
+// The sources tell which of them supplied each property; properties that were not found are left unset and reported
+// by Build. Default files that do not exist are skipped; an error is returned if a file named in the options cannot be
+// read.
+func LoadAwsKmsKeystore(vaultID string, name string, options *AwsCredentialSourceOptions) (builder *AwsKmsKeystoreBuilder, sources KeystoreFieldSources, err error) {
+	if options == nil {
+		options = &AwsCredentialSourceOptions{}
+	}
+	profile := firstNonEmpty(options.Profile, os.Getenv("AWS_PROFILE"), "default")
+	builder = NewAwsKmsKeystore(vaultID, name)
+	sources = KeystoreFieldSources{}
+
+	setRegion := func(value string, source string) {
+		if builder.region == nil && value != "" {
+			builder.region = core.StringPtr(value)
+			sources["aws_region"] = source
+		}
+	}
+	setAccessKey := func(accessKeyID string, secretAccessKey string, accessKeyIDSource string, secretAccessKeySource string) {
+		if builder.accessKeyID == nil && accessKeyID != "" && secretAccessKey != "" {
+			builder.accessKeyID = core.StringPtr(accessKeyID)
+			builder.secretAccessKey = core.StringPtr(secretAccessKey)
+			sources["aws_access_key_id"] = accessKeyIDSource
+			sources["aws_secret_access_key"] = secretAccessKeySource
+		}
+	}
+
+	if !options.IgnoreEnvironment {
+		if options.Profile == "" {
+			setAccessKey(os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"),
+				"environment variable AWS_ACCESS_KEY_ID", "environment variable AWS_SECRET_ACCESS_KEY")
+		}
+		for _, variable := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+			setRegion(os.Getenv(variable), "environment variable "+variable)
+		}
+	}
+
+	credentialsFile, required := sharedFilePath(options.CredentialsFile, "AWS_SHARED_CREDENTIALS_FILE", ".aws", "credentials")
+	sections, err := readIniFile(credentialsFile, required)
+	if err != nil {
+		return nil, nil, err
+	}
+	source := fmt.Sprintf("%s [%s]", credentialsFile, profile)
+	setAccessKey(sections[profile]["aws_access_key_id"], sections[profile]["aws_secret_access_key"], source, source)
+
+	configFile, required := sharedFilePath(options.ConfigFile, "AWS_CONFIG_FILE", ".aws", "config")
+	configSections, err := readIniFile(configFile, required)
+	if err != nil {
+		return nil, nil, err
+	}
+	section := "profile " + profile
+	if profile == "default" {
+		section = "default"
+	}
+	source = fmt.Sprintf("%s [%s]", configFile, section)
+	setAccessKey(configSections[section]["aws_access_key_id"], configSections[section]["aws_secret_access_key"], source, source)
+	setRegion(configSections[section]["region"], source)
+	return
+}
+
+// AzureCredentialSourceOptions : The LoadAzureKeystore options.
+type AzureCredentialSourceOptions struct {
+	// An SDK auth file, as written by "az ad sp create-for-rbac --sdk-auth". Defaults to $AZURE_AUTH_LOCATION.
+	AuthFile string
+
+	// Ignore the AZURE_TENANT_ID, AZURE_CLIENT_ID, AZURE_CLIENT_SECRET and AZURE_SUBSCRIPTION_ID environment variables.
+	IgnoreEnvironment bool
+}
+
+// azureAuthFile is the content of an SDK auth file.
+type azureAuthFile struct {
+	ClientID                   string `json:"clientId"`
+	ClientSecret               string `json:"clientSecret"`
+	SubscriptionID             string `json:"subscriptionId"`
+	TenantID                   string `json:"tenantId"`
+	ResourceManagerEndpointURL string `json:"resourceManagerEndpointUrl"`
+}
+
+// azureEnvironmentsByEndpoint maps the resource manager endpoints of the Azure clouds to their environment.
+var azureEnvironmentsByEndpoint = map[string]AzureEnvironment{
+	"management.azure.com":         AzureEnvironment_Azure,
+	"management.chinacloudapi.cn":  AzureEnvironment_AzureChina,
+	"management.microsoftazure.de": AzureEnvironment_AzureGermany,
+	"management.usgovcloudapi.net": AzureEnvironment_AzureUsGovernment,
+}
+
+// LoadAzureKeystore returns a builder for an Azure Key Vault keystore whose tenant, service principal and subscription
+// are loaded from the environment variables used by the Azure SDKs first, then from an SDK auth file. The auth file
+// also sets the environment, from its resource manager endpoint. The sources tell which of them supplied each
+// property. The key vault itself, its resource group and location, must still be set on the builder.
+func LoadAzureKeystore(vaultID string, name string, options *AzureCredentialSourceOptions) (builder *AzureKeystoreBuilder, sources KeystoreFieldSources, err error) {
+	if options == nil {
+		options = &AzureCredentialSourceOptions{}
+	}
+	builder = NewAzureKeystore(vaultID, name)
+	sources = KeystoreFieldSources{}
+
+	set := func(field string, target **string, value string, source string) {
+		if *target == nil && value != "" {
+			*target = core.StringPtr(value)
+			sources[field] = source
+		}
+	}
+	if !options.IgnoreEnvironment {
+		for _, variable := range []struct {
+			field  string
+			target **string
+			name   string
+		}{
+			{"azure_tenant", &builder.tenant, "AZURE_TENANT_ID"},
+			{"azure_service_principal_client_id", &builder.clientID, "AZURE_CLIENT_ID"},
+			{"azure_service_principal_password", &builder.password, "AZURE_CLIENT_SECRET"},
+			{"azure_subscription_id", &builder.subscriptionID, "AZURE_SUBSCRIPTION_ID"},
+		} {
+			set(variable.field, variable.target, os.Getenv(variable.name), "environment variable "+variable.name)
+		}
+	}
+
+	authFile := firstNonEmpty(options.AuthFile, os.Getenv("AZURE_AUTH_LOCATION"))
+	if authFile == "" {
+		return
+	}
+	data, err := ioutil.ReadFile(authFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	auth := new(azureAuthFile)
+	if err = json.Unmarshal(data, auth); err != nil {
+		return nil, nil, fmt.Errorf("invalid Azure auth file '%s': %s", authFile, err.Error())
+	}
+	source := "auth file " + authFile
+	set("azure_tenant", &builder.tenant, auth.TenantID, source)
+	set("azure_service_principal_client_id", &builder.clientID, auth.ClientID, source)
+	set("azure_service_principal_password", &builder.password, auth.ClientSecret, source)
+	set("azure_subscription_id", &builder.subscriptionID, auth.SubscriptionID, source)
+	if auth.ResourceManagerEndpointURL != "" {
+		host := strings.TrimSuffix(strings.TrimPrefix(auth.ResourceManagerEndpointURL, "https://"), "/")
+		environment, ok := azureEnvironmentsByEndpoint[host]
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid Azure auth file '%s': unknown resource manager endpoint '%s'",
+				authFile, auth.ResourceManagerEndpointURL)
+		}
+		builder.environment = environment
+		sources["azure_environment"] = source
+	}
+	return
+}
+
+// sharedFilePath returns the path of a shared file: the one from the options, which is required to exist, or the one
+// from the environment variable, or the default one in the home directory.
+func sharedFilePath(path string, variable string, elem ...string) (string, bool) {
+	if path != "" {
+		return path, true
+	}
+	if path = os.Getenv(variable); path != "" {
+		return path, false
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", false
+	}
+	return filepath.Join(append([]string{home}, elem...)...), false
+}
+
+// readIniFile reads the sections of an INI file, such as the AWS shared files. A file that does not exist is read as
+// empty unless it is required.
+func readIniFile(path string, required bool) (sections map[string]map[string]string, err error) {
+	sections = map[string]map[string]string{}
+	if path == "" {
+		return
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) && !required {
+		return sections, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var section map[string]string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, ";"):
+		case strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]"):
+			name := strings.Join(strings.Fields(text[1:len(text)-1]), " ")
+			if sections[name] == nil {
+				sections[name] = map[string]string{}
+			}
+			section = sections[name]
+		default:
+			separator := strings.Index(text, "=")
+			if separator < 0 || section == nil {
+				return nil, fmt.Errorf("%s:%d: invalid line", path, line)
+			}
+			section[strings.TrimSpace(text[:separator])] = strings.TrimSpace(text[separator+1:])
+		}
+	}
+	return sections, scanner.Err()
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,228 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/IBM/ibm-hpcs-uko-sdk/ukov4"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`Keystore credential sources`, func() {
+	var dir string
+	var saved map[string]*string
+	variables := []string{
+		"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_REGION", "AWS_DEFAULT_REGION", "AWS_PROFILE",
+		"AWS_SHARED_CREDENTIALS_FILE", "AWS_CONFIG_FILE",
+		"AZURE_TENANT_ID", "AZURE_CLIENT_ID", "AZURE_CLIENT_SECRET", "AZURE_SUBSCRIPTION_ID", "AZURE_AUTH_LOCATION",
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "credential-sources")
+		Expect(err).To(BeNil())
+		saved = map[string]*string{}
+		for _, variable := range variables {
+			if value, ok := os.LookupEnv(variable); ok {
+				saved[variable] = &value
+			} else {
+				saved[variable] = nil
+			}
+			os.Unsetenv(variable)
+		}
+		os.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+		os.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	})
+	AfterEach(func() {
+		for variable, value := range saved {
+			if value == nil {
+				os.Unsetenv(variable)
+			} else {
+				os.Setenv(variable, *value)
+			}
+		}
+		os.RemoveAll(dir)
+	})
+
+	writeFile := func(name string, contents string) string {
+		path := filepath.Join(dir, name)
+		Expect(ioutil.WriteFile(path, []byte(contents), 0600)).To(Succeed())
+		return path
+	}
+
+	It(`Load an AWS KMS keystore from the shared files`, func() {
+		writeFile("credentials", `
+[default]
+aws_access_key_id = AKIADEFAULT
+aws_secret_access_key = default-secret
+
+[payments]
+aws_access_key_id = AKIAPAYMENTS
+aws_secret_access_key = payments-secret
+`)
+		writeFile("config", `
+# regions per profile
+[default]
+region = us-east-1
+
+[profile payments]
+region = eu-central-1
+`)
+		builder, sources, err := ukov4.LoadAwsKmsKeystore("v1", "aws", &ukov4.AwsCredentialSourceOptions{Profile: "payments"})
+		Expect(err).To(BeNil())
+		keystore, err := builder.Groups("eu").Build()
+		Expect(err).To(BeNil())
+		Expect(*keystore.AwsAccessKeyID).To(Equal("AKIAPAYMENTS"))
+		Expect(*keystore.AwsSecretAccessKey).To(Equal("payments-secret"))
+		Expect(*keystore.AwsRegion).To(Equal("eu-central-1"))
+		Expect(sources).To(Equal(ukov4.KeystoreFieldSources{
+			"aws_access_key_id":     filepath.Join(dir, "credentials") + " [payments]",
+			"aws_secret_access_key": filepath.Join(dir, "credentials") + " [payments]",
+			"aws_region":            filepath.Join(dir, "config") + " [profile payments]",
+		}))
+	})
+	It(`Prefer the AWS environment variables to the shared files`, func() {
+		writeFile("config", "[default]\nregion = us-east-1\naws_access_key_id = AKIACONFIG\naws_secret_access_key = config-secret\n")
+		os.Setenv("AWS_ACCESS_KEY_ID", "AKIAENV")
+		os.Setenv("AWS_SECRET_ACCESS_KEY", "env-secret")
+		os.Setenv("AWS_DEFAULT_REGION", "eu-west-1")
+
+		builder, sources, err := ukov4.LoadAwsKmsKeystore("v1", "aws", nil)
+		Expect(err).To(BeNil())
+		keystore, err := builder.Build()
+		Expect(err).To(BeNil())
+		Expect(*keystore.AwsAccessKeyID).To(Equal("AKIAENV"))
+		Expect(*keystore.AwsSecretAccessKey).To(Equal("env-secret"))
+		Expect(*keystore.AwsRegion).To(Equal("eu-west-1"))
+		Expect(sources).To(Equal(ukov4.KeystoreFieldSources{
+			"aws_access_key_id":     "environment variable AWS_ACCESS_KEY_ID",
+			"aws_secret_access_key": "environment variable AWS_SECRET_ACCESS_KEY",
+			"aws_region":            "environment variable AWS_DEFAULT_REGION",
+		}))
+
+		builder, sources, err = ukov4.LoadAwsKmsKeystore("v1", "aws", &ukov4.AwsCredentialSourceOptions{IgnoreEnvironment: true})
+		Expect(err).To(BeNil())
+		Expect(sources["aws_access_key_id"]).To(Equal(filepath.Join(dir, "config") + " [default]"))
+	})
+	It(`Prefer an explicit AWS profile to the access key in the environment variables`, func() {
+		writeFile("credentials", "[payments]\naws_access_key_id = AKIAPAYMENTS\naws_secret_access_key = payments-secret\n")
+		os.Setenv("AWS_ACCESS_KEY_ID", "AKIAENV")
+		os.Setenv("AWS_SECRET_ACCESS_KEY", "env-secret")
+		os.Setenv("AWS_REGION", "eu-west-1")
+
+		builder, sources, err := ukov4.LoadAwsKmsKeystore("v1", "aws", &ukov4.AwsCredentialSourceOptions{Profile: "payments"})
+		Expect(err).To(BeNil())
+		keystore, err := builder.Build()
+		Expect(err).To(BeNil())
+		Expect(*keystore.AwsAccessKeyID).To(Equal("AKIAPAYMENTS"))
+		Expect(*keystore.AwsSecretAccessKey).To(Equal("payments-secret"))
+		Expect(*keystore.AwsRegion).To(Equal("eu-west-1"))
+		Expect(sources["aws_secret_access_key"]).To(Equal(filepath.Join(dir, "credentials") + " [payments]"))
+
+		os.Setenv("AWS_PROFILE", "payments")
+		_, sources, err = ukov4.LoadAwsKmsKeystore("v1", "aws", nil)
+		Expect(err).To(BeNil())
+		Expect(sources["aws_access_key_id"]).To(Equal("environment variable AWS_ACCESS_KEY_ID"))
+	})
+	It(`Load the AWS access key ID and secret access key from the same source`, func() {
+		writeFile("credentials", "[default]\naws_access_key_id = AKIADEFAULT\n")
+		writeFile("config", "[default]\naws_access_key_id = AKIACONFIG\naws_secret_access_key = config-secret\nregion = us-east-1\n")
+		os.Setenv("AWS_SECRET_ACCESS_KEY", "env-secret")
+
+		builder, sources, err := ukov4.LoadAwsKmsKeystore("v1", "aws", nil)
+		Expect(err).To(BeNil())
+		keystore, err := builder.Build()
+		Expect(err).To(BeNil())
+		Expect(*keystore.AwsAccessKeyID).To(Equal("AKIACONFIG"))
+		Expect(*keystore.AwsSecretAccessKey).To(Equal("config-secret"))
+		Expect(sources["aws_access_key_id"]).To(Equal(filepath.Join(dir, "config") + " [default]"))
+		Expect(sources["aws_secret_access_key"]).To(Equal(filepath.Join(dir, "config") + " [default]"))
+
+		writeFile("config", "[default]\nregion = us-east-1\n")
+		builder, sources, err = ukov4.LoadAwsKmsKeystore("v1", "aws", nil)
+		Expect(err).To(BeNil())
+		Expect(sources).To(Equal(ukov4.KeystoreFieldSources{"aws_region": filepath.Join(dir, "config") + " [default]"}))
+		_, err = builder.Build()
+		Expect(err).ToNot(BeNil())
+	})
+	It(`Report missing AWS sources (negative test)`, func() {
+		builder, sources, err := ukov4.LoadAwsKmsKeystore("v1", "aws", nil)
+		Expect(err).To(BeNil())
+		Expect(sources).To(BeEmpty())
+		_, err = builder.Build()
+		Expect(err).ToNot(BeNil())
+
+		_, _, err = ukov4.LoadAwsKmsKeystore("v1", "aws", &ukov4.AwsCredentialSourceOptions{
+			CredentialsFile: filepath.Join(dir, "missing")})
+		Expect(os.IsNotExist(err)).To(BeTrue())
+
+		_, _, err = ukov4.LoadAwsKmsKeystore("v1", "aws", &ukov4.AwsCredentialSourceOptions{
+			ConfigFile: writeFile("broken", "region = us-east-1\n")})
+		Expect(err).ToNot(BeNil())
+		Expect(err.Error()).To(ContainSubstring("broken:1: invalid line"))
+	})
+	It(`Load an Azure keystore from the environment and an auth file`, func() {
+		os.Setenv("AZURE_CLIENT_SECRET", "env-secret")
+		authFile := writeFile("azure.json", `{
+  "clientId": "client",
+  "clientSecret": "file-secret",
+  "subscriptionId": "subscription",
+  "tenantId": "tenant",
+  "resourceManagerEndpointUrl": "https://management.usgovcloudapi.net/"
+}`)
+		builder, sources, err := ukov4.LoadAzureKeystore("v1", "azure", &ukov4.AzureCredentialSourceOptions{AuthFile: authFile})
+		Expect(err).To(BeNil())
+		keystore, err := builder.
+			ServiceName("kv").
+			ResourceGroup("rg").
+			Location(ukov4.AzureLocation_UsGovVirginia).
+			Build()
+		Expect(err).To(BeNil())
+		Expect(*keystore.AzureServicePrincipalPassword).To(Equal("env-secret"))
+		Expect(*keystore.AzureServicePrincipalClientID).To(Equal("client"))
+		Expect(*keystore.AzureEnvironment).To(Equal("azure_us_government"))
+		Expect(sources).To(Equal(ukov4.KeystoreFieldSources{
+			"azure_tenant":                      "auth file " + authFile,
+			"azure_service_principal_client_id": "auth file " + authFile,
+			"azure_service_principal_password":  "environment variable AZURE_CLIENT_SECRET",
+			"azure_subscription_id":             "auth file " + authFile,
+			"azure_environment":                 "auth file " + authFile,
+		}))
+	})
+	It(`Load an Azure keystore from the environment only`, func() {
+		os.Setenv("AZURE_TENANT_ID", "tenant")
+		os.Setenv("AZURE_CLIENT_ID", "client")
+		os.Setenv("AZURE_CLIENT_SECRET", "secret")
+		os.Setenv("AZURE_SUBSCRIPTION_ID", "subscription")
+		builder, sources, err := ukov4.LoadAzureKeystore("v1", "azure", nil)
+		Expect(err).To(BeNil())
+		Expect(sources).To(HaveLen(4))
+		keystore, err := builder.ServiceName("kv").ResourceGroup("rg").Location(ukov4.AzureLocation_EuropeWest).Build()
+		Expect(err).To(BeNil())
+		Expect(*keystore.AzureTenant).To(Equal("tenant"))
+		Expect(*keystore.AzureEnvironment).To(Equal("azure"))
+
+		_, _, err = ukov4.LoadAzureKeystore("v1", "azure", &ukov4.AzureCredentialSourceOptions{
+			AuthFile: writeFile("cloud.json", `{"resourceManagerEndpointUrl": "https://management.example.com/"}`)})
+		Expect(err).ToNot(BeNil())
+		Expect(err.Error()).To(ContainSubstring("unknown resource manager endpoint"))
+	})
+})
@@ -0,0 +1,356 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+)
+
+// Default values used by RotateKeystoreCredentials when waiting for the keystore to become healthy.
+const (
+	DefaultCredentialRotationPollInterval = 10 * time.Second
+	DefaultCredentialRotationTimeout      = 5 * time.Minute
+)
+
+// KeystoreCredentials : The credentials of a keystore. Only the properties matching the type of the keystore are used.
+type KeystoreCredentials struct {
+	// AWS Access Key ID.
+	AwsAccessKeyID *string `json:"aws_access_key_id,omitempty"`
+
+	// AWS Secret Access Key.
+	AwsSecretAccessKey *string `json:"aws_secret_access_key,omitempty"`
+
+	// Azure service principal client ID.
+	AzureServicePrincipalClientID *string `json:"azure_service_principal_client_id,omitempty"`
+
+	// Azure service principal password.
+	AzureServicePrincipalPassword *string `json:"azure_service_principal_password,omitempty"`
+
+	// The value of the JSON key represented in the Base64 format.
+	GoogleCredentials *string `json:"google_credentials,omitempty"`
+
+	// The private key id associated with this keystore.
+	GooglePrivateKeyID *string `json:"google_private_key_id,omitempty"`
+
+	// The IBM Cloud API key to be used for connecting to this IBM Cloud keystore.
+	IbmApiKey *string `json:"ibm_api_key,omitempty"`
+}
+
+// updateRequest returns the typed update request that replaces the credentials of a keystore of the specified type.
+func (credentials *KeystoreCredentials) updateRequest(keystoreType string) (body KeystoreUpdateRequestIntf, err error) {
+	switch keystoreType {
+	case Keystore_Type_AwsKms:
+		if credentials.AwsAccessKeyID == nil && credentials.AwsSecretAccessKey == nil {
+			break
+		}
+		body = &KeystoreUpdateRequestKeystoreTypeAwsKmsUpdate{
+			AwsAccessKeyID:     credentials.AwsAccessKeyID,
+			AwsSecretAccessKey: credentials.AwsSecretAccessKey,
+		}
+	case Keystore_Type_AzureKeyVault:
+		if credentials.AzureServicePrincipalPassword == nil {
+			break
+		}
+		body = &KeystoreUpdateRequestKeystoreTypeAzureUpdate{
+			AzureServicePrincipalClientID: credentials.AzureServicePrincipalClientID,
+			AzureServicePrincipalPassword: credentials.AzureServicePrincipalPassword,
+		}
+	case Keystore_Type_GoogleKms:
+		if credentials.GoogleCredentials == nil {
+			break
+		}
+		body = &KeystoreUpdateRequestKeystoreTypeGoogleKmsUpdate{
+			GoogleCredentials:  credentials.GoogleCredentials,
+			GooglePrivateKeyID: credentials.GooglePrivateKeyID,
+		}
+	case Keystore_Type_IbmCloudKms:
+		if credentials.IbmApiKey == nil {
+			break
+		}
+		body = &KeystoreUpdateRequestKeystoreTypeIbmCloudKmsUpdate{
+			IbmApiKey: credentials.IbmApiKey,
+		}
+	default:
+		return nil, fmt.Errorf("keystores of type '%s' have no credentials to rotate", keystoreType)
+	}
+	if body == nil {
+		err = fmt.Errorf("no credentials for a keystore of type '%s'", keystoreType)
+	}
+	return
+}
+
+// KeystoreUnhealthyError : The error returned by RotateKeystoreCredentials when the keystore does not become healthy
+// with the new credentials.
+type KeystoreUnhealthyError struct {
+	// The UUID of the keystore.
+	KeystoreID string
+
+	// The last health status reported for the keystore; empty if its status could not be read.
+	HealthStatus string
+
+	// The last message reported with the health status.
+	Message string
+
+	// Whether the previous credentials were restored.
+	RolledBack bool
+}
+
+// Error implements the error interface.
+func (e *KeystoreUnhealthyError) Error() string {
+	healthStatus := e.HealthStatus
+	if healthStatus == "" {
+		healthStatus = "unknown"
+	}
+	msg := fmt.Sprintf("keystore '%s' is not healthy after the credential rotation: %s", e.KeystoreID, healthStatus)
+	if e.Message != "" {
+		msg += " (" + e.Message + ")"
+	}
+	if e.RolledBack {
+		msg += "; the previous credentials were restored"
+	}
+	return msg
+}
+
+// RotateKeystoreCredentials : Replace the credentials of a keystore and verify that it stays healthy
+// Updates the keystore with the new credentials, using the update model matching its type, then polls its status
+// until its health status is ok. The first check is made one poll interval after the update so that the status
+// reflects the new credentials; a status that cannot be read counts as unhealthy. If the keystore is still unhealthy
+// when the timeout expires, or the context is done before it becomes healthy, and PreviousCredentials are set, they are
+// restored. Whenever the keystore does not become healthy before the timeout, a *KeystoreUnhealthyError is returned
+// together with the result; if the context is done, its error is returned instead.
+func (uko *UkoV4) RotateKeystoreCredentials(rotateKeystoreCredentialsOptions *RotateKeystoreCredentialsOptions) (result *KeystoreCredentialRotationResult, err error) {
+	return uko.RotateKeystoreCredentialsWithContext(context.Background(), rotateKeystoreCredentialsOptions)
+}
+
+// RotateKeystoreCredentialsWithContext is an alternate form of the RotateKeystoreCredentials method which supports a Context parameter
+func (uko *UkoV4) RotateKeystoreCredentialsWithContext(ctx context.Context, rotateKeystoreCredentialsOptions *RotateKeystoreCredentialsOptions) (result *KeystoreCredentialRotationResult, err error) {
+	err = core.ValidateNotNil(rotateKeystoreCredentialsOptions, "rotateKeystoreCredentialsOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(rotateKeystoreCredentialsOptions, "rotateKeystoreCredentialsOptions")
+	if err != nil {
+		return
+	}
+	options := rotateKeystoreCredentialsOptions
+	id := *options.ID
+
+	keystoreType, etag, err := uko.getKeystoreTypeAndETag(ctx, id)
+	if err != nil {
+		return
+	}
+	body, err := options.Credentials.updateRequest(keystoreType)
+	if err != nil {
+		return
+	}
+	var previous KeystoreUpdateRequestIntf
+	if options.PreviousCredentials != nil {
+		previous, err = options.PreviousCredentials.updateRequest(keystoreType)
+		if err != nil {
+			return
+		}
+	}
+
+	_, _, err = uko.UpdateKeystoreWithContext(ctx, &UpdateKeystoreOptions{
+		ID:           core.StringPtr(id),
+		IfMatch:      core.StringPtr(etag),
+		KeystoreBody: body,
+	})
+	if err != nil {
+		return
+	}
+
+	result = &KeystoreCredentialRotationResult{
+		KeystoreID: core.StringPtr(id),
+		Type:       core.StringPtr(keystoreType),
+		RolledBack: core.BoolPtr(false),
+	}
+	status, pollErr, err := uko.waitForKeystoreHealth(ctx, id, options.PollInterval, options.Timeout)
+	if status != nil {
+		result.HealthStatus = status.HealthStatus
+		result.Message = status.Message
+	}
+	result.Healthy = core.BoolPtr(err == nil && pollErr == nil &&
+		core.StringNilMapper(result.HealthStatus) == KeystoreStatus_HealthStatus_Ok)
+	if *result.Healthy {
+		return
+	}
+
+	waitErr := err
+	if previous != nil {
+		// The credentials were replaced, so they are restored even if the context is done.
+		rollbackCtx := ctx
+		if ctx.Err() != nil {
+			rollbackCtx = context.Background()
+		}
+		_, etag, err = uko.getKeystoreTypeAndETag(rollbackCtx, id)
+		if err == nil {
+			_, _, err = uko.UpdateKeystoreWithContext(rollbackCtx, &UpdateKeystoreOptions{
+				ID:           core.StringPtr(id),
+				IfMatch:      core.StringPtr(etag),
+				KeystoreBody: previous,
+			})
+		}
+		if err != nil {
+			err = fmt.Errorf("error restoring the previous credentials of keystore '%s': %s", id, err.Error())
+			return
+		}
+		result.RolledBack = core.BoolPtr(true)
+	}
+	if waitErr != nil {
+		err = waitErr
+		return
+	}
+	message := core.StringNilMapper(result.Message)
+	if pollErr != nil {
+		message = "error reading the keystore status: " + pollErr.Error()
+	}
+	err = &KeystoreUnhealthyError{
+		KeystoreID:   id,
+		HealthStatus: core.StringNilMapper(result.HealthStatus),
+		Message:      message,
+		RolledBack:   *result.RolledBack,
+	}
+	return
+}
+
+// getKeystoreTypeAndETag reads a keystore and returns its type and current ETag.
+func (uko *UkoV4) getKeystoreTypeAndETag(ctx context.Context, id string) (keystoreType string, etag string, err error) {
+	keystore, response, err := uko.GetKeystoreWithContext(ctx, &GetKeystoreOptions{ID: core.StringPtr(id)})
+	if err != nil {
+		return
+	}
+	generic := new(Keystore)
+	err = remarshal(keystore, generic)
+	if err != nil {
+		return
+	}
+	return core.StringNilMapper(generic.Type), getETag(response), nil
+}
+
+// waitForKeystoreHealth polls the status of a keystore until its health status is ok or the timeout expires, and
+// returns the last status read. A status that cannot be read counts as not healthy yet; the error of the last poll is
+// returned as pollErr if it failed. err is only set if the context is done.
+func (uko *UkoV4) waitForKeystoreHealth(ctx context.Context, id string, pollInterval time.Duration, timeout time.Duration) (status *KeystoreStatus, pollErr error, err error) {
+	if pollInterval <= 0 {
+		pollInterval = DefaultCredentialRotationPollInterval
+	}
+	if timeout <= 0 {
+		timeout = DefaultCredentialRotationTimeout
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		if err = sleepWithContext(ctx, pollInterval); err != nil {
+			return
+		}
+		var current *KeystoreStatus
+		current, _, pollErr = uko.GetKeystoreStatusWithContext(ctx, &GetKeystoreStatusOptions{ID: core.StringPtr(id)})
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		if pollErr == nil {
+			status = current
+			if core.StringNilMapper(status.HealthStatus) == KeystoreStatus_HealthStatus_Ok {
+				return
+			}
+		}
+		if !time.Now().Add(pollInterval).Before(deadline) {
+			return
+		}
+	}
+}
+
+// RotateKeystoreCredentialsOptions : The RotateKeystoreCredentials options.
+type RotateKeystoreCredentialsOptions struct {
+	// UUID of the keystore.
+	ID *string `json:"id" validate:"required,ne="`
+
+	// The new credentials.
+	Credentials *KeystoreCredentials `json:"credentials" validate:"required"`
+
+	// The credentials to restore if the keystore does not become healthy. Nothing is restored if not set.
+	PreviousCredentials *KeystoreCredentials `json:"previous_credentials,omitempty"`
+
+	// The interval between status checks. Defaults to DefaultCredentialRotationPollInterval.
+	PollInterval time.Duration
+
+	// How long to wait for the keystore to become healthy. Defaults to DefaultCredentialRotationTimeout.
+	Timeout time.Duration
+}
+
+// NewRotateKeystoreCredentialsOptions : Instantiate RotateKeystoreCredentialsOptions
+func (*UkoV4) NewRotateKeystoreCredentialsOptions(id string, credentials *KeystoreCredentials) *RotateKeystoreCredentialsOptions {
+	return &RotateKeystoreCredentialsOptions{
+		ID:          core.StringPtr(id),
+		Credentials: credentials,
+	}
+}
+
+// SetID : Allow user to set ID
+func (_options *RotateKeystoreCredentialsOptions) SetID(id string) *RotateKeystoreCredentialsOptions {
+	_options.ID = core.StringPtr(id)
+	return _options
+}
+
+// SetCredentials : Allow user to set Credentials
+func (_options *RotateKeystoreCredentialsOptions) SetCredentials(credentials *KeystoreCredentials) *RotateKeystoreCredentialsOptions {
+	_options.Credentials = credentials
+	return _options
+}
+
+// SetPreviousCredentials : Allow user to set PreviousCredentials
+func (_options *RotateKeystoreCredentialsOptions) SetPreviousCredentials(previousCredentials *KeystoreCredentials) *RotateKeystoreCredentialsOptions {
+	_options.PreviousCredentials = previousCredentials
+	return _options
+}
+
+// SetPollInterval : Allow user to set PollInterval
+func (_options *RotateKeystoreCredentialsOptions) SetPollInterval(pollInterval time.Duration) *RotateKeystoreCredentialsOptions {
+	_options.PollInterval = pollInterval
+	return _options
+}
+
+// SetTimeout : Allow user to set Timeout
+func (_options *RotateKeystoreCredentialsOptions) SetTimeout(timeout time.Duration) *RotateKeystoreCredentialsOptions {
+	_options.Timeout = timeout
+	return _options
+}
+
+// KeystoreCredentialRotationResult : The result of a RotateKeystoreCredentials run.
+type KeystoreCredentialRotationResult struct {
+	// The UUID of the keystore.
+	KeystoreID *string `json:"keystore_id"`
+
+	// Type of keystore.
+	Type *string `json:"type"`
+
+	// Whether the keystore was healthy with the new credentials.
+	Healthy *bool `json:"healthy"`
+
+	// The last health status reported for the keystore with the new credentials.
+	HealthStatus *string `json:"health_status,omitempty"`
+
+	// The last message reported with the health status.
+	Message *string `json:"message,omitempty"`
+
+	// Whether the previous credentials were restored.
+	RolledBack *bool `json:"rolled_back"`
+}
@@ -0,0 +1,200 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-hpcs-uko-sdk/ukov4"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`RotateKeystoreCredentials`, func() {
+	var testServer *httptest.Server
+	var keystoreType string
+	var healthStatuses []string
+	var updates []map[string]interface{}
+	var version int
+	var statusCode int
+	var statusReads int
+	var cancelOnStatus context.CancelFunc
+
+	BeforeEach(func() {
+		keystoreType = "aws_kms"
+		healthStatuses = nil
+		statusCode = 200
+		statusReads = 0
+		cancelOnStatus = nil
+		updates = nil
+		version = 1
+		testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+
+			res.Header().Set("Content-type", "application/json")
+			switch req.Method + " " + req.URL.EscapedPath() {
+			case "GET /api/v4/keystores/ks1":
+				res.Header().Set("ETag", fmt.Sprintf("etag-%d", version))
+				res.WriteHeader(200)
+				fmt.Fprintf(res, `{"id": "ks1", "name": "ks", "type": "%s", "vault": {"id": "v1"}}`, keystoreType)
+			case "PATCH /api/v4/keystores/ks1":
+				Expect(req.Header.Get("If-Match")).To(Equal(fmt.Sprintf("etag-%d", version)))
+				version++
+				var body map[string]interface{}
+				Expect(json.NewDecoder(req.Body).Decode(&body)).To(Succeed())
+				updates = append(updates, body)
+				res.WriteHeader(200)
+				fmt.Fprintf(res, `{"id": "ks1", "name": "ks", "type": "%s"}`, keystoreType)
+			case "GET /api/v4/keystores/ks1/status":
+				statusReads++
+				if cancelOnStatus != nil {
+					cancelOnStatus()
+				}
+				if statusCode != 200 {
+					res.WriteHeader(statusCode)
+					fmt.Fprint(res, `{"status_code": 500, "errors": [{"code": "internal_error", "message": "status unavailable"}]}`)
+					return
+				}
+				status := "not_responding"
+				if len(healthStatuses) > 0 {
+					status = healthStatuses[0]
+					healthStatuses = healthStatuses[1:]
+				}
+				res.WriteHeader(200)
+				fmt.Fprintf(res, `{"status": "ok", "health_status": "%s", "message": "checked"}`, status)
+			default:
+				Fail(fmt.Sprintf("unexpected request %s %s", req.Method, req.URL.EscapedPath()))
+			}
+		}))
+	})
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	newService := func() *ukov4.UkoV4 {
+		ukoService, serviceErr := ukov4.NewUkoV4(&ukov4.UkoV4Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+		return ukoService
+	}
+	awsCredentials := func(id string, secret string) *ukov4.KeystoreCredentials {
+		return &ukov4.KeystoreCredentials{AwsAccessKeyID: core.StringPtr(id), AwsSecretAccessKey: core.StringPtr(secret)}
+	}
+	newOptions := func(ukoService *ukov4.UkoV4, credentials *ukov4.KeystoreCredentials) *ukov4.RotateKeystoreCredentialsOptions {
+		return ukoService.NewRotateKeystoreCredentialsOptions("ks1", credentials).
+			SetPollInterval(time.Millisecond).
+			SetTimeout(20 * time.Millisecond)
+	}
+
+	It(`Invoke RotateKeystoreCredentials with invalid options (negative test)`, func() {
+		ukoService := newService()
+		_, err := ukoService.RotateKeystoreCredentials(nil)
+		Expect(err).ToNot(BeNil())
+		_, err = ukoService.RotateKeystoreCredentials(new(ukov4.RotateKeystoreCredentialsOptions))
+		Expect(err).ToNot(BeNil())
+		_, err = ukoService.RotateKeystoreCredentials(newOptions(ukoService, &ukov4.KeystoreCredentials{IbmApiKey: core.StringPtr("k")}))
+		Expect(err).ToNot(BeNil())
+		Expect(updates).To(BeEmpty())
+	})
+	It(`Rotate credentials of a keystore that becomes healthy`, func() {
+		healthStatuses = []string{"not_responding", "ok"}
+		ukoService := newService()
+		result, err := ukoService.RotateKeystoreCredentials(newOptions(ukoService, awsCredentials("AKIA2", "new")).
+			SetPreviousCredentials(awsCredentials("AKIA1", "old")))
+		Expect(err).To(BeNil())
+		Expect(*result.Healthy).To(BeTrue())
+		Expect(*result.RolledBack).To(BeFalse())
+		Expect(*result.HealthStatus).To(Equal("ok"))
+		Expect(updates).To(Equal([]map[string]interface{}{
+			{"aws_access_key_id": "AKIA2", "aws_secret_access_key": "new"},
+		}))
+	})
+	It(`Restore the previous credentials when the keystore stays unhealthy`, func() {
+		ukoService := newService()
+		result, err := ukoService.RotateKeystoreCredentials(newOptions(ukoService, awsCredentials("AKIA2", "new")).
+			SetPreviousCredentials(awsCredentials("AKIA1", "old")))
+		Expect(err).ToNot(BeNil())
+		unhealthyErr, ok := err.(*ukov4.KeystoreUnhealthyError)
+		Expect(ok).To(BeTrue())
+		Expect(unhealthyErr.RolledBack).To(BeTrue())
+		Expect(unhealthyErr.HealthStatus).To(Equal("not_responding"))
+		Expect(*result.Healthy).To(BeFalse())
+		Expect(*result.RolledBack).To(BeTrue())
+		Expect(updates).To(HaveLen(2))
+		Expect(updates[1]).To(Equal(map[string]interface{}{"aws_access_key_id": "AKIA1", "aws_secret_access_key": "old"}))
+	})
+	It(`Keep polling and restore the previous credentials when the status cannot be read (negative test)`, func() {
+		statusCode = 500
+		ukoService := newService()
+		result, err := ukoService.RotateKeystoreCredentials(newOptions(ukoService, awsCredentials("AKIA2", "new")).
+			SetPreviousCredentials(awsCredentials("AKIA1", "old")))
+		Expect(err).ToNot(BeNil())
+		unhealthyErr, ok := err.(*ukov4.KeystoreUnhealthyError)
+		Expect(ok).To(BeTrue())
+		Expect(unhealthyErr.RolledBack).To(BeTrue())
+		Expect(unhealthyErr.HealthStatus).To(Equal(""))
+		Expect(unhealthyErr.Message).To(ContainSubstring("status unavailable"))
+		Expect(statusReads).To(BeNumerically(">", 1))
+		Expect(*result.Healthy).To(BeFalse())
+		Expect(*result.RolledBack).To(BeTrue())
+		Expect(updates).To(HaveLen(2))
+		Expect(updates[1]).To(Equal(map[string]interface{}{"aws_access_key_id": "AKIA1", "aws_secret_access_key": "old"}))
+	})
+	It(`Restore the previous credentials when the context is cancelled`, func() {
+		ukoService := newService()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		cancelOnStatus = cancel
+		result, err := ukoService.RotateKeystoreCredentialsWithContext(ctx, newOptions(ukoService, awsCredentials("AKIA2", "new")).
+			SetPreviousCredentials(awsCredentials("AKIA1", "old")).
+			SetTimeout(time.Minute))
+		Expect(err).To(Equal(context.Canceled))
+		Expect(*result.Healthy).To(BeFalse())
+		Expect(*result.RolledBack).To(BeTrue())
+		Expect(updates).To(HaveLen(2))
+		Expect(updates[1]).To(Equal(map[string]interface{}{"aws_access_key_id": "AKIA1", "aws_secret_access_key": "old"}))
+	})
+	It(`Report an unhealthy keystore without previous credentials`, func() {
+		keystoreType = "google_kms"
+		ukoService := newService()
+		result, err := ukoService.RotateKeystoreCredentials(newOptions(ukoService, &ukov4.KeystoreCredentials{
+			GoogleCredentials:  core.StringPtr("e30="),
+			GooglePrivateKeyID: core.StringPtr("key2"),
+		}))
+		Expect(err).ToNot(BeNil())
+		Expect(err.(*ukov4.KeystoreUnhealthyError).RolledBack).To(BeFalse())
+		Expect(*result.RolledBack).To(BeFalse())
+		Expect(updates).To(Equal([]map[string]interface{}{
+			{"google_credentials": "e30=", "google_private_key_id": "key2"},
+		}))
+	})
+	It(`Refuse to rotate the credentials of a CCA keystore`, func() {
+		keystoreType = "cca"
+		ukoService := newService()
+		_, err := ukoService.RotateKeystoreCredentials(newOptions(ukoService, awsCredentials("AKIA2", "new")))
+		Expect(err).ToNot(BeNil())
+		Expect(err.Error()).To(ContainSubstring("no credentials to rotate"))
+	})
+})
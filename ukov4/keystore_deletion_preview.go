@@ -0,0 +1,244 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+)
+
+// PreviewDeleteKeystore : Preview the impact of deleting a keystore
+// Lists the managed keys in the keystore together with their states, the other keystores they are referenced in and
+// their associated resources, and describes what would happen to each key if the keystore were deleted with the
+// specified mode. Nothing is modified.
+func (uko *UkoV4) PreviewDeleteKeystore(previewDeleteKeystoreOptions *PreviewDeleteKeystoreOptions) (result *KeystoreDeletionPreview, err error) {
+	return uko.PreviewDeleteKeystoreWithContext(context.Background(), previewDeleteKeystoreOptions)
+}
+
+// PreviewDeleteKeystoreWithContext is an alternate form of the PreviewDeleteKeystore method which supports a Context parameter
+func (uko *UkoV4) PreviewDeleteKeystoreWithContext(ctx context.Context, previewDeleteKeystoreOptions *PreviewDeleteKeystoreOptions) (result *KeystoreDeletionPreview, err error) {
+	err = core.ValidateNotNil(previewDeleteKeystoreOptions, "previewDeleteKeystoreOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(previewDeleteKeystoreOptions, "previewDeleteKeystoreOptions")
+	if err != nil {
+		return
+	}
+
+	mode := DeleteKeystoreOptions_Mode_Restrict
+	if previewDeleteKeystoreOptions.Mode != nil {
+		mode = *previewDeleteKeystoreOptions.Mode
+	}
+	switch mode {
+	case DeleteKeystoreOptions_Mode_Restrict, DeleteKeystoreOptions_Mode_Deactivate,
+		DeleteKeystoreOptions_Mode_Destroy, DeleteKeystoreOptions_Mode_Disconnect:
+	default:
+		err = fmt.Errorf("unsupported keystore deletion mode '%s'", mode)
+		return
+	}
+
+	keystoreID := *previewDeleteKeystoreOptions.ID
+	pager, err := uko.NewManagedKeysFromKeystorePager(&ListManagedKeysFromKeystoreOptions{ID: core.StringPtr(keystoreID)})
+	if err != nil {
+		return
+	}
+	keys, err := pager.GetAllWithContext(ctx)
+	if err != nil {
+		return
+	}
+
+	preview := &KeystoreDeletionPreview{
+		KeystoreID:    core.StringPtr(keystoreID),
+		Mode:          core.StringPtr(mode),
+		Keys:          []KeystoreDeletionKeyImpact{},
+		OutcomeCounts: map[string]int64{},
+	}
+	for i := range keys {
+		key := &keys[i]
+		impact := KeystoreDeletionKeyImpact{
+			KeyID: key.ID,
+			Label: key.Label,
+			State: key.State,
+		}
+		for _, keystore := range key.ReferencedKeystores {
+			if keystore.ID == nil || *keystore.ID != keystoreID {
+				impact.OtherKeystores = append(impact.OtherKeystores, keystore)
+			}
+		}
+		var resourcesPager *AssociatedResourcesForManagedKeyPager
+		resourcesPager, err = uko.NewAssociatedResourcesForManagedKeyPager(&ListAssociatedResourcesForManagedKeyOptions{ID: key.ID})
+		if err != nil {
+			return
+		}
+		impact.AssociatedResources, err = resourcesPager.GetAllWithContext(ctx)
+		if err != nil {
+			return
+		}
+		impact.describe(mode)
+		if *impact.Outcome == KeystoreDeletionKeyImpact_Outcome_BlocksDeletion {
+			preview.Blocked = core.BoolPtr(true)
+		}
+		preview.OutcomeCounts[*impact.Outcome]++
+		preview.Keys = append(preview.Keys, impact)
+	}
+	if preview.Blocked == nil {
+		preview.Blocked = core.BoolPtr(false)
+	}
+	result = preview
+	return
+}
+
+// describe fills in the outcome of the key impact for the specified deletion mode.
+func (impact *KeystoreDeletionKeyImpact) describe(mode string) {
+	state := core.StringNilMapper(impact.State)
+	var outcome, description string
+	switch mode {
+	case DeleteKeystoreOptions_Mode_Restrict:
+		if state == ManagedKey_State_Destroyed || state == ManagedKey_State_DestroyedCompromised {
+			outcome, description = KeystoreDeletionKeyImpact_Outcome_Unchanged, "the key is already destroyed"
+		} else {
+			outcome, description = KeystoreDeletionKeyImpact_Outcome_BlocksDeletion,
+				fmt.Sprintf("the keystore cannot be deleted in restrict mode while it contains a key in state '%s'", state)
+		}
+	case DeleteKeystoreOptions_Mode_Deactivate:
+		if state == ManagedKey_State_Active {
+			outcome, description = KeystoreDeletionKeyImpact_Outcome_Deactivated, "the key will be deactivated"
+		} else {
+			outcome, description = KeystoreDeletionKeyImpact_Outcome_Unchanged, fmt.Sprintf("the key is not active (state '%s')", state)
+		}
+	case DeleteKeystoreOptions_Mode_Destroy:
+		switch state {
+		case ManagedKey_State_Destroyed, ManagedKey_State_DestroyedCompromised:
+			outcome, description = KeystoreDeletionKeyImpact_Outcome_Unchanged, "the key is already destroyed"
+		case ManagedKey_State_Active:
+			outcome, description = KeystoreDeletionKeyImpact_Outcome_Destroyed, "the key will be deactivated and destroyed"
+		default:
+			outcome, description = KeystoreDeletionKeyImpact_Outcome_Destroyed, "the key will be destroyed"
+		}
+	case DeleteKeystoreOptions_Mode_Disconnect:
+		outcome, description = KeystoreDeletionKeyImpact_Outcome_Disconnected,
+			"the key remains in the external keystore but is no longer managed there"
+	}
+
+	if outcome == KeystoreDeletionKeyImpact_Outcome_Deactivated || outcome == KeystoreDeletionKeyImpact_Outcome_Destroyed {
+		if len(impact.OtherKeystores) > 0 {
+			description += "; this also affects the key in " + describeKeystores(impact.OtherKeystores)
+		}
+		if len(impact.AssociatedResources) > 0 {
+			description += fmt.Sprintf("; %d associated resources will lose access to the key", len(impact.AssociatedResources))
+		}
+	}
+	impact.Outcome = core.StringPtr(outcome)
+	impact.Description = core.StringPtr(description)
+}
+
+// describeKeystores returns a human-readable list of keystore references.
+func describeKeystores(keystores []TargetKeystoreReference) string {
+	names := make([]string, 0, len(keystores))
+	for _, keystore := range keystores {
+		name := core.StringNilMapper(keystore.Name)
+		if name == "" {
+			name = core.StringNilMapper(keystore.ID)
+		}
+		names = append(names, fmt.Sprintf("'%s' (%s)", name, core.StringNilMapper(keystore.Type)))
+	}
+	return strings.Join(names, ", ")
+}
+
+// PreviewDeleteKeystoreOptions : The PreviewDeleteKeystore options.
+type PreviewDeleteKeystoreOptions struct {
+	// UUID of the keystore.
+	ID *string `json:"id" validate:"required,ne="`
+
+	// Mode of disconnecting from keystore. Defaults to restrict.
+	Mode *string `json:"mode,omitempty"`
+}
+
+// NewPreviewDeleteKeystoreOptions : Instantiate PreviewDeleteKeystoreOptions
+func (*UkoV4) NewPreviewDeleteKeystoreOptions(id string) *PreviewDeleteKeystoreOptions {
+	return &PreviewDeleteKeystoreOptions{
+		ID: core.StringPtr(id),
+	}
+}
+
+// SetID : Allow user to set ID
+func (_options *PreviewDeleteKeystoreOptions) SetID(id string) *PreviewDeleteKeystoreOptions {
+	_options.ID = core.StringPtr(id)
+	return _options
+}
+
+// SetMode : Allow user to set Mode
+func (_options *PreviewDeleteKeystoreOptions) SetMode(mode string) *PreviewDeleteKeystoreOptions {
+	_options.Mode = core.StringPtr(mode)
+	return _options
+}
+
+// KeystoreDeletionPreview : The expected impact of deleting a keystore with a given mode.
+type KeystoreDeletionPreview struct {
+	// UUID of the keystore.
+	KeystoreID *string `json:"keystore_id"`
+
+	// Mode of disconnecting from keystore.
+	Mode *string `json:"mode"`
+
+	// Whether the deletion would be rejected because of the keys in the keystore.
+	Blocked *bool `json:"blocked"`
+
+	// The expected impact on each managed key in the keystore.
+	Keys []KeystoreDeletionKeyImpact `json:"keys"`
+
+	// The number of keys for each outcome.
+	OutcomeCounts map[string]int64 `json:"outcome_counts"`
+}
+
+// KeystoreDeletionKeyImpact : The expected impact of deleting a keystore on one of its managed keys.
+type KeystoreDeletionKeyImpact struct {
+	// The v4 UUID of the managed key.
+	KeyID *string `json:"key_id"`
+
+	// The label of the key.
+	Label *string `json:"label,omitempty"`
+
+	// The state of the key.
+	State *string `json:"state"`
+
+	// The other keystores the key is referenced in.
+	OtherKeystores []TargetKeystoreReference `json:"other_keystores,omitempty"`
+
+	// The resources associated with the key.
+	AssociatedResources []AssociatedResource `json:"associated_resources,omitempty"`
+
+	// What would happen to the key.
+	Outcome *string `json:"outcome"`
+
+	// A human-readable description of the outcome.
+	Description *string `json:"description"`
+}
+
+// Constants associated with the KeystoreDeletionKeyImpact.Outcome property.
+// What would happen to the key.
+const (
+	KeystoreDeletionKeyImpact_Outcome_BlocksDeletion = "blocks_deletion"
+	KeystoreDeletionKeyImpact_Outcome_Deactivated    = "deactivated"
+	KeystoreDeletionKeyImpact_Outcome_Destroyed      = "destroyed"
+	KeystoreDeletionKeyImpact_Outcome_Disconnected   = "disconnected"
+	KeystoreDeletionKeyImpact_Outcome_Unchanged      = "unchanged"
+)
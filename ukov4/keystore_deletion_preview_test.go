@@ -0,0 +1,104 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-hpcs-uko-sdk/ukov4"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`PreviewDeleteKeystore`, func() {
+	var testServer *httptest.Server
+
+	BeforeEach(func() {
+		testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+
+			Expect(req.Method).To(Equal("GET"))
+			res.Header().Set("Content-type", "application/json")
+			switch req.URL.EscapedPath() {
+			case "/api/v4/keystores/ks1/managed_keys":
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"total_count": 2, "limit": 2, "offset": 0, "managed_keys": [`+
+					`{"id": "k1", "label": "ACTIVE", "state": "active", "algorithm": "aes", "instances": [], "status_in_keystores": [], "referenced_keystores": [{"id": "ks1", "name": "aws", "type": "aws_kms"}, {"id": "ks2", "name": "azure", "type": "azure_key_vault"}]},`+
+					`{"id": "k2", "label": "DESTROYED", "state": "destroyed", "algorithm": "aes", "instances": [], "status_in_keystores": [], "referenced_keystores": [{"id": "ks1", "name": "aws", "type": "aws_kms"}]}]}`)
+			case "/api/v4/managed_keys/k1/associated_resources":
+				res.WriteHeader(200)
+				fmt.Fprint(res, mockRegisteredResources)
+			case "/api/v4/managed_keys/k2/associated_resources":
+				res.WriteHeader(200)
+				fmt.Fprint(res, mockNoResources)
+			default:
+				Fail(fmt.Sprintf("unexpected request %s %s", req.Method, req.URL.EscapedPath()))
+			}
+		}))
+	})
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	newService := func() *ukov4.UkoV4 {
+		ukoService, serviceErr := ukov4.NewUkoV4(&ukov4.UkoV4Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+		return ukoService
+	}
+
+	It(`Invoke PreviewDeleteKeystore with invalid options (negative test)`, func() {
+		ukoService := newService()
+		_, err := ukoService.PreviewDeleteKeystore(nil)
+		Expect(err).ToNot(BeNil())
+		_, err = ukoService.PreviewDeleteKeystore(ukoService.NewPreviewDeleteKeystoreOptions("ks1").SetMode("obliterate"))
+		Expect(err).ToNot(BeNil())
+	})
+	It(`Report blocking keys in restrict mode`, func() {
+		ukoService := newService()
+		preview, err := ukoService.PreviewDeleteKeystore(ukoService.NewPreviewDeleteKeystoreOptions("ks1"))
+		Expect(err).To(BeNil())
+		Expect(*preview.Mode).To(Equal(ukov4.DeleteKeystoreOptions_Mode_Restrict))
+		Expect(*preview.Blocked).To(BeTrue())
+		Expect(*preview.Keys[0].Outcome).To(Equal(ukov4.KeystoreDeletionKeyImpact_Outcome_BlocksDeletion))
+		Expect(*preview.Keys[1].Outcome).To(Equal(ukov4.KeystoreDeletionKeyImpact_Outcome_Unchanged))
+	})
+	It(`Describe the impact of destroy mode`, func() {
+		ukoService := newService()
+		options := ukoService.NewPreviewDeleteKeystoreOptions("ks1").SetMode(ukov4.DeleteKeystoreOptions_Mode_Destroy)
+		preview, err := ukoService.PreviewDeleteKeystore(options)
+		Expect(err).To(BeNil())
+		Expect(*preview.Blocked).To(BeFalse())
+		Expect(preview.Keys[0].OtherKeystores).To(HaveLen(1))
+		Expect(preview.Keys[0].AssociatedResources).To(HaveLen(1))
+		Expect(*preview.Keys[0].Outcome).To(Equal(ukov4.KeystoreDeletionKeyImpact_Outcome_Destroyed))
+		Expect(*preview.Keys[0].Description).To(ContainSubstring("'azure' (azure_key_vault)"))
+		Expect(preview.OutcomeCounts).To(Equal(map[string]int64{"destroyed": 1, "unchanged": 1}))
+	})
+	It(`Describe the impact of disconnect mode`, func() {
+		ukoService := newService()
+		options := ukoService.NewPreviewDeleteKeystoreOptions("ks1").SetMode(ukov4.DeleteKeystoreOptions_Mode_Disconnect)
+		preview, err := ukoService.PreviewDeleteKeystore(options)
+		Expect(err).To(BeNil())
+		Expect(preview.OutcomeCounts).To(Equal(map[string]int64{"disconnected": 2}))
+	})
+})
@@ -0,0 +1,260 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4
+
+import (
+	"context"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+)
+
+// ReconcileKeystoreDrift : Detect and optionally repair drift between managed keys and their keystores
+// Scans every managed key in the vault and collects the keystore statuses flagged out_of_sync or error, classifying each
+// of them by its keystore_sync_flag_detail. When Sync is set, keys whose drift is entirely safe to repair are
+// synchronized with SyncManagedKey; drift that could destroy key material or that needs an operator, such as a removed
+// keystore or a connection error, is only reported.
+func (uko *UkoV4) ReconcileKeystoreDrift(reconcileKeystoreDriftOptions *ReconcileKeystoreDriftOptions) (result *KeystoreDriftReport, err error) {
+	return uko.ReconcileKeystoreDriftWithContext(context.Background(), reconcileKeystoreDriftOptions)
+}
+
+// ReconcileKeystoreDriftWithContext is an alternate form of the ReconcileKeystoreDrift method which supports a Context parameter
+func (uko *UkoV4) ReconcileKeystoreDriftWithContext(ctx context.Context, reconcileKeystoreDriftOptions *ReconcileKeystoreDriftOptions) (result *KeystoreDriftReport, err error) {
+	err = core.ValidateNotNil(reconcileKeystoreDriftOptions, "reconcileKeystoreDriftOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(reconcileKeystoreDriftOptions, "reconcileKeystoreDriftOptions")
+	if err != nil {
+		return
+	}
+
+	pager, err := uko.NewManagedKeysPager(&ListManagedKeysOptions{
+		VaultID: []string{*reconcileKeystoreDriftOptions.VaultID},
+	})
+	if err != nil {
+		return
+	}
+	keys, err := pager.GetAllWithContext(ctx)
+	if err != nil {
+		return
+	}
+
+	sync := reconcileKeystoreDriftOptions.Sync != nil && *reconcileKeystoreDriftOptions.Sync
+	report := &KeystoreDriftReport{
+		VaultID:       reconcileKeystoreDriftOptions.VaultID,
+		KeysScanned:   core.Int64Ptr(int64(len(keys))),
+		KeysDrifted:   core.Int64Ptr(0),
+		KeysSynced:    core.Int64Ptr(0),
+		KeysNotSynced: core.Int64Ptr(0),
+		DetailCounts:  map[string]int64{},
+		Drift:         []KeystoreDrift{},
+	}
+	for i := range keys {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		key := &keys[i]
+		drift := classifyKeystoreDrift(key)
+		if len(drift) == 0 {
+			continue
+		}
+		*report.KeysDrifted++
+
+		fixable := true
+		for _, entry := range drift {
+			report.DetailCounts[*entry.KeystoreSyncFlagDetail]++
+			fixable = fixable && *entry.Fixable
+		}
+		if sync && fixable {
+			err = uko.syncDriftedKey(ctx, key)
+			for j := range drift {
+				drift[j].Synced = core.BoolPtr(err == nil)
+				if err != nil {
+					drift[j].SyncError = core.StringPtr(err.Error())
+				}
+			}
+			if err == nil {
+				*report.KeysSynced++
+			} else {
+				*report.KeysNotSynced++
+			}
+			err = nil
+		}
+		report.Drift = append(report.Drift, drift...)
+	}
+	result = report
+	return
+}
+
+// syncDriftedKey synchronizes a managed key with its keystores using its current ETag.
+func (uko *UkoV4) syncDriftedKey(ctx context.Context, key *ManagedKey) error {
+	_, response, err := uko.GetManagedKeyWithContext(ctx, &GetManagedKeyOptions{ID: key.ID})
+	if err != nil {
+		return err
+	}
+	_, _, err = uko.SyncManagedKeyWithContext(ctx, &SyncManagedKeyOptions{
+		ID:      key.ID,
+		IfMatch: core.StringPtr(getETag(response)),
+	})
+	return err
+}
+
+// classifyKeystoreDrift returns the drift entries of the managed key, one per keystore status flagged out_of_sync or
+// error.
+func classifyKeystoreDrift(key *ManagedKey) (drift []KeystoreDrift) {
+	for _, status := range key.StatusInKeystores {
+		flag := core.StringNilMapper(status.KeystoreSyncFlag)
+		if flag != StatusInKeystore_KeystoreSyncFlag_OutOfSync && flag != StatusInKeystore_KeystoreSyncFlag_Error {
+			continue
+		}
+		detail := core.StringNilMapper(status.KeystoreSyncFlagDetail)
+		category, ok := keystoreDriftCategories[detail]
+		if !ok {
+			category = KeystoreDrift_Category_Unknown
+		}
+		entry := KeystoreDrift{
+			KeyID:                  key.ID,
+			Label:                  key.Label,
+			State:                  key.State,
+			Keystore:               status.Keystore,
+			Status:                 status.Status,
+			KeystoreSyncFlag:       status.KeystoreSyncFlag,
+			KeystoreSyncFlagDetail: core.StringPtr(detail),
+			Category:               core.StringPtr(category),
+			Fixable:                core.BoolPtr(category == KeystoreDrift_Category_StateMismatch),
+		}
+		if status.Error != nil && len(status.Error.Errors) > 0 {
+			entry.Error = status.Error.Errors[0].Message
+		}
+		drift = append(drift, entry)
+	}
+	return
+}
+
+// keystoreDriftCategories maps each keystore_sync_flag_detail that represents drift to its category. Only state
+// mismatches are repaired automatically: SyncManagedKey re-applies the managed key state to the keystore without
+// removing key material that may still be in use.
+var keystoreDriftCategories = map[string]string{
+	StatusInKeystore_KeystoreSyncFlagDetail_ActiveKeyIsNotActiveInKeystore:                 KeystoreDrift_Category_StateMismatch,
+	StatusInKeystore_KeystoreSyncFlagDetail_DeactivatedKeyIsNotDeactivatedInKeystore:       KeystoreDrift_Category_StateMismatch,
+	StatusInKeystore_KeystoreSyncFlagDetail_PreActiveKeyIsPresentInKeystore:                KeystoreDrift_Category_StateMismatch,
+	StatusInKeystore_KeystoreSyncFlagDetail_DestroyedKeyIsPresentInKeystore:                KeystoreDrift_Category_KeyMaterial,
+	StatusInKeystore_KeystoreSyncFlagDetail_ConnectionError:                                KeystoreDrift_Category_Connectivity,
+	StatusInKeystore_KeystoreSyncFlagDetail_TargetKeystoreRemovedByUser:                    KeystoreDrift_Category_KeystoreRemoved,
+	StatusInKeystore_KeystoreSyncFlagDetail_TargetKeystoreRemovedByUserContainsAnActiveKey: KeystoreDrift_Category_KeystoreRemoved,
+}
+
+// ReconcileKeystoreDriftOptions : The ReconcileKeystoreDrift options.
+type ReconcileKeystoreDriftOptions struct {
+	// The UUID of the vault to scan.
+	VaultID *string `json:"vault_id" validate:"required,ne="`
+
+	// Synchronize the keys whose drift is safe to repair.
+	Sync *bool `json:"sync,omitempty"`
+}
+
+// NewReconcileKeystoreDriftOptions : Instantiate ReconcileKeystoreDriftOptions
+func (*UkoV4) NewReconcileKeystoreDriftOptions(vaultID string) *ReconcileKeystoreDriftOptions {
+	return &ReconcileKeystoreDriftOptions{
+		VaultID: core.StringPtr(vaultID),
+	}
+}
+
+// SetVaultID : Allow user to set VaultID
+func (_options *ReconcileKeystoreDriftOptions) SetVaultID(vaultID string) *ReconcileKeystoreDriftOptions {
+	_options.VaultID = core.StringPtr(vaultID)
+	return _options
+}
+
+// SetSync : Allow user to set Sync
+func (_options *ReconcileKeystoreDriftOptions) SetSync(sync bool) *ReconcileKeystoreDriftOptions {
+	_options.Sync = core.BoolPtr(sync)
+	return _options
+}
+
+// KeystoreDriftReport : The summary of a ReconcileKeystoreDrift run.
+type KeystoreDriftReport struct {
+	// The UUID of the scanned vault.
+	VaultID *string `json:"vault_id"`
+
+	// The number of managed keys scanned.
+	KeysScanned *int64 `json:"keys_scanned"`
+
+	// The number of managed keys with at least one drifted keystore.
+	KeysDrifted *int64 `json:"keys_drifted"`
+
+	// The number of managed keys successfully synchronized.
+	KeysSynced *int64 `json:"keys_synced"`
+
+	// The number of managed keys whose synchronization failed.
+	KeysNotSynced *int64 `json:"keys_not_synced"`
+
+	// The number of drift entries for each keystore_sync_flag_detail.
+	DetailCounts map[string]int64 `json:"detail_counts"`
+
+	// The drift entries, one per managed key and keystore.
+	Drift []KeystoreDrift `json:"drift"`
+}
+
+// KeystoreDrift : A managed key whose state in a keystore differs from its state in UKO.
+type KeystoreDrift struct {
+	// The v4 UUID of the managed key.
+	KeyID *string `json:"key_id"`
+
+	// The label of the key.
+	Label *string `json:"label,omitempty"`
+
+	// The state of the key.
+	State *string `json:"state,omitempty"`
+
+	// Reference to a target keystore.
+	Keystore *TargetKeystoreReference `json:"keystore,omitempty"`
+
+	// Possible states of a key in keystore.
+	Status *string `json:"status,omitempty"`
+
+	// Flag to represent synchronization status between UKO Managed Key and Target Keystore.
+	KeystoreSyncFlag *string `json:"keystore_sync_flag"`
+
+	// Detailed description of the mismatch between UKO Managed Key and Target Keystore.
+	KeystoreSyncFlagDetail *string `json:"keystore_sync_flag_detail"`
+
+	// The kind of drift.
+	Category *string `json:"category"`
+
+	// Whether the drift can be safely repaired with SyncManagedKey.
+	Fixable *bool `json:"fixable"`
+
+	// The message of the error reported by the keystore, if any.
+	Error *string `json:"error,omitempty"`
+
+	// Whether the key was synchronized; not set if no synchronization was attempted.
+	Synced *bool `json:"synced,omitempty"`
+
+	// The error message of a failed synchronization.
+	SyncError *string `json:"sync_error,omitempty"`
+}
+
+// Constants associated with the KeystoreDrift.Category property.
+// The kind of drift.
+const (
+	KeystoreDrift_Category_Connectivity    = "connectivity"
+	KeystoreDrift_Category_KeyMaterial     = "key_material"
+	KeystoreDrift_Category_KeystoreRemoved = "keystore_removed"
+	KeystoreDrift_Category_StateMismatch   = "state_mismatch"
+	KeystoreDrift_Category_Unknown         = "unknown"
+)
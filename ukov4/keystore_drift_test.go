@@ -0,0 +1,111 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-hpcs-uko-sdk/ukov4"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`ReconcileKeystoreDrift`, func() {
+	var testServer *httptest.Server
+	var synced []string
+
+	BeforeEach(func() {
+		synced = nil
+		testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+
+			res.Header().Set("Content-type", "application/json")
+			switch req.Method + " " + req.URL.EscapedPath() {
+			case "GET /api/v4/managed_keys":
+				Expect(req.URL.Query().Get("vault.id")).To(Equal("vault1"))
+				res.WriteHeader(200)
+				fmt.Fprintf(res, `{"total_count": 3, "limit": 3, "offset": 0, "managed_keys": [%s, %s, %s]}`,
+					mockDriftedKey("k1", "active", "out_of_sync", "active_key_is_not_active_in_keystore"),
+					mockDriftedKey("k2", "destroyed", "out_of_sync", "destroyed_key_is_present_in_keystore"),
+					mockDriftedKey("k3", "active", "ok", "active_key_is_active_in_keystore"))
+			case "GET /api/v4/managed_keys/k1":
+				res.Header().Set("ETag", "etag-k1")
+				res.WriteHeader(200)
+				fmt.Fprint(res, mockDriftedKey("k1", "active", "out_of_sync", "active_key_is_not_active_in_keystore"))
+			case "POST /api/v4/managed_keys/k1/sync_status_in_keystores":
+				Expect(req.Header.Get("If-Match")).To(Equal("etag-k1"))
+				synced = append(synced, "k1")
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"status_in_keystores": []}`)
+			default:
+				Fail(fmt.Sprintf("unexpected request %s %s", req.Method, req.URL.EscapedPath()))
+			}
+		}))
+	})
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	newService := func() *ukov4.UkoV4 {
+		ukoService, serviceErr := ukov4.NewUkoV4(&ukov4.UkoV4Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+		return ukoService
+	}
+
+	It(`Invoke ReconcileKeystoreDrift with invalid options (negative test)`, func() {
+		ukoService := newService()
+		_, err := ukoService.ReconcileKeystoreDrift(nil)
+		Expect(err).ToNot(BeNil())
+		_, err = ukoService.ReconcileKeystoreDrift(new(ukov4.ReconcileKeystoreDriftOptions))
+		Expect(err).ToNot(BeNil())
+	})
+	It(`Classify drift without synchronizing`, func() {
+		ukoService := newService()
+		report, err := ukoService.ReconcileKeystoreDrift(ukoService.NewReconcileKeystoreDriftOptions("vault1"))
+		Expect(err).To(BeNil())
+		Expect(*report.KeysScanned).To(Equal(int64(3)))
+		Expect(*report.KeysDrifted).To(Equal(int64(2)))
+		Expect(report.Drift).To(HaveLen(2))
+		Expect(*report.Drift[0].Category).To(Equal(ukov4.KeystoreDrift_Category_StateMismatch))
+		Expect(*report.Drift[0].Fixable).To(BeTrue())
+		Expect(report.Drift[0].Synced).To(BeNil())
+		Expect(*report.Drift[1].Category).To(Equal(ukov4.KeystoreDrift_Category_KeyMaterial))
+		Expect(*report.Drift[1].Fixable).To(BeFalse())
+		Expect(report.DetailCounts).To(HaveKeyWithValue("destroyed_key_is_present_in_keystore", int64(1)))
+		Expect(synced).To(BeEmpty())
+	})
+	It(`Synchronize only keys with fixable drift`, func() {
+		ukoService := newService()
+		report, err := ukoService.ReconcileKeystoreDrift(ukoService.NewReconcileKeystoreDriftOptions("vault1").SetSync(true))
+		Expect(err).To(BeNil())
+		Expect(synced).To(Equal([]string{"k1"}))
+		Expect(*report.KeysSynced).To(Equal(int64(1)))
+		Expect(*report.Drift[0].Synced).To(BeTrue())
+		Expect(report.Drift[1].Synced).To(BeNil())
+	})
+})
+
+func mockDriftedKey(id string, state string, flag string, detail string) string {
+	return fmt.Sprintf(`{"id": "%s", "label": "KEY-%s", "state": "%s", "algorithm": "aes", "referenced_keystores": [], "instances": [], "status_in_keystores": [{"keystore": {"id": "ks1", "name": "aws", "type": "aws_kms"}, "status": "not_active", "keystore_sync_flag": "%s", "keystore_sync_flag_detail": "%s"}]}`,
+		id, id, state, flag, detail)
+}
@@ -0,0 +1,469 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+)
+
+// keystoreGroupUpdateAttempts is the number of times the groups of a keystore are read and updated before a
+// concurrent modification is reported as an error.
+const keystoreGroupUpdateAttempts = 3
+
+// ListKeystoreGroups : List keystore groups
+// Lists the groups of the keystores in the specified vaults, or in all vaults, with the keystores in each group and
+// the templates that distribute keys to it. Groups are scoped to a vault, so a group name used in two vaults is listed
+// twice. Groups that are targeted by a template but contain no keystore are listed too. Groups are sorted by vault ID
+// and name.
+func (uko *UkoV4) ListKeystoreGroups(listKeystoreGroupsOptions *ListKeystoreGroupsOptions) (result []KeystoreGroup, err error) {
+	return uko.ListKeystoreGroupsWithContext(context.Background(), listKeystoreGroupsOptions)
+}
+
+// ListKeystoreGroupsWithContext is an alternate form of the ListKeystoreGroups method which supports a Context parameter
+func (uko *UkoV4) ListKeystoreGroupsWithContext(ctx context.Context, listKeystoreGroupsOptions *ListKeystoreGroupsOptions) (result []KeystoreGroup, err error) {
+	if listKeystoreGroupsOptions == nil {
+		listKeystoreGroupsOptions = &ListKeystoreGroupsOptions{}
+	}
+	vaultID := listKeystoreGroupsOptions.VaultID
+
+	keystoresPager, err := uko.NewKeystoresPager(&ListKeystoresOptions{VaultID: vaultID})
+	if err != nil {
+		return
+	}
+	keystores, err := keystoresPager.GetAllWithContext(ctx)
+	if err != nil {
+		return
+	}
+	templatesPager, err := uko.NewKeyTemplatesPager(&ListKeyTemplatesOptions{VaultID: vaultID})
+	if err != nil {
+		return
+	}
+	templates, err := templatesPager.GetAllWithContext(ctx)
+	if err != nil {
+		return
+	}
+
+	type groupKey struct{ vaultID, name string }
+	groups := map[groupKey]*KeystoreGroup{}
+	group := func(vault *VaultReference, name string) *KeystoreGroup {
+		key := groupKey{name: name}
+		if vault != nil {
+			key.vaultID = core.StringNilMapper(vault.ID)
+		}
+		if groups[key] == nil {
+			groups[key] = &KeystoreGroup{
+				Vault:     vault,
+				Name:      core.StringPtr(name),
+				Keystores: []KeystoreGroupKeystore{},
+				Templates: []KeystoreGroupTemplate{},
+			}
+		}
+		return groups[key]
+	}
+
+	for _, item := range keystores {
+		keystore := new(Keystore)
+		if err = remarshal(item, keystore); err != nil {
+			return
+		}
+		for _, name := range keystore.Groups {
+			member := group(keystore.Vault, name)
+			member.Keystores = append(member.Keystores, KeystoreGroupKeystore{
+				ID:   keystore.ID,
+				Name: keystore.Name,
+				Type: keystore.Type,
+			})
+		}
+	}
+	for i := range templates {
+		template := &templates[i]
+		for _, item := range template.Keystores {
+			var properties *KeystoresPropertiesCreate
+			properties, err = toKeystoresPropertiesCreate(item)
+			if err != nil {
+				return
+			}
+			if properties.Group == nil {
+				continue
+			}
+			target := group(template.Vault, *properties.Group)
+			target.Templates = append(target.Templates, KeystoreGroupTemplate{
+				ID:           template.ID,
+				Name:         template.Name,
+				KeystoreType: properties.Type,
+			})
+		}
+	}
+
+	result = make([]KeystoreGroup, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		vaultI, vaultJ := "", ""
+		if result[i].Vault != nil {
+			vaultI = core.StringNilMapper(result[i].Vault.ID)
+		}
+		if result[j].Vault != nil {
+			vaultJ = core.StringNilMapper(result[j].Vault.ID)
+		}
+		if vaultI != vaultJ {
+			return vaultI < vaultJ
+		}
+		return *result[i].Name < *result[j].Name
+	})
+	return
+}
+
+// AddKeystoreToGroup : Add a keystore to a group
+// Adds a group to the groups of a keystore with UpdateKeystore, using the ETag of the keystore so that concurrent
+// changes to its groups are not lost. The keystore is not updated if it already belongs to the group.
+func (uko *UkoV4) AddKeystoreToGroup(addKeystoreToGroupOptions *AddKeystoreToGroupOptions) (result KeystoreIntf, response *core.DetailedResponse, err error) {
+	return uko.AddKeystoreToGroupWithContext(context.Background(), addKeystoreToGroupOptions)
+}
+
+// AddKeystoreToGroupWithContext is an alternate form of the AddKeystoreToGroup method which supports a Context parameter
+func (uko *UkoV4) AddKeystoreToGroupWithContext(ctx context.Context, addKeystoreToGroupOptions *AddKeystoreToGroupOptions) (result KeystoreIntf, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(addKeystoreToGroupOptions, "addKeystoreToGroupOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(addKeystoreToGroupOptions, "addKeystoreToGroupOptions")
+	if err != nil {
+		return
+	}
+	group := *addKeystoreToGroupOptions.Group
+	return uko.updateKeystoreGroups(ctx, *addKeystoreToGroupOptions.ID, func(groups []string) ([]string, error) {
+		for _, name := range groups {
+			if name == group {
+				return nil, nil
+			}
+		}
+		return append(groups, group), nil
+	})
+}
+
+// RemoveKeystoreFromGroup : Remove a keystore from a group
+// Removes a group from the groups of a keystore with UpdateKeystore, using the ETag of the keystore so that concurrent
+// changes to its groups are not lost. The keystore is not updated if it does not belong to the group. A keystore
+// cannot be removed from its last group, as the update request cannot clear the groups of a keystore.
+func (uko *UkoV4) RemoveKeystoreFromGroup(removeKeystoreFromGroupOptions *RemoveKeystoreFromGroupOptions) (result KeystoreIntf, response *core.DetailedResponse, err error) {
+	return uko.RemoveKeystoreFromGroupWithContext(context.Background(), removeKeystoreFromGroupOptions)
+}
+
+// RemoveKeystoreFromGroupWithContext is an alternate form of the RemoveKeystoreFromGroup method which supports a Context parameter
+func (uko *UkoV4) RemoveKeystoreFromGroupWithContext(ctx context.Context, removeKeystoreFromGroupOptions *RemoveKeystoreFromGroupOptions) (result KeystoreIntf, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(removeKeystoreFromGroupOptions, "removeKeystoreFromGroupOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(removeKeystoreFromGroupOptions, "removeKeystoreFromGroupOptions")
+	if err != nil {
+		return
+	}
+	id := *removeKeystoreFromGroupOptions.ID
+	group := *removeKeystoreFromGroupOptions.Group
+	return uko.updateKeystoreGroups(ctx, id, func(groups []string) ([]string, error) {
+		remaining := make([]string, 0, len(groups))
+		for _, name := range groups {
+			if name != group {
+				remaining = append(remaining, name)
+			}
+		}
+		if len(remaining) == len(groups) {
+			return nil, nil
+		}
+		if len(remaining) == 0 {
+			return nil, fmt.Errorf("keystore '%s' cannot be removed from its only group '%s'", id, group)
+		}
+		return remaining, nil
+	})
+}
+
+// RenameGroup : Rename a keystore group
+// Replaces a group with another one in the groups of every keystore of a vault that belongs to it, with
+// UpdateKeystore. Templates are not changed: the templates that still target the old group are returned in the
+// result, and must be updated with UpdateKeyTemplate for new keys to be distributed to the renamed group. If updating
+// a keystore fails, the keystores updated so far are returned together with the error.
+func (uko *UkoV4) RenameGroup(renameGroupOptions *RenameGroupOptions) (result *KeystoreGroupRenameResult, err error) {
+	return uko.RenameGroupWithContext(context.Background(), renameGroupOptions)
+}
+
+// RenameGroupWithContext is an alternate form of the RenameGroup method which supports a Context parameter
+func (uko *UkoV4) RenameGroupWithContext(ctx context.Context, renameGroupOptions *RenameGroupOptions) (result *KeystoreGroupRenameResult, err error) {
+	err = core.ValidateNotNil(renameGroupOptions, "renameGroupOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(renameGroupOptions, "renameGroupOptions")
+	if err != nil {
+		return
+	}
+	vaultID := *renameGroupOptions.VaultID
+	oldGroup := *renameGroupOptions.Group
+	newGroup := *renameGroupOptions.NewGroup
+
+	groups, err := uko.ListKeystoreGroupsWithContext(ctx, &ListKeystoreGroupsOptions{VaultID: []string{vaultID}})
+	if err != nil {
+		return
+	}
+	result = &KeystoreGroupRenameResult{
+		VaultID:          core.StringPtr(vaultID),
+		Group:            core.StringPtr(oldGroup),
+		NewGroup:         core.StringPtr(newGroup),
+		UpdatedKeystores: []KeystoreGroupKeystore{},
+		Templates:        []KeystoreGroupTemplate{},
+	}
+	if oldGroup == newGroup {
+		return
+	}
+	for _, group := range groups {
+		if *group.Name != oldGroup {
+			continue
+		}
+		result.Templates = group.Templates
+		for _, keystore := range group.Keystores {
+			_, _, err = uko.updateKeystoreGroups(ctx, *keystore.ID, func(groups []string) ([]string, error) {
+				renamed := make([]string, 0, len(groups))
+				found := false
+				for _, name := range groups {
+					switch name {
+					case oldGroup:
+						found = true
+					case newGroup:
+					default:
+						renamed = append(renamed, name)
+					}
+				}
+				if !found {
+					return nil, nil
+				}
+				return append(renamed, newGroup), nil
+			})
+			if err != nil {
+				err = fmt.Errorf("error renaming group '%s' of keystore '%s': %s", oldGroup, *keystore.ID, err.Error())
+				return
+			}
+			result.UpdatedKeystores = append(result.UpdatedKeystores, keystore)
+		}
+	}
+	return
+}
+
+// updateKeystoreGroups reads the groups of a keystore, changes them and updates the keystore if the change returns new
+// groups, using the ETag of the keystore. The change is retried with the current groups if the keystore is modified
+// concurrently.
+func (uko *UkoV4) updateKeystoreGroups(ctx context.Context, id string, change func(groups []string) ([]string, error)) (result KeystoreIntf, response *core.DetailedResponse, err error) {
+	for attempt := 1; ; attempt++ {
+		result, response, err = uko.GetKeystoreWithContext(ctx, &GetKeystoreOptions{ID: core.StringPtr(id)})
+		if err != nil {
+			return
+		}
+		current := new(Keystore)
+		if err = remarshal(result, current); err != nil {
+			return
+		}
+		var groups []string
+		groups, err = change(append([]string{}, current.Groups...))
+		if err != nil || groups == nil {
+			return
+		}
+		result, response, err = uko.UpdateKeystoreWithContext(ctx, &UpdateKeystoreOptions{
+			ID:           core.StringPtr(id),
+			IfMatch:      core.StringPtr(getETag(response)),
+			KeystoreBody: &KeystoreUpdateRequest{Groups: groups},
+		})
+		if err == nil || !isConflict(response) || attempt == keystoreGroupUpdateAttempts {
+			return
+		}
+	}
+}
+
+// ListKeystoreGroupsOptions : The ListKeystoreGroups options.
+type ListKeystoreGroupsOptions struct {
+	// The UUIDs of the vaults whose keystore groups are listed. All vaults are listed if not set.
+	VaultID []string `json:"vault.id,omitempty"`
+}
+
+// NewListKeystoreGroupsOptions : Instantiate ListKeystoreGroupsOptions
+func (*UkoV4) NewListKeystoreGroupsOptions() *ListKeystoreGroupsOptions {
+	return &ListKeystoreGroupsOptions{}
+}
+
+// SetVaultID : Allow user to set VaultID
+func (_options *ListKeystoreGroupsOptions) SetVaultID(vaultID []string) *ListKeystoreGroupsOptions {
+	_options.VaultID = vaultID
+	return _options
+}
+
+// AddKeystoreToGroupOptions : The AddKeystoreToGroup options.
+type AddKeystoreToGroupOptions struct {
+	// UUID of the keystore.
+	ID *string `json:"id" validate:"required,ne="`
+
+	// The group the keystore is added to.
+	Group *string `json:"group" validate:"required,ne="`
+}
+
+// NewAddKeystoreToGroupOptions : Instantiate AddKeystoreToGroupOptions
+func (*UkoV4) NewAddKeystoreToGroupOptions(id string, group string) *AddKeystoreToGroupOptions {
+	return &AddKeystoreToGroupOptions{
+		ID:    core.StringPtr(id),
+		Group: core.StringPtr(group),
+	}
+}
+
+// SetID : Allow user to set ID
+func (_options *AddKeystoreToGroupOptions) SetID(id string) *AddKeystoreToGroupOptions {
+	_options.ID = core.StringPtr(id)
+	return _options
+}
+
+// SetGroup : Allow user to set Group
+func (_options *AddKeystoreToGroupOptions) SetGroup(group string) *AddKeystoreToGroupOptions {
+	_options.Group = core.StringPtr(group)
+	return _options
+}
+
+// RemoveKeystoreFromGroupOptions : The RemoveKeystoreFromGroup options.
+type RemoveKeystoreFromGroupOptions struct {
+	// UUID of the keystore.
+	ID *string `json:"id" validate:"required,ne="`
+
+	// The group the keystore is removed from.
+	Group *string `json:"group" validate:"required,ne="`
+}
+
+// NewRemoveKeystoreFromGroupOptions : Instantiate RemoveKeystoreFromGroupOptions
+func (*UkoV4) NewRemoveKeystoreFromGroupOptions(id string, group string) *RemoveKeystoreFromGroupOptions {
+	return &RemoveKeystoreFromGroupOptions{
+		ID:    core.StringPtr(id),
+		Group: core.StringPtr(group),
+	}
+}
+
+// SetID : Allow user to set ID
+func (_options *RemoveKeystoreFromGroupOptions) SetID(id string) *RemoveKeystoreFromGroupOptions {
+	_options.ID = core.StringPtr(id)
+	return _options
+}
+
+// SetGroup : Allow user to set Group
+func (_options *RemoveKeystoreFromGroupOptions) SetGroup(group string) *RemoveKeystoreFromGroupOptions {
+	_options.Group = core.StringPtr(group)
+	return _options
+}
+
+// RenameGroupOptions : The RenameGroup options.
+type RenameGroupOptions struct {
+	// UUID of the vault the group is in.
+	VaultID *string `json:"vault_id" validate:"required,ne="`
+
+	// The current name of the group.
+	Group *string `json:"group" validate:"required,ne="`
+
+	// The new name of the group.
+	NewGroup *string `json:"new_group" validate:"required,ne="`
+}
+
+// NewRenameGroupOptions : Instantiate RenameGroupOptions
+func (*UkoV4) NewRenameGroupOptions(vaultID string, group string, newGroup string) *RenameGroupOptions {
+	return &RenameGroupOptions{
+		VaultID:  core.StringPtr(vaultID),
+		Group:    core.StringPtr(group),
+		NewGroup: core.StringPtr(newGroup),
+	}
+}
+
+// SetVaultID : Allow user to set VaultID
+func (_options *RenameGroupOptions) SetVaultID(vaultID string) *RenameGroupOptions {
+	_options.VaultID = core.StringPtr(vaultID)
+	return _options
+}
+
+// SetGroup : Allow user to set Group
+func (_options *RenameGroupOptions) SetGroup(group string) *RenameGroupOptions {
+	_options.Group = core.StringPtr(group)
+	return _options
+}
+
+// SetNewGroup : Allow user to set NewGroup
+func (_options *RenameGroupOptions) SetNewGroup(newGroup string) *RenameGroupOptions {
+	_options.NewGroup = core.StringPtr(newGroup)
+	return _options
+}
+
+// KeystoreGroup : A group of keystores in a vault, and the templates that distribute keys to it.
+type KeystoreGroup struct {
+	// Reference to the vault of the group.
+	Vault *VaultReference `json:"vault,omitempty"`
+
+	// The name of the group.
+	Name *string `json:"name"`
+
+	// The keystores in the group.
+	Keystores []KeystoreGroupKeystore `json:"keystores"`
+
+	// The templates that target the group.
+	Templates []KeystoreGroupTemplate `json:"templates"`
+}
+
+// KeystoreGroupKeystore : A keystore in a group.
+type KeystoreGroupKeystore struct {
+	// The v4 UUID of the keystore.
+	ID *string `json:"id"`
+
+	// Name of the keystore.
+	Name *string `json:"name,omitempty"`
+
+	// Type of keystore.
+	Type *string `json:"type"`
+}
+
+// KeystoreGroupTemplate : A template that targets a group.
+type KeystoreGroupTemplate struct {
+	// The v4 UUID of the template.
+	ID *string `json:"id"`
+
+	// Name of the template.
+	Name *string `json:"name"`
+
+	// The type of the keystores in the group that the template distributes keys to.
+	KeystoreType *string `json:"keystore_type"`
+}
+
+// KeystoreGroupRenameResult : The result of renaming a keystore group.
+type KeystoreGroupRenameResult struct {
+	// UUID of the vault the group is in.
+	VaultID *string `json:"vault_id"`
+
+	// The previous name of the group.
+	Group *string `json:"group"`
+
+	// The new name of the group.
+	NewGroup *string `json:"new_group"`
+
+	// The keystores that were moved to the new group.
+	UpdatedKeystores []KeystoreGroupKeystore `json:"updated_keystores"`
+
+	// The templates that still target the previous name of the group.
+	Templates []KeystoreGroupTemplate `json:"templates"`
+}
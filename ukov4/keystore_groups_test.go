@@ -0,0 +1,194 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-hpcs-uko-sdk/ukov4"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`Keystore groups`, func() {
+	type testKeystore struct {
+		id, name, keystoreType string
+		groups                 []string
+		version                int
+	}
+	var testServer *httptest.Server
+	var ukoService *ukov4.UkoV4
+	var keystores []*testKeystore
+	var updates []string
+	var concurrentUpdates int
+
+	keystoreJSON := func(keystore *testKeystore) string {
+		groups, _ := json.Marshal(keystore.groups)
+		return fmt.Sprintf(`{"id": "%s", "vault": {"id": "v1"}, "name": "%s", "type": "%s", "groups": %s}`,
+			keystore.id, keystore.name, keystore.keystoreType, groups)
+	}
+
+	BeforeEach(func() {
+		keystores = []*testKeystore{
+			{id: "ks1", name: "aws-eu", keystoreType: "aws_kms", groups: []string{"eu", "prod"}},
+			{id: "ks2", name: "azure-eu", keystoreType: "azure_key_vault", groups: []string{"eu"}},
+			{id: "ks3", name: "gcp-us", keystoreType: "google_kms", groups: []string{"us"}},
+		}
+		updates = nil
+		concurrentUpdates = 0
+		testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+
+			res.Header().Set("Content-type", "application/json")
+			path := req.Method + " " + req.URL.EscapedPath()
+			switch {
+			case path == "GET /api/v4/keystores":
+				items := make([]string, 0, len(keystores))
+				for _, keystore := range keystores {
+					items = append(items, keystoreJSON(keystore))
+				}
+				res.WriteHeader(200)
+				fmt.Fprintf(res, `{"total_count": %d, "limit": 100, "offset": 0, "keystores": [%s]}`,
+					len(items), strings.Join(items, ","))
+			case path == "GET /api/v4/templates":
+				Expect(req.URL.Query().Get("vault.id")).To(Equal("v1"))
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"total_count": 2, "limit": 100, "offset": 0, "templates": [
+					{"id": "t1", "vault": {"id": "v1"}, "name": "aes-eu", "type": ["user_defined"], "state": "unarchived", "keys_count": 1, "description": "", "key": {"algorithm": "aes"}, "keystores": [{"group": "eu", "type": "aws_kms"}, {"group": "eu", "type": "azure_key_vault"}]},
+					{"id": "t2", "vault": {"id": "v1"}, "name": "aes-apac", "type": ["user_defined"], "state": "unarchived", "keys_count": 0, "description": "", "key": {"algorithm": "aes"}, "keystores": [{"group": "apac", "type": "aws_kms"}]}]}`)
+			case strings.HasPrefix(path, "GET /api/v4/keystores/") || strings.HasPrefix(path, "PATCH /api/v4/keystores/"):
+				var keystore *testKeystore
+				for _, candidate := range keystores {
+					if strings.HasSuffix(path, "/"+candidate.id) {
+						keystore = candidate
+					}
+				}
+				Expect(keystore).ToNot(BeNil())
+				if req.Method == "PATCH" {
+					if concurrentUpdates > 0 {
+						concurrentUpdates--
+						keystore.version++
+					}
+					if req.Header.Get("If-Match") != fmt.Sprintf("etag-%d", keystore.version) {
+						res.WriteHeader(412)
+						fmt.Fprint(res, `{"errors": [{"code": "PRECONDITION_FAILED"}]}`)
+						return
+					}
+					body, _ := ioutil.ReadAll(req.Body)
+					update := map[string][]string{}
+					Expect(json.Unmarshal(body, &update)).To(Succeed())
+					keystore.groups = update["groups"]
+					keystore.version++
+					updates = append(updates, fmt.Sprintf("%s %v", keystore.id, keystore.groups))
+				}
+				res.Header().Set("ETag", fmt.Sprintf("etag-%d", keystore.version))
+				res.WriteHeader(200)
+				fmt.Fprint(res, keystoreJSON(keystore))
+			default:
+				Fail("unexpected request " + path)
+			}
+		}))
+		var err error
+		ukoService, err = ukov4.NewUkoV4(&ukov4.UkoV4Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(err).To(BeNil())
+	})
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	It(`List groups with their keystores and templates`, func() {
+		groups, err := ukoService.ListKeystoreGroups(ukoService.NewListKeystoreGroupsOptions().SetVaultID([]string{"v1"}))
+		Expect(err).To(BeNil())
+		names := []string{}
+		for _, group := range groups {
+			names = append(names, *group.Name)
+		}
+		Expect(names).To(Equal([]string{"apac", "eu", "prod", "us"}))
+
+		apac := groups[0]
+		Expect(apac.Keystores).To(BeEmpty())
+		Expect(apac.Templates).To(HaveLen(1))
+		Expect(*apac.Templates[0].Name).To(Equal("aes-apac"))
+
+		eu := groups[1]
+		Expect(*eu.Vault.ID).To(Equal("v1"))
+		Expect(eu.Keystores).To(HaveLen(2))
+		Expect(*eu.Keystores[1].Type).To(Equal("azure_key_vault"))
+		Expect(eu.Templates).To(HaveLen(2))
+		Expect(*eu.Templates[0].KeystoreType).To(Equal("aws_kms"))
+		Expect(groups[3].Templates).To(BeEmpty())
+	})
+	It(`Add and remove keystores from groups`, func() {
+		result, _, err := ukoService.AddKeystoreToGroup(ukoService.NewAddKeystoreToGroupOptions("ks2", "prod"))
+		Expect(err).To(BeNil())
+		keystore, ok := result.AsAzure()
+		Expect(ok).To(BeTrue())
+		Expect(keystore.Groups).To(Equal([]string{"eu", "prod"}))
+
+		_, _, err = ukoService.AddKeystoreToGroup(ukoService.NewAddKeystoreToGroupOptions("ks2", "prod"))
+		Expect(err).To(BeNil())
+		_, _, err = ukoService.RemoveKeystoreFromGroup(ukoService.NewRemoveKeystoreFromGroupOptions("ks2", "us"))
+		Expect(err).To(BeNil())
+		Expect(updates).To(Equal([]string{"ks2 [eu prod]"}))
+
+		concurrentUpdates = 1
+		_, _, err = ukoService.RemoveKeystoreFromGroup(ukoService.NewRemoveKeystoreFromGroupOptions("ks2", "eu"))
+		Expect(err).To(BeNil())
+		Expect(updates).To(Equal([]string{"ks2 [eu prod]", "ks2 [prod]"}))
+	})
+	It(`Reject invalid group changes (negative test)`, func() {
+		_, _, err := ukoService.AddKeystoreToGroup(nil)
+		Expect(err).ToNot(BeNil())
+		_, _, err = ukoService.AddKeystoreToGroup(ukoService.NewAddKeystoreToGroupOptions("ks1", ""))
+		Expect(err).ToNot(BeNil())
+		_, err = ukoService.RenameGroup(new(ukov4.RenameGroupOptions))
+		Expect(err).ToNot(BeNil())
+
+		_, _, err = ukoService.RemoveKeystoreFromGroup(ukoService.NewRemoveKeystoreFromGroupOptions("ks3", "us"))
+		Expect(err).To(MatchError("keystore 'ks3' cannot be removed from its only group 'us'"))
+
+		concurrentUpdates = 3
+		_, response, err := ukoService.AddKeystoreToGroup(ukoService.NewAddKeystoreToGroupOptions("ks3", "eu"))
+		Expect(err).ToNot(BeNil())
+		Expect(response.StatusCode).To(Equal(412))
+		Expect(updates).To(BeEmpty())
+	})
+	It(`Rename a group`, func() {
+		keystores[1].groups = []string{"eu", "europe"}
+		result, err := ukoService.RenameGroup(ukoService.NewRenameGroupOptions("v1", "eu", "europe"))
+		Expect(err).To(BeNil())
+		Expect(result.UpdatedKeystores).To(HaveLen(2))
+		Expect(updates).To(Equal([]string{"ks1 [prod europe]", "ks2 [europe]"}))
+		Expect(result.Templates).To(HaveLen(2))
+		Expect(*result.Templates[0].ID).To(Equal("t1"))
+
+		updates = nil
+		result, err = ukoService.RenameGroup(ukoService.NewRenameGroupOptions("v1", "missing", "other"))
+		Expect(err).To(BeNil())
+		Expect(result.UpdatedKeystores).To(BeEmpty())
+		Expect(updates).To(BeEmpty())
+	})
+})
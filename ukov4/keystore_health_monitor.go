@@ -0,0 +1,393 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/go-openapi/strfmt"
+)
+
+// Default values used by KeystoreHealthMonitor.
+const (
+	DefaultKeystoreHealthPollInterval  = time.Minute
+	DefaultKeystoreHeartbeatStaleAfter = 10 * time.Minute
+)
+
+// KeystoreHealthEvent : A change in the health of a keystore observed by a KeystoreHealthMonitor.
+type KeystoreHealthEvent struct {
+	// The type of the event.
+	Type *string `json:"type"`
+
+	// The UUID of the keystore.
+	KeystoreID *string `json:"keystore_id"`
+
+	// Name of the keystore.
+	KeystoreName *string `json:"keystore_name,omitempty"`
+
+	// Type of keystore.
+	KeystoreType *string `json:"keystore_type,omitempty"`
+
+	// The health status before the event; not set when the keystore is observed for the first time.
+	PreviousHealthStatus *string `json:"previous_health_status,omitempty"`
+
+	// The health status of the keystore.
+	HealthStatus *string `json:"health_status,omitempty"`
+
+	// The last heartbeat of the keystore.
+	LastHeartbeat *strfmt.DateTime `json:"last_heartbeat,omitempty"`
+
+	// The message reported with the health status, or the error of a failed status check.
+	Message *string `json:"message,omitempty"`
+
+	// Date and time when the event was observed.
+	ObservedAt *strfmt.DateTime `json:"observed_at"`
+}
+
+// Constants associated with the KeystoreHealthEvent.Type property.
+// The type of the event.
+const (
+	KeystoreHealthEvent_Type_CheckFailed      = "check_failed"
+	KeystoreHealthEvent_Type_HealthChanged    = "health_changed"
+	KeystoreHealthEvent_Type_HeartbeatResumed = "heartbeat_resumed"
+	KeystoreHealthEvent_Type_HeartbeatStale   = "heartbeat_stale"
+	KeystoreHealthEvent_Type_KeystoreRemoved  = "keystore_removed"
+)
+
+// KeystoreHealthSink : A destination for the events of a KeystoreHealthMonitor.
+type KeystoreHealthSink interface {
+	// Send delivers an event. It is called synchronously, once per event, in the order the events are observed.
+	Send(ctx context.Context, event *KeystoreHealthEvent) error
+}
+
+// KeystoreHealthSinkFunc : An adapter that allows an ordinary function to be used as a KeystoreHealthSink.
+type KeystoreHealthSinkFunc func(ctx context.Context, event *KeystoreHealthEvent) error
+
+// Send calls the function.
+func (f KeystoreHealthSinkFunc) Send(ctx context.Context, event *KeystoreHealthEvent) error {
+	return f(ctx, event)
+}
+
+// NewCallbackKeystoreHealthSink returns a sink that invokes the callback for every event.
+func NewCallbackKeystoreHealthSink(callback func(*KeystoreHealthEvent)) KeystoreHealthSink {
+	return KeystoreHealthSinkFunc(func(ctx context.Context, event *KeystoreHealthEvent) error {
+		callback(event)
+		return nil
+	})
+}
+
+// NewChannelKeystoreHealthSink returns a sink that sends every event to the channel. Sending blocks until the
+// channel accepts the event or the context of the monitor is done.
+func NewChannelKeystoreHealthSink(channel chan<- *KeystoreHealthEvent) KeystoreHealthSink {
+	return KeystoreHealthSinkFunc(func(ctx context.Context, event *KeystoreHealthEvent) error {
+		select {
+		case channel <- event:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
+
+// WebhookKeystoreHealthSink : A sink that posts every event as JSON to a URL.
+type WebhookKeystoreHealthSink struct {
+	// The URL the events are posted to.
+	URL string
+
+	// Headers added to every request, such as an Authorization header.
+	Headers map[string]string
+
+	// The client used to post the events. Defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+// NewWebhookKeystoreHealthSink returns a sink that posts every event to the URL.
+func NewWebhookKeystoreHealthSink(url string) *WebhookKeystoreHealthSink {
+	return &WebhookKeystoreHealthSink{URL: url}
+}
+
+// Send posts the event. Responses with a status code outside of the 2xx range are returned as errors.
+func (sink *WebhookKeystoreHealthSink) Send(ctx context.Context, event *KeystoreHealthEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sink.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range sink.Headers {
+		req.Header.Set(name, value)
+	}
+	client := sink.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("webhook %s returned status %d", sink.URL, res.StatusCode)
+	}
+	return nil
+}
+
+// keystoreHealthState is what a monitor remembers about a keystore between checks.
+type keystoreHealthState struct {
+	healthStatus string
+	stale        bool
+	failing      bool
+}
+
+// KeystoreHealthMonitor : Tracks the health of keystores and emits an event whenever it changes
+// Every check lists the keystores with KeystoresPager and reads the status of each of them with GetKeystoreStatus. An
+// event is emitted when the health status of a keystore changes, when its last heartbeat becomes older than StaleAfter
+// or recent again, when its status cannot be read and when it is no longer listed. A keystore seen for the first time
+// only produces an event if it is not healthy.
+type KeystoreHealthMonitor struct {
+	service *UkoV4
+	options KeystoreHealthMonitorOptions
+	states  map[string]*keystoreHealthState
+	mutex   sync.Mutex
+}
+
+// NewKeystoreHealthMonitor returns a monitor for the keystores selected by the options.
+func (uko *UkoV4) NewKeystoreHealthMonitor(keystoreHealthMonitorOptions *KeystoreHealthMonitorOptions) (monitor *KeystoreHealthMonitor, err error) {
+	err = core.ValidateNotNil(keystoreHealthMonitorOptions, "keystoreHealthMonitorOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	options := *keystoreHealthMonitorOptions
+	if options.PollInterval <= 0 {
+		options.PollInterval = DefaultKeystoreHealthPollInterval
+	}
+	if options.StaleAfter <= 0 {
+		options.StaleAfter = DefaultKeystoreHeartbeatStaleAfter
+	}
+	monitor = &KeystoreHealthMonitor{
+		service: uko,
+		options: options,
+		states:  map[string]*keystoreHealthState{},
+	}
+	return
+}
+
+// Run checks the keystores every PollInterval until the context is done, and then returns the context's error. Errors
+// of a check and of the sinks are passed to the ErrorHandler and do not stop the monitor.
+func (monitor *KeystoreHealthMonitor) Run(ctx context.Context) error {
+	for {
+		_, err := monitor.Check(ctx)
+		if err != nil && ctx.Err() == nil {
+			monitor.handleError(err)
+		}
+		if err = sleepWithContext(ctx, monitor.options.PollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// Check checks every keystore once, delivers the events to the sinks and returns them. Sink errors are passed to the
+// ErrorHandler; an error listing the keystores is returned.
+func (monitor *KeystoreHealthMonitor) Check(ctx context.Context) (events []*KeystoreHealthEvent, err error) {
+	monitor.mutex.Lock()
+	defer monitor.mutex.Unlock()
+
+	pager, err := monitor.service.NewKeystoresPager(&ListKeystoresOptions{VaultID: monitor.options.VaultID})
+	if err != nil {
+		return
+	}
+	keystores, err := pager.GetAllWithContext(ctx)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	seen := map[string]bool{}
+	for _, item := range keystores {
+		keystore := new(Keystore)
+		if err = remarshal(item, keystore); err != nil {
+			return
+		}
+		if keystore.ID == nil {
+			continue
+		}
+		seen[*keystore.ID] = true
+		status, _, checkErr := monitor.service.GetKeystoreStatusWithContext(ctx, &GetKeystoreStatusOptions{ID: keystore.ID})
+		if checkErr != nil && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		events = append(events, monitor.observe(keystore, status, checkErr, now)...)
+	}
+
+	var removed []string
+	for id := range monitor.states {
+		if !seen[id] {
+			removed = append(removed, id)
+		}
+	}
+	sort.Strings(removed)
+	for _, id := range removed {
+		event := newKeystoreHealthEvent(KeystoreHealthEvent_Type_KeystoreRemoved, &Keystore{ID: core.StringPtr(id)}, now)
+		if previous := monitor.states[id].healthStatus; previous != "" {
+			event.PreviousHealthStatus = core.StringPtr(previous)
+		}
+		events = append(events, event)
+		delete(monitor.states, id)
+	}
+
+	for _, event := range events {
+		for _, sink := range monitor.options.Sinks {
+			if sinkErr := sink.Send(ctx, event); sinkErr != nil {
+				monitor.handleError(fmt.Errorf("error sending %s event for keystore '%s': %s", *event.Type,
+					*event.KeystoreID, sinkErr.Error()))
+			}
+		}
+	}
+	return
+}
+
+// observe updates the state of a keystore with the result of a status check and returns the resulting events.
+func (monitor *KeystoreHealthMonitor) observe(keystore *Keystore, status *KeystoreStatus, checkErr error, now time.Time) (events []*KeystoreHealthEvent) {
+	state, known := monitor.states[*keystore.ID]
+	if !known {
+		state = &keystoreHealthState{}
+		monitor.states[*keystore.ID] = state
+	}
+
+	if checkErr != nil {
+		if !state.failing {
+			event := newKeystoreHealthEvent(KeystoreHealthEvent_Type_CheckFailed, keystore, now)
+			event.Message = core.StringPtr(checkErr.Error())
+			events = append(events, event)
+		}
+		state.failing = true
+		return
+	}
+	state.failing = false
+
+	healthStatus := core.StringNilMapper(status.HealthStatus)
+	if healthStatus != state.healthStatus && (known || healthStatus != KeystoreStatus_HealthStatus_Ok) {
+		event := newKeystoreHealthEvent(KeystoreHealthEvent_Type_HealthChanged, keystore, now)
+		if state.healthStatus != "" {
+			event.PreviousHealthStatus = core.StringPtr(state.healthStatus)
+		}
+		event.withStatus(status)
+		events = append(events, event)
+	}
+	state.healthStatus = healthStatus
+
+	stale := status.LastHeartbeat != nil && now.Sub(time.Time(*status.LastHeartbeat)) > monitor.options.StaleAfter
+	if stale != state.stale {
+		eventType := KeystoreHealthEvent_Type_HeartbeatStale
+		if !stale {
+			eventType = KeystoreHealthEvent_Type_HeartbeatResumed
+		}
+		event := newKeystoreHealthEvent(eventType, keystore, now)
+		event.withStatus(status)
+		events = append(events, event)
+	}
+	state.stale = stale
+	return
+}
+
+func (monitor *KeystoreHealthMonitor) handleError(err error) {
+	if monitor.options.ErrorHandler != nil {
+		monitor.options.ErrorHandler(err)
+	}
+}
+
+func newKeystoreHealthEvent(eventType string, keystore *Keystore, now time.Time) *KeystoreHealthEvent {
+	observedAt := strfmt.DateTime(now.UTC())
+	return &KeystoreHealthEvent{
+		Type:         core.StringPtr(eventType),
+		KeystoreID:   keystore.ID,
+		KeystoreName: keystore.Name,
+		KeystoreType: keystore.Type,
+		ObservedAt:   &observedAt,
+	}
+}
+
+func (event *KeystoreHealthEvent) withStatus(status *KeystoreStatus) {
+	event.HealthStatus = status.HealthStatus
+	event.LastHeartbeat = status.LastHeartbeat
+	event.Message = status.Message
+}
+
+// KeystoreHealthMonitorOptions : The KeystoreHealthMonitor options.
+type KeystoreHealthMonitorOptions struct {
+	// The UUIDs of the vaults whose keystores are monitored. All keystores are monitored if empty.
+	VaultID []string
+
+	// The interval between checks. Defaults to DefaultKeystoreHealthPollInterval.
+	PollInterval time.Duration
+
+	// How old the last heartbeat of a keystore can be before it is considered stale. Defaults to
+	// DefaultKeystoreHeartbeatStaleAfter.
+	StaleAfter time.Duration
+
+	// The destinations of the events.
+	Sinks []KeystoreHealthSink
+
+	// Invoked with the errors of checks run by Run and with the errors of the sinks.
+	ErrorHandler func(error)
+}
+
+// NewKeystoreHealthMonitorOptions : Instantiate KeystoreHealthMonitorOptions
+func (*UkoV4) NewKeystoreHealthMonitorOptions() *KeystoreHealthMonitorOptions {
+	return &KeystoreHealthMonitorOptions{}
+}
+
+// SetVaultID : Allow user to set VaultID
+func (_options *KeystoreHealthMonitorOptions) SetVaultID(vaultID []string) *KeystoreHealthMonitorOptions {
+	_options.VaultID = vaultID
+	return _options
+}
+
+// SetPollInterval : Allow user to set PollInterval
+func (_options *KeystoreHealthMonitorOptions) SetPollInterval(pollInterval time.Duration) *KeystoreHealthMonitorOptions {
+	_options.PollInterval = pollInterval
+	return _options
+}
+
+// SetStaleAfter : Allow user to set StaleAfter
+func (_options *KeystoreHealthMonitorOptions) SetStaleAfter(staleAfter time.Duration) *KeystoreHealthMonitorOptions {
+	_options.StaleAfter = staleAfter
+	return _options
+}
+
+// AddSink : Allow user to add a sink
+func (_options *KeystoreHealthMonitorOptions) AddSink(sink KeystoreHealthSink) *KeystoreHealthMonitorOptions {
+	_options.Sinks = append(_options.Sinks, sink)
+	return _options
+}
+
+// SetErrorHandler : Allow user to set ErrorHandler
+func (_options *KeystoreHealthMonitorOptions) SetErrorHandler(errorHandler func(error)) *KeystoreHealthMonitorOptions {
+	_options.ErrorHandler = errorHandler
+	return _options
+}
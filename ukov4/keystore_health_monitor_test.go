@@ -0,0 +1,219 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-hpcs-uko-sdk/ukov4"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`KeystoreHealthMonitor`, func() {
+	var testServer *httptest.Server
+	var keystores []string
+	var healthStatuses map[string]string
+	var heartbeats map[string]time.Time
+
+	BeforeEach(func() {
+		keystores = []string{"ks1", "ks2"}
+		healthStatuses = map[string]string{"ks1": "ok", "ks2": "ok"}
+		heartbeats = map[string]time.Time{}
+		testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+
+			res.Header().Set("Content-type", "application/json")
+			path := req.URL.EscapedPath()
+			switch {
+			case req.Method == "GET" && path == "/api/v4/keystores":
+				Expect(req.URL.Query().Get("vault.id")).To(Equal("v1"))
+				items := make([]string, len(keystores))
+				for i, id := range keystores {
+					items[i] = fmt.Sprintf(`{"id": "%s", "name": "name-%s", "type": "aws_kms", "vault": {"id": "v1"}}`, id, id)
+				}
+				res.WriteHeader(200)
+				fmt.Fprintf(res, `{"total_count": %d, "limit": %d, "offset": 0, "keystores": [%s]}`,
+					len(items), len(items), strings.Join(items, ","))
+			case req.Method == "GET" && strings.HasSuffix(path, "/status"):
+				id := strings.TrimSuffix(strings.TrimPrefix(path, "/api/v4/keystores/"), "/status")
+				healthStatus, ok := healthStatuses[id]
+				if !ok {
+					res.WriteHeader(500)
+					fmt.Fprint(res, `{"errors": [{"message": "internal error"}]}`)
+					return
+				}
+				heartbeat, ok := heartbeats[id]
+				if !ok {
+					heartbeat = time.Now()
+				}
+				res.WriteHeader(200)
+				fmt.Fprintf(res, `{"status": "ok", "health_status": "%s", "last_heartbeat": "%s", "message": "checked"}`,
+					healthStatus, heartbeat.UTC().Format(time.RFC3339))
+			default:
+				Fail(fmt.Sprintf("unexpected request %s %s", req.Method, path))
+			}
+		}))
+	})
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	newService := func() *ukov4.UkoV4 {
+		ukoService, serviceErr := ukov4.NewUkoV4(&ukov4.UkoV4Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+		return ukoService
+	}
+	eventTypes := func(events []*ukov4.KeystoreHealthEvent) (types []string) {
+		for _, event := range events {
+			types = append(types, *event.KeystoreID+" "+*event.Type)
+		}
+		return
+	}
+
+	It(`Invoke NewKeystoreHealthMonitor with invalid options (negative test)`, func() {
+		_, err := newService().NewKeystoreHealthMonitor(nil)
+		Expect(err).ToNot(BeNil())
+	})
+	It(`Emit events on health status transitions`, func() {
+		ukoService := newService()
+		var received []*ukov4.KeystoreHealthEvent
+		monitor, err := ukoService.NewKeystoreHealthMonitor(ukoService.NewKeystoreHealthMonitorOptions().
+			SetVaultID([]string{"v1"}).
+			AddSink(ukov4.NewCallbackKeystoreHealthSink(func(event *ukov4.KeystoreHealthEvent) {
+				received = append(received, event)
+			})))
+		Expect(err).To(BeNil())
+
+		healthStatuses["ks2"] = "configuration_error"
+		events, err := monitor.Check(context.Background())
+		Expect(err).To(BeNil())
+		Expect(eventTypes(events)).To(Equal([]string{"ks2 health_changed"}))
+		Expect(events[0].PreviousHealthStatus).To(BeNil())
+		Expect(*events[0].HealthStatus).To(Equal("configuration_error"))
+		Expect(*events[0].KeystoreName).To(Equal("name-ks2"))
+
+		events, err = monitor.Check(context.Background())
+		Expect(err).To(BeNil())
+		Expect(events).To(BeEmpty())
+
+		healthStatuses["ks1"] = "not_responding"
+		healthStatuses["ks2"] = "ok"
+		events, err = monitor.Check(context.Background())
+		Expect(err).To(BeNil())
+		Expect(eventTypes(events)).To(Equal([]string{"ks1 health_changed", "ks2 health_changed"}))
+		Expect(*events[0].PreviousHealthStatus).To(Equal("ok"))
+		Expect(*events[0].HealthStatus).To(Equal("not_responding"))
+		Expect(*events[1].PreviousHealthStatus).To(Equal("configuration_error"))
+		Expect(received).To(HaveLen(3))
+		Expect(received[1:]).To(Equal(events))
+	})
+	It(`Emit events for stale heartbeats, failed checks and removed keystores`, func() {
+		ukoService := newService()
+		monitor, err := ukoService.NewKeystoreHealthMonitor(&ukov4.KeystoreHealthMonitorOptions{
+			VaultID:    []string{"v1"},
+			StaleAfter: time.Minute,
+		})
+		Expect(err).To(BeNil())
+
+		heartbeats["ks1"] = time.Now().Add(-time.Hour)
+		delete(healthStatuses, "ks2")
+		events, err := monitor.Check(context.Background())
+		Expect(err).To(BeNil())
+		Expect(eventTypes(events)).To(Equal([]string{"ks1 heartbeat_stale", "ks2 check_failed"}))
+		Expect(events[1].Message).ToNot(BeNil())
+
+		events, err = monitor.Check(context.Background())
+		Expect(err).To(BeNil())
+		Expect(events).To(BeEmpty())
+
+		delete(heartbeats, "ks1")
+		keystores = []string{"ks1"}
+		events, err = monitor.Check(context.Background())
+		Expect(err).To(BeNil())
+		Expect(eventTypes(events)).To(Equal([]string{"ks1 heartbeat_resumed", "ks2 keystore_removed"}))
+	})
+	It(`Run until the context is done and deliver events to a channel`, func() {
+		ukoService := newService()
+		channel := make(chan *ukov4.KeystoreHealthEvent, 10)
+		var sinkErrors []error
+		monitor, err := ukoService.NewKeystoreHealthMonitor(ukoService.NewKeystoreHealthMonitorOptions().
+			SetVaultID([]string{"v1"}).
+			SetPollInterval(time.Millisecond).
+			AddSink(ukov4.NewChannelKeystoreHealthSink(channel)).
+			AddSink(ukov4.KeystoreHealthSinkFunc(func(ctx context.Context, event *ukov4.KeystoreHealthEvent) error {
+				return fmt.Errorf("unavailable")
+			})).
+			SetErrorHandler(func(err error) {
+				sinkErrors = append(sinkErrors, err)
+			}))
+		Expect(err).To(BeNil())
+
+		healthStatuses["ks1"] = "not_responding"
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		Expect(monitor.Run(ctx)).To(Equal(context.DeadlineExceeded))
+
+		Expect(channel).To(HaveLen(1))
+		event := <-channel
+		Expect(*event.Type).To(Equal(ukov4.KeystoreHealthEvent_Type_HealthChanged))
+		Expect(*event.KeystoreID).To(Equal("ks1"))
+		Expect(sinkErrors).To(HaveLen(1))
+		Expect(sinkErrors[0].Error()).To(ContainSubstring("unavailable"))
+	})
+	It(`Post events to a webhook`, func() {
+		var posted []map[string]interface{}
+		webhook := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+			Expect(req.Method).To(Equal("POST"))
+			Expect(req.Header.Get("Content-Type")).To(Equal("application/json"))
+			if req.Header.Get("Authorization") != "Bearer token" {
+				res.WriteHeader(401)
+				return
+			}
+			var body map[string]interface{}
+			Expect(json.NewDecoder(req.Body).Decode(&body)).To(Succeed())
+			posted = append(posted, body)
+			res.WriteHeader(204)
+		}))
+		defer webhook.Close()
+
+		event := &ukov4.KeystoreHealthEvent{
+			Type:         core.StringPtr(ukov4.KeystoreHealthEvent_Type_HealthChanged),
+			KeystoreID:   core.StringPtr("ks1"),
+			HealthStatus: core.StringPtr("not_responding"),
+		}
+		sink := ukov4.NewWebhookKeystoreHealthSink(webhook.URL)
+		Expect(sink.Send(context.Background(), event)).ToNot(Succeed())
+
+		sink.Headers = map[string]string{"Authorization": "Bearer token"}
+		Expect(sink.Send(context.Background(), event)).To(Succeed())
+		Expect(posted).To(HaveLen(1))
+		Expect(posted[0]["keystore_id"]).To(Equal("ks1"))
+		Expect(posted[0]["health_status"]).To(Equal("not_responding"))
+	})
+})
@@ -0,0 +1,561 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+)
+
+// KeystorePreflightReport : The result of checking a keystore request before it is sent to the service.
+type KeystorePreflightReport struct {
+	// Type of keystore.
+	Type *string `json:"type,omitempty"`
+
+	// The checks that were run, in order. Every kind of check appears at least once.
+	Checks []KeystorePreflightCheck `json:"checks"`
+}
+
+// Passed returns true if no check failed.
+func (report *KeystorePreflightReport) Passed() bool {
+	return len(report.Failures()) == 0
+}
+
+// Failures returns the checks that failed.
+func (report *KeystorePreflightReport) Failures() (checks []KeystorePreflightCheck) {
+	for _, check := range report.Checks {
+		if core.StringNilMapper(check.Status) == KeystorePreflightCheck_Status_Failed {
+			checks = append(checks, check)
+		}
+	}
+	return
+}
+
+// KeystorePreflightCheck : The outcome of one preflight check.
+type KeystorePreflightCheck struct {
+	// The kind of check.
+	Check *string `json:"check"`
+
+	// The JSON name of the property the outcome is about, if any.
+	Field *string `json:"field,omitempty"`
+
+	// The outcome of the check.
+	Status *string `json:"status"`
+
+	// A description of the outcome.
+	Message *string `json:"message,omitempty"`
+}
+
+// Constants associated with the KeystorePreflightCheck.Check property.
+// The kind of check.
+const (
+	KeystorePreflightCheck_Check_CcaCertificate   = "cca_certificate"
+	KeystorePreflightCheck_Check_CredentialFormat = "credential_format"
+	KeystorePreflightCheck_Check_DryRun           = "dry_run"
+	KeystorePreflightCheck_Check_RequiredFields   = "required_fields"
+	KeystorePreflightCheck_Check_TlsProxy         = "tls_proxy"
+)
+
+// Constants associated with the KeystorePreflightCheck.Status property.
+// The outcome of the check.
+const (
+	KeystorePreflightCheck_Status_Failed  = "failed"
+	KeystorePreflightCheck_Status_Passed  = "passed"
+	KeystorePreflightCheck_Status_Skipped = "skipped"
+	KeystorePreflightCheck_Status_Warning = "warning"
+)
+
+// keystoreRequiredFields lists, for each keystore type, the JSON properties a creation request must set. IBM Cloud
+// KMS keystores depend on their variant, see ibmCloudKmsRequiredFields.
+var keystoreRequiredFields = map[string][]string{
+	KeystoreCreationRequest_Type_AwsKms: {"name", "aws_region", "aws_access_key_id", "aws_secret_access_key"},
+	KeystoreCreationRequest_Type_AzureKeyVault: {"azure_service_name", "azure_resource_group", "azure_location",
+		"azure_service_principal_client_id", "azure_service_principal_password", "azure_tenant",
+		"azure_subscription_id", "azure_environment"},
+	KeystoreCreationRequest_Type_Cca:       {"name", "cca_host", "cca_port", "cca_public_key_hash"},
+	KeystoreCreationRequest_Type_GoogleKms: {"name", "google_credentials"},
+}
+
+// ibmCloudKmsRequiredFields lists the JSON properties an IBM Cloud KMS keystore creation request must set, by variant.
+var ibmCloudKmsRequiredFields = map[string][]string{
+	KeystoreCreationRequest_IbmVariant_Internal:   {"name"},
+	KeystoreCreationRequest_IbmVariant_Hpcs:       {"name", "ibm_api_endpoint", "ibm_iam_endpoint", "ibm_api_key", "ibm_instance_id"},
+	KeystoreCreationRequest_IbmVariant_KeyProtect: {"name", "ibm_api_endpoint", "ibm_iam_endpoint", "ibm_api_key", "ibm_instance_id"},
+}
+
+var (
+	awsAccessKeyIDPattern     = regexp.MustCompile(`^(AKIA|ASIA)[A-Z0-9]{16}$`)
+	awsSecretAccessKeyPattern = regexp.MustCompile(`^[A-Za-z0-9/+]{40}$`)
+	uuidPattern               = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	domainNamePattern         = regexp.MustCompile(`^[A-Za-z0-9-]+(\.[A-Za-z0-9-]+)+$`)
+)
+
+// PreflightKeystore : Check a keystore creation request
+// Runs client-side checks on the request: that the fields required by its type are set, that the credentials are
+// well-formed, that the TLS proxy is a valid address and that the CCA trusted issuer is a valid certificate. If none of
+// them failed, the request is then sent to the service with dry_run, which verifies that the keystore can be reached.
+// Problems are reported in the returned report; an error is only returned for invalid options.
+func (uko *UkoV4) PreflightKeystore(preflightKeystoreOptions *PreflightKeystoreOptions) (report *KeystorePreflightReport, err error) {
+	return uko.PreflightKeystoreWithContext(context.Background(), preflightKeystoreOptions)
+}
+
+// PreflightKeystoreWithContext is an alternate form of the PreflightKeystore method which supports a Context parameter
+func (uko *UkoV4) PreflightKeystoreWithContext(ctx context.Context, preflightKeystoreOptions *PreflightKeystoreOptions) (report *KeystorePreflightReport, err error) {
+	err = core.ValidateNotNil(preflightKeystoreOptions, "preflightKeystoreOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	// The body is not validated with core.ValidateStruct: missing required fields are reported as failed checks.
+	if preflightKeystoreOptions.KeystoreBody == nil {
+		err = fmt.Errorf("preflightKeystoreOptions.KeystoreBody cannot be nil")
+		return
+	}
+
+	request := new(KeystoreCreationRequest)
+	fields := map[string]interface{}{}
+	if err = remarshal(preflightKeystoreOptions.KeystoreBody, request); err != nil {
+		return
+	}
+	if err = remarshal(preflightKeystoreOptions.KeystoreBody, &fields); err != nil {
+		return
+	}
+
+	checker := &keystorePreflightChecker{report: &KeystorePreflightReport{Type: request.Type}}
+	checker.checkRequest(request, fields, nil)
+
+	switch {
+	case !checker.report.Passed():
+		checker.add(KeystorePreflightCheck_Check_DryRun, "", KeystorePreflightCheck_Status_Skipped,
+			"skipped because client-side checks failed")
+	case preflightKeystoreOptions.SkipDryRun != nil && *preflightKeystoreOptions.SkipDryRun:
+		checker.add(KeystorePreflightCheck_Check_DryRun, "", KeystorePreflightCheck_Status_Skipped,
+			"skipped on request")
+	default:
+		_, response, dryRunErr := uko.CreateKeystoreWithContext(ctx, &CreateKeystoreOptions{
+			KeystoreBody: preflightKeystoreOptions.KeystoreBody,
+			DryRun:       core.BoolPtr(true),
+			Headers:      preflightKeystoreOptions.Headers,
+		})
+		if dryRunErr != nil {
+			message := dryRunErr.Error()
+			if response != nil {
+				message = fmt.Sprintf("the service rejected the request (status %d): %s", response.StatusCode, message)
+			}
+			checker.add(KeystorePreflightCheck_Check_DryRun, "", KeystorePreflightCheck_Status_Failed, message)
+		} else {
+			checker.add(KeystorePreflightCheck_Check_DryRun, "", KeystorePreflightCheck_Status_Passed,
+				"the service accepted the request")
+		}
+	}
+	report = checker.report
+	return
+}
+
+// PreflightKeystoreUpdate : Check a keystore update request
+// Runs the client-side checks of PreflightKeystore on the keystore as it would be after the update. Credentials are not
+// returned by the service, so the ones the update does not replace are not checked. The service does not support
+// dry_run for updates, so the dry run check is always skipped.
+func (uko *UkoV4) PreflightKeystoreUpdate(preflightKeystoreUpdateOptions *PreflightKeystoreUpdateOptions) (report *KeystorePreflightReport, err error) {
+	return uko.PreflightKeystoreUpdateWithContext(context.Background(), preflightKeystoreUpdateOptions)
+}
+
+// PreflightKeystoreUpdateWithContext is an alternate form of the PreflightKeystoreUpdate method which supports a Context parameter
+func (uko *UkoV4) PreflightKeystoreUpdateWithContext(ctx context.Context, preflightKeystoreUpdateOptions *PreflightKeystoreUpdateOptions) (report *KeystorePreflightReport, err error) {
+	err = core.ValidateNotNil(preflightKeystoreUpdateOptions, "preflightKeystoreUpdateOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(preflightKeystoreUpdateOptions, "preflightKeystoreUpdateOptions")
+	if err != nil {
+		return
+	}
+
+	current, _, err := uko.GetKeystoreWithContext(ctx, &GetKeystoreOptions{
+		ID:      preflightKeystoreUpdateOptions.ID,
+		Headers: preflightKeystoreUpdateOptions.Headers,
+	})
+	if err != nil {
+		return
+	}
+	fields := map[string]interface{}{}
+	if err = remarshal(current, &fields); err != nil {
+		return
+	}
+	update := map[string]interface{}{}
+	if err = remarshal(preflightKeystoreUpdateOptions.KeystoreBody, &update); err != nil {
+		return
+	}
+	for name, value := range update {
+		fields[name] = value
+	}
+	request := new(KeystoreCreationRequest)
+	if err = remarshal(fields, request); err != nil {
+		return
+	}
+
+	unchecked := map[string]bool{}
+	for name := range RedactedJSONFields {
+		if _, ok := update[name]; !ok {
+			unchecked[name] = true
+		}
+	}
+	checker := &keystorePreflightChecker{report: &KeystorePreflightReport{Type: request.Type}}
+	checker.checkRequest(request, fields, unchecked)
+	checker.add(KeystorePreflightCheck_Check_DryRun, "", KeystorePreflightCheck_Status_Skipped,
+		"the service does not support dry_run for keystore updates")
+	report = checker.report
+	return
+}
+
+// keystorePreflightChecker accumulates the checks of a preflight report.
+type keystorePreflightChecker struct {
+	report *KeystorePreflightReport
+}
+
+func (checker *keystorePreflightChecker) add(check string, field string, status string, message string) {
+	result := KeystorePreflightCheck{
+		Check:  core.StringPtr(check),
+		Status: core.StringPtr(status),
+	}
+	if field != "" {
+		result.Field = core.StringPtr(field)
+	}
+	if message != "" {
+		result.Message = core.StringPtr(message)
+	}
+	checker.report.Checks = append(checker.report.Checks, result)
+}
+
+// run runs one kind of check and records it as passed if it reported nothing.
+func (checker *keystorePreflightChecker) run(check string, f func()) {
+	count := len(checker.report.Checks)
+	f()
+	if len(checker.report.Checks) == count {
+		checker.add(check, "", KeystorePreflightCheck_Status_Passed, "")
+	}
+}
+
+// checkRequest runs the client-side checks. Fields are the JSON properties of the request; the credentials listed in
+// unchecked are neither required nor checked.
+func (checker *keystorePreflightChecker) checkRequest(request *KeystoreCreationRequest, fields map[string]interface{}, unchecked map[string]bool) {
+	checker.run(KeystorePreflightCheck_Check_RequiredFields, func() {
+		checker.checkRequiredFields(request, fields, unchecked)
+	})
+	checker.run(KeystorePreflightCheck_Check_CredentialFormat, func() {
+		checker.checkCredentialFormat(request, unchecked)
+	})
+	checker.run(KeystorePreflightCheck_Check_TlsProxy, func() {
+		checker.checkTlsProxy(request.TlsProxy)
+	})
+	checker.run(KeystorePreflightCheck_Check_CcaCertificate, func() {
+		checker.checkCcaCertificate(request)
+	})
+}
+
+func (checker *keystorePreflightChecker) checkRequiredFields(request *KeystoreCreationRequest, fields map[string]interface{}, unchecked map[string]bool) {
+	keystoreType := core.StringNilMapper(request.Type)
+	required, ok := keystoreRequiredFields[keystoreType]
+	if keystoreType == KeystoreCreationRequest_Type_IbmCloudKms {
+		variant := core.StringNilMapper(request.IbmVariant)
+		required, ok = ibmCloudKmsRequiredFields[variant]
+		if !ok {
+			checker.add(KeystorePreflightCheck_Check_RequiredFields, "ibm_variant", KeystorePreflightCheck_Status_Failed,
+				fmt.Sprintf("unknown IBM Cloud KMS variant '%s'", variant))
+			return
+		}
+	}
+	if !ok {
+		checker.add(KeystorePreflightCheck_Check_RequiredFields, "type", KeystorePreflightCheck_Status_Failed,
+			fmt.Sprintf("unknown keystore type '%s'", keystoreType))
+		return
+	}
+	if request.Vault == nil || request.Vault.ID == nil || *request.Vault.ID == "" {
+		checker.add(KeystorePreflightCheck_Check_RequiredFields, "vault", KeystorePreflightCheck_Status_Failed,
+			"the vault is required")
+	}
+	for _, field := range required {
+		if unchecked[field] {
+			continue
+		}
+		if value, ok := fields[field]; !ok || value == nil || value == "" {
+			checker.add(KeystorePreflightCheck_Check_RequiredFields, field, KeystorePreflightCheck_Status_Failed,
+				fmt.Sprintf("%s is required for %s keystores", field, keystoreType))
+		}
+	}
+}
+
+func (checker *keystorePreflightChecker) checkCredentialFormat(request *KeystoreCreationRequest, unchecked map[string]bool) {
+	failed := func(field string, format string, args ...interface{}) {
+		checker.add(KeystorePreflightCheck_Check_CredentialFormat, field, KeystorePreflightCheck_Status_Failed,
+			fmt.Sprintf(format, args...))
+	}
+	isSet := func(field string, value *string) bool {
+		return !unchecked[field] && value != nil && *value != ""
+	}
+
+	switch core.StringNilMapper(request.Type) {
+	case KeystoreCreationRequest_Type_AwsKms:
+		if isSet("aws_access_key_id", request.AwsAccessKeyID) {
+			if !awsAccessKeyIDPattern.MatchString(*request.AwsAccessKeyID) {
+				failed("aws_access_key_id", "not an AWS access key ID")
+			} else if (*request.AwsAccessKeyID)[:4] == "ASIA" {
+				checker.add(KeystorePreflightCheck_Check_CredentialFormat, "aws_access_key_id",
+					KeystorePreflightCheck_Status_Warning,
+					"temporary credentials expire and need a session token, which keystores do not support")
+			}
+		}
+		if isSet("aws_secret_access_key", request.AwsSecretAccessKey) &&
+			!awsSecretAccessKeyPattern.MatchString(*request.AwsSecretAccessKey) {
+			failed("aws_secret_access_key", "not an AWS secret access key")
+		}
+	case KeystoreCreationRequest_Type_AzureKeyVault:
+		if isSet("azure_service_principal_client_id", request.AzureServicePrincipalClientID) &&
+			!uuidPattern.MatchString(*request.AzureServicePrincipalClientID) {
+			failed("azure_service_principal_client_id", "azure_service_principal_client_id must be a UUID")
+		}
+		if isSet("azure_subscription_id", request.AzureSubscriptionID) &&
+			!uuidPattern.MatchString(*request.AzureSubscriptionID) {
+			failed("azure_subscription_id", "azure_subscription_id must be a UUID")
+		}
+		if isSet("azure_tenant", request.AzureTenant) && !uuidPattern.MatchString(*request.AzureTenant) &&
+			!domainNamePattern.MatchString(*request.AzureTenant) {
+			failed("azure_tenant", "azure_tenant must be a UUID or a domain name")
+		}
+	case KeystoreCreationRequest_Type_GoogleKms:
+		if isSet("google_credentials", request.GoogleCredentials) {
+			checker.checkGoogleCredentials(request)
+		}
+	case KeystoreCreationRequest_Type_IbmCloudKms:
+		if isSet("ibm_instance_id", request.IbmInstanceID) && !uuidPattern.MatchString(*request.IbmInstanceID) {
+			failed("ibm_instance_id", "ibm_instance_id must be a UUID")
+		}
+		for _, field := range []string{"ibm_api_endpoint", "ibm_iam_endpoint"} {
+			value := request.IbmApiEndpoint
+			if field == "ibm_iam_endpoint" {
+				value = request.IbmIamEndpoint
+			}
+			if !isSet(field, value) {
+				continue
+			}
+			if endpoint, err := url.Parse(*value); err != nil || endpoint.Scheme != "https" || endpoint.Host == "" {
+				failed(field, "%s must be an https URL", field)
+			}
+		}
+	case KeystoreCreationRequest_Type_Cca:
+		if isSet("cca_public_key_hash", request.CcaPublicKeyHash) {
+			if _, err := hex.DecodeString(*request.CcaPublicKeyHash); err != nil {
+				failed("cca_public_key_hash", "cca_public_key_hash must be hex encoded")
+			}
+		}
+		if request.CcaPort != nil && (*request.CcaPort < 1 || *request.CcaPort > 65535) {
+			failed("cca_port", "cca_port must be between 1 and 65535")
+		}
+	}
+}
+
+// checkGoogleCredentials checks that the credentials are a base64 encoded service account key that agrees with the
+// project and private key IDs of the request.
+func (checker *keystorePreflightChecker) checkGoogleCredentials(request *KeystoreCreationRequest) {
+	failed := func(field string, message string) {
+		checker.add(KeystorePreflightCheck_Check_CredentialFormat, field, KeystorePreflightCheck_Status_Failed, message)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(*request.GoogleCredentials)
+	if err != nil {
+		failed("google_credentials", "google_credentials must be base64 encoded")
+		return
+	}
+	var key struct {
+		Type         string `json:"type"`
+		ProjectID    string `json:"project_id"`
+		PrivateKeyID string `json:"private_key_id"`
+		PrivateKey   string `json:"private_key"`
+		ClientEmail  string `json:"client_email"`
+	}
+	if err = json.Unmarshal(decoded, &key); err != nil {
+		failed("google_credentials", "google_credentials is not a JSON key file")
+		return
+	}
+	if key.Type != "service_account" || key.PrivateKey == "" || key.ClientEmail == "" {
+		failed("google_credentials", "google_credentials is not a service account key")
+		return
+	}
+	if request.GoogleProjectID != nil && *request.GoogleProjectID != "" && *request.GoogleProjectID != key.ProjectID {
+		failed("google_project_id", fmt.Sprintf("the service account key belongs to project '%s'", key.ProjectID))
+	}
+	if request.GooglePrivateKeyID != nil && *request.GooglePrivateKeyID != "" &&
+		*request.GooglePrivateKeyID != key.PrivateKeyID {
+		failed("google_private_key_id", fmt.Sprintf("the service account key has private key ID '%s'", key.PrivateKeyID))
+	}
+}
+
+// checkTlsProxy checks that the TLS proxy, if any, is a host and port, with or without a URL scheme.
+func (checker *keystorePreflightChecker) checkTlsProxy(tlsProxy *string) {
+	if tlsProxy == nil || *tlsProxy == "" {
+		checker.add(KeystorePreflightCheck_Check_TlsProxy, "tls_proxy", KeystorePreflightCheck_Status_Skipped,
+			"no TLS proxy is used")
+		return
+	}
+	address := *tlsProxy
+	if proxy, err := url.Parse(address); err == nil && proxy.Scheme != "" && proxy.Host != "" {
+		address = proxy.Host
+	}
+	host, port, err := net.SplitHostPort(address)
+	if err != nil || host == "" {
+		checker.add(KeystorePreflightCheck_Check_TlsProxy, "tls_proxy", KeystorePreflightCheck_Status_Failed,
+			fmt.Sprintf("'%s' is not a host and port", *tlsProxy))
+		return
+	}
+	if number, err := strconv.Atoi(port); err != nil || number < 1 || number > 65535 {
+		checker.add(KeystorePreflightCheck_Check_TlsProxy, "tls_proxy", KeystorePreflightCheck_Status_Failed,
+			fmt.Sprintf("'%s' is not a valid port", port))
+	}
+}
+
+// checkCcaCertificate checks that the trusted issuer of a CCA keystore is a base64 encoded PEM certificate that is
+// currently valid.
+func (checker *keystorePreflightChecker) checkCcaCertificate(request *KeystoreCreationRequest) {
+	add := func(status string, message string) {
+		checker.add(KeystorePreflightCheck_Check_CcaCertificate, "cca_trusted_issuer", status, message)
+	}
+	if core.StringNilMapper(request.Type) != KeystoreCreationRequest_Type_Cca {
+		add(KeystorePreflightCheck_Status_Skipped, "not a CCA keystore")
+		return
+	}
+	useTls := request.CcaUseTls != nil && *request.CcaUseTls
+	if request.CcaTrustedIssuer == nil || *request.CcaTrustedIssuer == "" {
+		if useTls {
+			add(KeystorePreflightCheck_Status_Warning, "TLS is used without a trusted issuer")
+		} else {
+			add(KeystorePreflightCheck_Status_Skipped, "TLS is not used")
+		}
+		return
+	}
+	if !useTls {
+		add(KeystorePreflightCheck_Status_Warning, "the trusted issuer is ignored because cca_use_tls is not set")
+	}
+	decoded, err := base64.StdEncoding.DecodeString(*request.CcaTrustedIssuer)
+	if err != nil {
+		add(KeystorePreflightCheck_Status_Failed, "cca_trusted_issuer must be base64 encoded")
+		return
+	}
+	block, _ := pem.Decode(decoded)
+	if block == nil || block.Type != "CERTIFICATE" {
+		add(KeystorePreflightCheck_Status_Failed, "cca_trusted_issuer is not a PEM certificate")
+		return
+	}
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		add(KeystorePreflightCheck_Status_Failed, fmt.Sprintf("cca_trusted_issuer cannot be parsed: %s", err.Error()))
+		return
+	}
+	now := time.Now()
+	if now.Before(certificate.NotBefore) || now.After(certificate.NotAfter) {
+		add(KeystorePreflightCheck_Status_Failed, fmt.Sprintf("the certificate '%s' is valid from %s to %s",
+			certificate.Subject.String(), certificate.NotBefore.Format(time.RFC3339),
+			certificate.NotAfter.Format(time.RFC3339)))
+	} else if !certificate.IsCA {
+		add(KeystorePreflightCheck_Status_Warning,
+			fmt.Sprintf("the certificate '%s' is not a CA certificate", certificate.Subject.String()))
+	}
+}
+
+// PreflightKeystoreOptions : The PreflightKeystore options.
+type PreflightKeystoreOptions struct {
+	// Keystore properties to check.
+	KeystoreBody KeystoreCreationRequestIntf `json:"keystoreBody" validate:"required"`
+
+	// Only run the client-side checks.
+	SkipDryRun *bool `json:"skip_dry_run,omitempty"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewPreflightKeystoreOptions : Instantiate PreflightKeystoreOptions
+func (*UkoV4) NewPreflightKeystoreOptions(keystoreBody KeystoreCreationRequestIntf) *PreflightKeystoreOptions {
+	return &PreflightKeystoreOptions{
+		KeystoreBody: keystoreBody,
+	}
+}
+
+// SetKeystoreBody : Allow user to set KeystoreBody
+func (_options *PreflightKeystoreOptions) SetKeystoreBody(keystoreBody KeystoreCreationRequestIntf) *PreflightKeystoreOptions {
+	_options.KeystoreBody = keystoreBody
+	return _options
+}
+
+// SetSkipDryRun : Allow user to set SkipDryRun
+func (_options *PreflightKeystoreOptions) SetSkipDryRun(skipDryRun bool) *PreflightKeystoreOptions {
+	_options.SkipDryRun = core.BoolPtr(skipDryRun)
+	return _options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *PreflightKeystoreOptions) SetHeaders(param map[string]string) *PreflightKeystoreOptions {
+	options.Headers = param
+	return options
+}
+
+// PreflightKeystoreUpdateOptions : The PreflightKeystoreUpdate options.
+type PreflightKeystoreUpdateOptions struct {
+	// UUID of the keystore.
+	ID *string `json:"id" validate:"required,ne="`
+
+	// Keystore properties to update.
+	KeystoreBody KeystoreUpdateRequestIntf `json:"keystoreBody" validate:"required"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewPreflightKeystoreUpdateOptions : Instantiate PreflightKeystoreUpdateOptions
+func (*UkoV4) NewPreflightKeystoreUpdateOptions(id string, keystoreBody KeystoreUpdateRequestIntf) *PreflightKeystoreUpdateOptions {
+	return &PreflightKeystoreUpdateOptions{
+		ID:           core.StringPtr(id),
+		KeystoreBody: keystoreBody,
+	}
+}
+
+// SetID : Allow user to set ID
+func (_options *PreflightKeystoreUpdateOptions) SetID(id string) *PreflightKeystoreUpdateOptions {
+	_options.ID = core.StringPtr(id)
+	return _options
+}
+
+// SetKeystoreBody : Allow user to set KeystoreBody
+func (_options *PreflightKeystoreUpdateOptions) SetKeystoreBody(keystoreBody KeystoreUpdateRequestIntf) *PreflightKeystoreUpdateOptions {
+	_options.KeystoreBody = keystoreBody
+	return _options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *PreflightKeystoreUpdateOptions) SetHeaders(param map[string]string) *PreflightKeystoreUpdateOptions {
+	options.Headers = param
+	return options
+}
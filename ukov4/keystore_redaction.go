@@ -0,0 +1,362 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// RedactedValue replaces the value of each credential in redacted keystore models.
+const RedactedValue = "REDACTED"
+
+// RedactedJSONFields are the JSON properties masked by MarshalRedactedJSON. They hold the keystore credentials;
+// identifiers such as aws_region, aws_access_key_id, google_project_id or azure_service_principal_client_id are kept.
+var RedactedJSONFields = map[string]bool{
+	"aws_secret_access_key":            true,
+	"azure_service_principal_password": true,
+	"google_credentials":               true,
+	"ibm_api_key":                      true,
+}
+
+// redactString returns RedactedValue in place of a non-empty value.
+func redactString(value *string) *string {
+	if value == nil || *value == "" {
+		return value
+	}
+	redacted := RedactedValue
+	return &redacted
+}
+
+// MarshalRedactedJSON returns the JSON encoding of a model with the value of every property listed in
+// RedactedJSONFields replaced by RedactedValue, at any depth. It accepts any model, such as a KeystoreList or a
+// ManagedKey, and is meant for logs, inventories and support cases. The models themselves keep the default encoding,
+// which the service requires to receive credentials.
+func MarshalRedactedJSON(model interface{}) ([]byte, error) {
+	buffer, err := json.Marshal(model)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(buffer))
+	decoder.UseNumber()
+	var document interface{}
+	err = decoder.Decode(&document)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(redactJSON(document))
+}
+
+func redactJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, property := range v {
+			if RedactedJSONFields[name] {
+				if s, ok := property.(string); ok && s != "" {
+					v[name] = RedactedValue
+				}
+				continue
+			}
+			v[name] = redactJSON(property)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactJSON(v[i])
+		}
+	}
+	return value
+}
+
+// RedactKeystore returns a redacted copy of a keystore returned by the service, such as the result of GetKeystore.
+// Keystore models of unknown types are converted to a redacted Keystore.
+func RedactKeystore(keystore KeystoreIntf) KeystoreIntf {
+	switch model := keystore.(type) {
+	case nil:
+		return nil
+	case *Keystore:
+		return model.Redacted()
+	case *KeystoreTypeAwsKms:
+		return model.Redacted()
+	case *KeystoreTypeAzure:
+		return model.Redacted()
+	case *KeystoreTypeCca:
+		return model.Redacted()
+	case *KeystoreTypeGoogleKms:
+		return model.Redacted()
+	case *KeystoreTypeIbmCloudKms:
+		return model.Redacted()
+	default:
+		generic := new(Keystore)
+		if err := remarshal(keystore, generic); err != nil {
+			return nil
+		}
+		return generic.Redacted()
+	}
+}
+
+// Redacted returns a deep copy with its credentials replaced by RedactedValue.
+func (model *Keystore) Redacted() *Keystore {
+	if model == nil {
+		return nil
+	}
+	redacted := new(Keystore)
+	if err := remarshal(model, redacted); err != nil {
+		return nil
+	}
+	redacted.AwsSecretAccessKey = redactString(redacted.AwsSecretAccessKey)
+	redacted.AzureServicePrincipalPassword = redactString(redacted.AzureServicePrincipalPassword)
+	redacted.GoogleCredentials = redactString(redacted.GoogleCredentials)
+	redacted.IbmApiKey = redactString(redacted.IbmApiKey)
+	return redacted
+}
+
+// Redacted returns a deep copy with its credentials replaced by RedactedValue.
+func (model *KeystoreTypeAwsKms) Redacted() *KeystoreTypeAwsKms {
+	if model == nil {
+		return nil
+	}
+	redacted := new(KeystoreTypeAwsKms)
+	if err := remarshal(model, redacted); err != nil {
+		return nil
+	}
+	redacted.AwsSecretAccessKey = redactString(redacted.AwsSecretAccessKey)
+	return redacted
+}
+
+// Redacted returns a deep copy with its credentials replaced by RedactedValue.
+func (model *KeystoreTypeAzure) Redacted() *KeystoreTypeAzure {
+	if model == nil {
+		return nil
+	}
+	redacted := new(KeystoreTypeAzure)
+	if err := remarshal(model, redacted); err != nil {
+		return nil
+	}
+	redacted.AzureServicePrincipalPassword = redactString(redacted.AzureServicePrincipalPassword)
+	return redacted
+}
+
+// Redacted returns a deep copy with its credentials replaced by RedactedValue.
+func (model *KeystoreTypeGoogleKms) Redacted() *KeystoreTypeGoogleKms {
+	if model == nil {
+		return nil
+	}
+	redacted := new(KeystoreTypeGoogleKms)
+	if err := remarshal(model, redacted); err != nil {
+		return nil
+	}
+	redacted.GoogleCredentials = redactString(redacted.GoogleCredentials)
+	return redacted
+}
+
+// Redacted returns a deep copy with its credentials replaced by RedactedValue.
+func (model *KeystoreTypeIbmCloudKms) Redacted() *KeystoreTypeIbmCloudKms {
+	if model == nil {
+		return nil
+	}
+	redacted := new(KeystoreTypeIbmCloudKms)
+	if err := remarshal(model, redacted); err != nil {
+		return nil
+	}
+	redacted.IbmApiKey = redactString(redacted.IbmApiKey)
+	return redacted
+}
+
+// Redacted returns a deep copy. CCA keystores have no credentials to mask.
+func (model *KeystoreTypeCca) Redacted() *KeystoreTypeCca {
+	if model == nil {
+		return nil
+	}
+	redacted := new(KeystoreTypeCca)
+	if err := remarshal(model, redacted); err != nil {
+		return nil
+	}
+	return redacted
+}
+
+// Redacted returns a deep copy with its credentials replaced by RedactedValue.
+func (model *KeystoreCreationRequest) Redacted() *KeystoreCreationRequest {
+	if model == nil {
+		return nil
+	}
+	redacted := new(KeystoreCreationRequest)
+	if err := remarshal(model, redacted); err != nil {
+		return nil
+	}
+	redacted.AwsSecretAccessKey = redactString(redacted.AwsSecretAccessKey)
+	redacted.AzureServicePrincipalPassword = redactString(redacted.AzureServicePrincipalPassword)
+	redacted.GoogleCredentials = redactString(redacted.GoogleCredentials)
+	redacted.IbmApiKey = redactString(redacted.IbmApiKey)
+	return redacted
+}
+
+// Redacted returns a deep copy with its credentials replaced by RedactedValue.
+func (model *KeystoreCreationRequestKeystoreTypeAwsKmsCreate) Redacted() *KeystoreCreationRequestKeystoreTypeAwsKmsCreate {
+	if model == nil {
+		return nil
+	}
+	redacted := new(KeystoreCreationRequestKeystoreTypeAwsKmsCreate)
+	if err := remarshal(model, redacted); err != nil {
+		return nil
+	}
+	redacted.AwsSecretAccessKey = redactString(redacted.AwsSecretAccessKey)
+	return redacted
+}
+
+// Redacted returns a deep copy with its credentials replaced by RedactedValue.
+func (model *KeystoreCreationRequestKeystoreTypeAzureCreate) Redacted() *KeystoreCreationRequestKeystoreTypeAzureCreate {
+	if model == nil {
+		return nil
+	}
+	redacted := new(KeystoreCreationRequestKeystoreTypeAzureCreate)
+	if err := remarshal(model, redacted); err != nil {
+		return nil
+	}
+	redacted.AzureServicePrincipalPassword = redactString(redacted.AzureServicePrincipalPassword)
+	return redacted
+}
+
+// Redacted returns a deep copy with its credentials replaced by RedactedValue.
+func (model *KeystoreCreationRequestKeystoreTypeGoogleKmsCreate) Redacted() *KeystoreCreationRequestKeystoreTypeGoogleKmsCreate {
+	if model == nil {
+		return nil
+	}
+	redacted := new(KeystoreCreationRequestKeystoreTypeGoogleKmsCreate)
+	if err := remarshal(model, redacted); err != nil {
+		return nil
+	}
+	redacted.GoogleCredentials = redactString(redacted.GoogleCredentials)
+	return redacted
+}
+
+// Redacted returns a deep copy with its credentials replaced by RedactedValue.
+func (model *KeystoreCreationRequestKeystoreTypeIbmCloudKmsInternalExternalCreate) Redacted() *KeystoreCreationRequestKeystoreTypeIbmCloudKmsInternalExternalCreate {
+	if model == nil {
+		return nil
+	}
+	redacted := new(KeystoreCreationRequestKeystoreTypeIbmCloudKmsInternalExternalCreate)
+	if err := remarshal(model, redacted); err != nil {
+		return nil
+	}
+	redacted.IbmApiKey = redactString(redacted.IbmApiKey)
+	return redacted
+}
+
+// Redacted returns a deep copy with its credentials replaced by RedactedValue.
+func (model *KeystoreCreationRequestKeystoreTypeIbmCloudKmsInternalExternalCreateKeystoreTypeIbmCloudKmsInternalExternalCreateKeystoreTypeIbmCloudKmsCreate) Redacted() *KeystoreCreationRequestKeystoreTypeIbmCloudKmsInternalExternalCreateKeystoreTypeIbmCloudKmsInternalExternalCreateKeystoreTypeIbmCloudKmsCreate {
+	if model == nil {
+		return nil
+	}
+	redacted := new(KeystoreCreationRequestKeystoreTypeIbmCloudKmsInternalExternalCreateKeystoreTypeIbmCloudKmsInternalExternalCreateKeystoreTypeIbmCloudKmsCreate)
+	if err := remarshal(model, redacted); err != nil {
+		return nil
+	}
+	redacted.IbmApiKey = redactString(redacted.IbmApiKey)
+	return redacted
+}
+
+// Redacted returns a deep copy. CCA keystores have no credentials to mask.
+func (model *KeystoreCreationRequestKeystoreTypeCcaCreate) Redacted() *KeystoreCreationRequestKeystoreTypeCcaCreate {
+	if model == nil {
+		return nil
+	}
+	redacted := new(KeystoreCreationRequestKeystoreTypeCcaCreate)
+	if err := remarshal(model, redacted); err != nil {
+		return nil
+	}
+	return redacted
+}
+
+// Redacted returns a deep copy with its credentials replaced by RedactedValue.
+func (model *KeystoreUpdateRequest) Redacted() *KeystoreUpdateRequest {
+	if model == nil {
+		return nil
+	}
+	redacted := new(KeystoreUpdateRequest)
+	if err := remarshal(model, redacted); err != nil {
+		return nil
+	}
+	redacted.AwsSecretAccessKey = redactString(redacted.AwsSecretAccessKey)
+	redacted.AzureServicePrincipalPassword = redactString(redacted.AzureServicePrincipalPassword)
+	redacted.GoogleCredentials = redactString(redacted.GoogleCredentials)
+	redacted.IbmApiKey = redactString(redacted.IbmApiKey)
+	return redacted
+}
+
+// Redacted returns a deep copy with its credentials replaced by RedactedValue.
+func (model *KeystoreUpdateRequestKeystoreTypeAwsKmsUpdate) Redacted() *KeystoreUpdateRequestKeystoreTypeAwsKmsUpdate {
+	if model == nil {
+		return nil
+	}
+	redacted := new(KeystoreUpdateRequestKeystoreTypeAwsKmsUpdate)
+	if err := remarshal(model, redacted); err != nil {
+		return nil
+	}
+	redacted.AwsSecretAccessKey = redactString(redacted.AwsSecretAccessKey)
+	return redacted
+}
+
+// Redacted returns a deep copy with its credentials replaced by RedactedValue.
+func (model *KeystoreUpdateRequestKeystoreTypeAzureUpdate) Redacted() *KeystoreUpdateRequestKeystoreTypeAzureUpdate {
+	if model == nil {
+		return nil
+	}
+	redacted := new(KeystoreUpdateRequestKeystoreTypeAzureUpdate)
+	if err := remarshal(model, redacted); err != nil {
+		return nil
+	}
+	redacted.AzureServicePrincipalPassword = redactString(redacted.AzureServicePrincipalPassword)
+	return redacted
+}
+
+// Redacted returns a deep copy with its credentials replaced by RedactedValue.
+func (model *KeystoreUpdateRequestKeystoreTypeGoogleKmsUpdate) Redacted() *KeystoreUpdateRequestKeystoreTypeGoogleKmsUpdate {
+	if model == nil {
+		return nil
+	}
+	redacted := new(KeystoreUpdateRequestKeystoreTypeGoogleKmsUpdate)
+	if err := remarshal(model, redacted); err != nil {
+		return nil
+	}
+	redacted.GoogleCredentials = redactString(redacted.GoogleCredentials)
+	return redacted
+}
+
+// Redacted returns a deep copy with its credentials replaced by RedactedValue.
+func (model *KeystoreUpdateRequestKeystoreTypeIbmCloudKmsUpdate) Redacted() *KeystoreUpdateRequestKeystoreTypeIbmCloudKmsUpdate {
+	if model == nil {
+		return nil
+	}
+	redacted := new(KeystoreUpdateRequestKeystoreTypeIbmCloudKmsUpdate)
+	if err := remarshal(model, redacted); err != nil {
+		return nil
+	}
+	redacted.IbmApiKey = redactString(redacted.IbmApiKey)
+	return redacted
+}
+
+// Redacted returns a deep copy. CCA keystores have no credentials to mask.
+func (model *KeystoreUpdateRequestKeystoreTypeCcaUpdate) Redacted() *KeystoreUpdateRequestKeystoreTypeCcaUpdate {
+	if model == nil {
+		return nil
+	}
+	redacted := new(KeystoreUpdateRequestKeystoreTypeCcaUpdate)
+	if err := remarshal(model, redacted); err != nil {
+		return nil
+	}
+	return redacted
+}
@@ -0,0 +1,118 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4_test
+
+import (
+	"encoding/json"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-hpcs-uko-sdk/ukov4"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`Keystore redaction`, func() {
+	It(`Redact credentials and keep identifiers`, func() {
+		keystore := &ukov4.Keystore{
+			Type:                          core.StringPtr("aws_kms"),
+			AwsRegion:                     core.StringPtr("eu-central-1"),
+			AwsAccessKeyID:                core.StringPtr("AKIA"),
+			AwsSecretAccessKey:            core.StringPtr("secret"),
+			GoogleProjectID:               core.StringPtr("project"),
+			GoogleCredentials:             core.StringPtr("e30="),
+			AzureServicePrincipalClientID: core.StringPtr("client"),
+			AzureServicePrincipalPassword: core.StringPtr("password"),
+			IbmApiKey:                     core.StringPtr(""),
+		}
+		redacted := keystore.Redacted()
+		Expect(*redacted.AwsRegion).To(Equal("eu-central-1"))
+		Expect(*redacted.AwsAccessKeyID).To(Equal("AKIA"))
+		Expect(*redacted.AwsSecretAccessKey).To(Equal(ukov4.RedactedValue))
+		Expect(*redacted.GoogleProjectID).To(Equal("project"))
+		Expect(*redacted.GoogleCredentials).To(Equal(ukov4.RedactedValue))
+		Expect(*redacted.AzureServicePrincipalClientID).To(Equal("client"))
+		Expect(*redacted.AzureServicePrincipalPassword).To(Equal(ukov4.RedactedValue))
+		Expect(*redacted.IbmApiKey).To(Equal(""))
+		Expect(*keystore.AwsSecretAccessKey).To(Equal("secret"))
+
+		var nilKeystore *ukov4.Keystore
+		Expect(nilKeystore.Redacted()).To(BeNil())
+	})
+	It(`Leave the original unchanged when the redacted copy is modified`, func() {
+		keystore := &ukov4.Keystore{
+			Name:               core.StringPtr("name"),
+			Groups:             []string{"group"},
+			Vault:              &ukov4.VaultReference{ID: core.StringPtr("vault")},
+			AwsSecretAccessKey: core.StringPtr("secret"),
+		}
+		redacted := keystore.Redacted()
+		*redacted.Name = "changed"
+		redacted.Groups[0] = "changed"
+		*redacted.Vault.ID = "changed"
+		Expect(*keystore.Name).To(Equal("name"))
+		Expect(keystore.Groups).To(Equal([]string{"group"}))
+		Expect(*keystore.Vault.ID).To(Equal("vault"))
+		Expect(*keystore.AwsSecretAccessKey).To(Equal("secret"))
+
+		aws := &ukov4.KeystoreTypeAwsKms{Groups: []string{"group"}, AwsSecretAccessKey: core.StringPtr("secret")}
+		aws.Redacted().Groups[0] = "changed"
+		Expect(aws.Groups).To(Equal([]string{"group"}))
+	})
+	It(`Redact typed keystores, creation requests and update requests`, func() {
+		Expect(*(&ukov4.KeystoreTypeAwsKms{AwsSecretAccessKey: core.StringPtr("s")}).Redacted().AwsSecretAccessKey).To(Equal(ukov4.RedactedValue))
+		Expect(*(&ukov4.KeystoreTypeAzure{AzureServicePrincipalPassword: core.StringPtr("s")}).Redacted().AzureServicePrincipalPassword).To(Equal(ukov4.RedactedValue))
+		Expect(*(&ukov4.KeystoreTypeGoogleKms{GoogleCredentials: core.StringPtr("s")}).Redacted().GoogleCredentials).To(Equal(ukov4.RedactedValue))
+		Expect(*(&ukov4.KeystoreTypeIbmCloudKms{IbmApiKey: core.StringPtr("s")}).Redacted().IbmApiKey).To(Equal(ukov4.RedactedValue))
+		Expect(*(&ukov4.KeystoreTypeCca{CcaHost: core.StringPtr("h")}).Redacted().CcaHost).To(Equal("h"))
+
+		create := &ukov4.KeystoreCreationRequestKeystoreTypeAwsKmsCreate{AwsRegion: core.StringPtr("r"), AwsSecretAccessKey: core.StringPtr("s")}
+		Expect(*create.Redacted().AwsSecretAccessKey).To(Equal(ukov4.RedactedValue))
+		Expect(*create.Redacted().AwsRegion).To(Equal("r"))
+		Expect(*(&ukov4.KeystoreCreationRequest{IbmApiKey: core.StringPtr("s")}).Redacted().IbmApiKey).To(Equal(ukov4.RedactedValue))
+		Expect(*(&ukov4.KeystoreUpdateRequest{GoogleCredentials: core.StringPtr("s")}).Redacted().GoogleCredentials).To(Equal(ukov4.RedactedValue))
+		Expect(*(&ukov4.KeystoreUpdateRequestKeystoreTypeAzureUpdate{AzureServicePrincipalPassword: core.StringPtr("s")}).Redacted().AzureServicePrincipalPassword).To(Equal(ukov4.RedactedValue))
+	})
+	It(`Redact a keystore returned by the service`, func() {
+		Expect(ukov4.RedactKeystore(nil)).To(BeNil())
+		redacted := ukov4.RedactKeystore(&ukov4.KeystoreTypeGoogleKms{GoogleCredentials: core.StringPtr("s"), GoogleProjectID: core.StringPtr("p")})
+		google, ok := redacted.(*ukov4.KeystoreTypeGoogleKms)
+		Expect(ok).To(BeTrue())
+		Expect(*google.GoogleCredentials).To(Equal(ukov4.RedactedValue))
+		Expect(*google.GoogleProjectID).To(Equal("p"))
+	})
+	It(`Marshal redacted JSON at any depth`, func() {
+		list := &ukov4.KeystoreList{
+			Keystores: []ukov4.KeystoreIntf{
+				&ukov4.Keystore{Name: core.StringPtr("aws"), AwsSecretAccessKey: core.StringPtr("s3cr3t-value"), CcaPort: core.Int64Ptr(9000)},
+			},
+		}
+		buffer, err := ukov4.MarshalRedactedJSON(list)
+		Expect(err).To(BeNil())
+		Expect(string(buffer)).ToNot(ContainSubstring("s3cr3t-value"))
+
+		var decoded map[string]interface{}
+		Expect(json.Unmarshal(buffer, &decoded)).To(Succeed())
+		keystore := decoded["keystores"].([]interface{})[0].(map[string]interface{})
+		Expect(keystore["aws_secret_access_key"]).To(Equal(ukov4.RedactedValue))
+		Expect(keystore["name"]).To(Equal("aws"))
+		Expect(keystore["cca_port"]).To(Equal(float64(9000)))
+
+		plain, err := json.Marshal(list)
+		Expect(err).To(BeNil())
+		Expect(string(plain)).To(ContainSubstring("s3cr3t-value"))
+	})
+})
@@ -0,0 +1,308 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4
+
+import (
+	"github.com/IBM/go-sdk-core/v5/core"
+)
+
+// The As methods of the keystore and key instance models return the type-specific model of a keystore or key
+// instance. Keystores and key instances returned by the service are already unmarshalled into their type-specific
+// model; a Keystore or KeyInstance, such as one of an unknown type or one built by the caller, is converted into a copy.
+// Code that asserted them to *Keystore or *KeyInstance must use the As methods or the visitors instead; the typed
+// accessors such as GetType, GetAzureLocation and GetIbmVariant are also defined on the type-specific keystore models.
+
+// KeystoreVisitor : The functions called by VisitKeystore for each type of keystore. A keystore for whose type no
+// function is set, or of an unknown type, is passed to Default, if set.
+type KeystoreVisitor struct {
+	AwsKms      func(keystore *KeystoreTypeAwsKms) error
+	Azure       func(keystore *KeystoreTypeAzure) error
+	Cca         func(keystore *KeystoreTypeCca) error
+	GoogleKms   func(keystore *KeystoreTypeGoogleKms) error
+	IbmCloudKms func(keystore *KeystoreTypeIbmCloudKms) error
+	Default     func(keystore KeystoreIntf) error
+}
+
+// VisitKeystore calls the function of the visitor for the type of the keystore, and returns its error.
+func VisitKeystore(keystore KeystoreIntf, visitor *KeystoreVisitor) error {
+	if keystore == nil {
+		return nil
+	}
+	if model, ok := keystore.AsAwsKms(); ok && visitor.AwsKms != nil {
+		return visitor.AwsKms(model)
+	}
+	if model, ok := keystore.AsAzure(); ok && visitor.Azure != nil {
+		return visitor.Azure(model)
+	}
+	if model, ok := keystore.AsCca(); ok && visitor.Cca != nil {
+		return visitor.Cca(model)
+	}
+	if model, ok := keystore.AsGoogleKms(); ok && visitor.GoogleKms != nil {
+		return visitor.GoogleKms(model)
+	}
+	if model, ok := keystore.AsIbmCloudKms(); ok && visitor.IbmCloudKms != nil {
+		return visitor.IbmCloudKms(model)
+	}
+	if visitor.Default != nil {
+		return visitor.Default(keystore)
+	}
+	return nil
+}
+
+// KeyInstanceVisitor : The functions called by VisitKeyInstance for each type of keystore a key instance is in. A key
+// instance for whose keystore type no function is set, or in a keystore of an unknown type, is passed to Default, if
+// set.
+type KeyInstanceVisitor struct {
+	AwsKms      func(instance *KeyInstanceAwsKms) error
+	Azure       func(instance *KeyInstanceAzure) error
+	Cca         func(instance *KeyInstanceCca) error
+	GoogleKms   func(instance *KeyInstanceGoogleKms) error
+	IbmCloudKms func(instance *KeyInstanceIbmCloudKms) error
+	Default     func(instance KeyInstanceIntf) error
+}
+
+// VisitKeyInstance calls the function of the visitor for the keystore type of the key instance, and returns its error.
+func VisitKeyInstance(instance KeyInstanceIntf, visitor *KeyInstanceVisitor) error {
+	if instance == nil {
+		return nil
+	}
+	if model, ok := instance.AsAwsKms(); ok && visitor.AwsKms != nil {
+		return visitor.AwsKms(model)
+	}
+	if model, ok := instance.AsAzure(); ok && visitor.Azure != nil {
+		return visitor.Azure(model)
+	}
+	if model, ok := instance.AsCca(); ok && visitor.Cca != nil {
+		return visitor.Cca(model)
+	}
+	if model, ok := instance.AsGoogleKms(); ok && visitor.GoogleKms != nil {
+		return visitor.GoogleKms(model)
+	}
+	if model, ok := instance.AsIbmCloudKms(); ok && visitor.IbmCloudKms != nil {
+		return visitor.IbmCloudKms(model)
+	}
+	if visitor.Default != nil {
+		return visitor.Default(instance)
+	}
+	return nil
+}
+
+// keyInstanceKeystore returns the keystore a key instance is in.
+func keyInstanceKeystore(instance KeyInstanceIntf) *InstanceInKeystore {
+	switch model := instance.(type) {
+	case *KeyInstance:
+		return model.Keystore
+	case *KeyInstanceAwsKms:
+		return model.Keystore
+	case *KeyInstanceAzure:
+		return model.Keystore
+	case *KeyInstanceCca:
+		return model.Keystore
+	case *KeyInstanceGoogleKms:
+		return model.Keystore
+	case *KeyInstanceIbmCloudKms:
+		return model.Keystore
+	}
+	return nil
+}
+
+// convertKeystore copies a Keystore of the specified type into its type-specific model.
+func convertKeystore(keystore *Keystore, keystoreType string, result KeystoreIntf) bool {
+	return keystore != nil && core.StringNilMapper(keystore.Type) == keystoreType && remarshal(keystore, result) == nil
+}
+
+// convertKeyInstance copies a KeyInstance in a keystore of the specified type into its type-specific model.
+func convertKeyInstance(instance *KeyInstance, keystoreType string, result KeyInstanceIntf) bool {
+	return instance != nil && instance.Keystore != nil && core.StringNilMapper(instance.Keystore.Type) == keystoreType &&
+		remarshal(instance, result) == nil
+}
+
+// AsAwsKms returns a copy of the keystore as a KeystoreTypeAwsKms, if it is an AWS KMS keystore.
+func (keystore *Keystore) AsAwsKms() (*KeystoreTypeAwsKms, bool) {
+	result := new(KeystoreTypeAwsKms)
+	if !convertKeystore(keystore, Keystore_Type_AwsKms, result) {
+		return nil, false
+	}
+	return result, true
+}
+
+// AsAzure returns a copy of the keystore as a KeystoreTypeAzure, if it is an Azure Key Vault keystore.
+func (keystore *Keystore) AsAzure() (*KeystoreTypeAzure, bool) {
+	result := new(KeystoreTypeAzure)
+	if !convertKeystore(keystore, Keystore_Type_AzureKeyVault, result) {
+		return nil, false
+	}
+	return result, true
+}
+
+// AsCca returns a copy of the keystore as a KeystoreTypeCca, if it is a CCA keystore.
+func (keystore *Keystore) AsCca() (*KeystoreTypeCca, bool) {
+	result := new(KeystoreTypeCca)
+	if !convertKeystore(keystore, Keystore_Type_Cca, result) {
+		return nil, false
+	}
+	return result, true
+}
+
+// AsGoogleKms returns a copy of the keystore as a KeystoreTypeGoogleKms, if it is a Google Cloud KMS keystore.
+func (keystore *Keystore) AsGoogleKms() (*KeystoreTypeGoogleKms, bool) {
+	result := new(KeystoreTypeGoogleKms)
+	if !convertKeystore(keystore, Keystore_Type_GoogleKms, result) {
+		return nil, false
+	}
+	return result, true
+}
+
+// AsIbmCloudKms returns a copy of the keystore as a KeystoreTypeIbmCloudKms, if it is an IBM Cloud KMS keystore.
+func (keystore *Keystore) AsIbmCloudKms() (*KeystoreTypeIbmCloudKms, bool) {
+	result := new(KeystoreTypeIbmCloudKms)
+	if !convertKeystore(keystore, Keystore_Type_IbmCloudKms, result) {
+		return nil, false
+	}
+	return result, true
+}
+
+// AsAwsKms returns the keystore itself.
+func (keystore *KeystoreTypeAwsKms) AsAwsKms() (*KeystoreTypeAwsKms, bool) {
+	return keystore, keystore != nil
+}
+func (*KeystoreTypeAwsKms) AsAzure() (*KeystoreTypeAzure, bool)             { return nil, false }
+func (*KeystoreTypeAwsKms) AsCca() (*KeystoreTypeCca, bool)                 { return nil, false }
+func (*KeystoreTypeAwsKms) AsGoogleKms() (*KeystoreTypeGoogleKms, bool)     { return nil, false }
+func (*KeystoreTypeAwsKms) AsIbmCloudKms() (*KeystoreTypeIbmCloudKms, bool) { return nil, false }
+
+// AsAzure returns the keystore itself.
+func (keystore *KeystoreTypeAzure) AsAzure() (*KeystoreTypeAzure, bool) {
+	return keystore, keystore != nil
+}
+func (*KeystoreTypeAzure) AsAwsKms() (*KeystoreTypeAwsKms, bool)           { return nil, false }
+func (*KeystoreTypeAzure) AsCca() (*KeystoreTypeCca, bool)                 { return nil, false }
+func (*KeystoreTypeAzure) AsGoogleKms() (*KeystoreTypeGoogleKms, bool)     { return nil, false }
+func (*KeystoreTypeAzure) AsIbmCloudKms() (*KeystoreTypeIbmCloudKms, bool) { return nil, false }
+
+// AsCca returns the keystore itself.
+func (keystore *KeystoreTypeCca) AsCca() (*KeystoreTypeCca, bool)        { return keystore, keystore != nil }
+func (*KeystoreTypeCca) AsAwsKms() (*KeystoreTypeAwsKms, bool)           { return nil, false }
+func (*KeystoreTypeCca) AsAzure() (*KeystoreTypeAzure, bool)             { return nil, false }
+func (*KeystoreTypeCca) AsGoogleKms() (*KeystoreTypeGoogleKms, bool)     { return nil, false }
+func (*KeystoreTypeCca) AsIbmCloudKms() (*KeystoreTypeIbmCloudKms, bool) { return nil, false }
+
+// AsGoogleKms returns the keystore itself.
+func (keystore *KeystoreTypeGoogleKms) AsGoogleKms() (*KeystoreTypeGoogleKms, bool) {
+	return keystore, keystore != nil
+}
+func (*KeystoreTypeGoogleKms) AsAwsKms() (*KeystoreTypeAwsKms, bool)           { return nil, false }
+func (*KeystoreTypeGoogleKms) AsAzure() (*KeystoreTypeAzure, bool)             { return nil, false }
+func (*KeystoreTypeGoogleKms) AsCca() (*KeystoreTypeCca, bool)                 { return nil, false }
+func (*KeystoreTypeGoogleKms) AsIbmCloudKms() (*KeystoreTypeIbmCloudKms, bool) { return nil, false }
+
+// AsIbmCloudKms returns the keystore itself.
+func (keystore *KeystoreTypeIbmCloudKms) AsIbmCloudKms() (*KeystoreTypeIbmCloudKms, bool) {
+	return keystore, keystore != nil
+}
+func (*KeystoreTypeIbmCloudKms) AsAwsKms() (*KeystoreTypeAwsKms, bool)       { return nil, false }
+func (*KeystoreTypeIbmCloudKms) AsAzure() (*KeystoreTypeAzure, bool)         { return nil, false }
+func (*KeystoreTypeIbmCloudKms) AsCca() (*KeystoreTypeCca, bool)             { return nil, false }
+func (*KeystoreTypeIbmCloudKms) AsGoogleKms() (*KeystoreTypeGoogleKms, bool) { return nil, false }
+
+// AsAwsKms returns a copy of the key instance as a KeyInstanceAwsKms, if it is in an AWS KMS keystore.
+func (instance *KeyInstance) AsAwsKms() (*KeyInstanceAwsKms, bool) {
+	result := new(KeyInstanceAwsKms)
+	if !convertKeyInstance(instance, Keystore_Type_AwsKms, result) {
+		return nil, false
+	}
+	return result, true
+}
+
+// AsAzure returns a copy of the key instance as a KeyInstanceAzure, if it is in an Azure Key Vault keystore.
+func (instance *KeyInstance) AsAzure() (*KeyInstanceAzure, bool) {
+	result := new(KeyInstanceAzure)
+	if !convertKeyInstance(instance, Keystore_Type_AzureKeyVault, result) {
+		return nil, false
+	}
+	return result, true
+}
+
+// AsCca returns a copy of the key instance as a KeyInstanceCca, if it is in a CCA keystore.
+func (instance *KeyInstance) AsCca() (*KeyInstanceCca, bool) {
+	result := new(KeyInstanceCca)
+	if !convertKeyInstance(instance, Keystore_Type_Cca, result) {
+		return nil, false
+	}
+	return result, true
+}
+
+// AsGoogleKms returns a copy of the key instance as a KeyInstanceGoogleKms, if it is in a Google Cloud KMS keystore.
+func (instance *KeyInstance) AsGoogleKms() (*KeyInstanceGoogleKms, bool) {
+	result := new(KeyInstanceGoogleKms)
+	if !convertKeyInstance(instance, Keystore_Type_GoogleKms, result) {
+		return nil, false
+	}
+	return result, true
+}
+
+// AsIbmCloudKms returns a copy of the key instance as a KeyInstanceIbmCloudKms, if it is in an IBM Cloud KMS keystore.
+func (instance *KeyInstance) AsIbmCloudKms() (*KeyInstanceIbmCloudKms, bool) {
+	result := new(KeyInstanceIbmCloudKms)
+	if !convertKeyInstance(instance, Keystore_Type_IbmCloudKms, result) {
+		return nil, false
+	}
+	return result, true
+}
+
+// AsAwsKms returns the key instance itself.
+func (instance *KeyInstanceAwsKms) AsAwsKms() (*KeyInstanceAwsKms, bool) {
+	return instance, instance != nil
+}
+func (*KeyInstanceAwsKms) AsAzure() (*KeyInstanceAzure, bool)             { return nil, false }
+func (*KeyInstanceAwsKms) AsCca() (*KeyInstanceCca, bool)                 { return nil, false }
+func (*KeyInstanceAwsKms) AsGoogleKms() (*KeyInstanceGoogleKms, bool)     { return nil, false }
+func (*KeyInstanceAwsKms) AsIbmCloudKms() (*KeyInstanceIbmCloudKms, bool) { return nil, false }
+
+// AsAzure returns the key instance itself.
+func (instance *KeyInstanceAzure) AsAzure() (*KeyInstanceAzure, bool) {
+	return instance, instance != nil
+}
+func (*KeyInstanceAzure) AsAwsKms() (*KeyInstanceAwsKms, bool)           { return nil, false }
+func (*KeyInstanceAzure) AsCca() (*KeyInstanceCca, bool)                 { return nil, false }
+func (*KeyInstanceAzure) AsGoogleKms() (*KeyInstanceGoogleKms, bool)     { return nil, false }
+func (*KeyInstanceAzure) AsIbmCloudKms() (*KeyInstanceIbmCloudKms, bool) { return nil, false }
+
+// AsCca returns the key instance itself.
+func (instance *KeyInstanceCca) AsCca() (*KeyInstanceCca, bool)        { return instance, instance != nil }
+func (*KeyInstanceCca) AsAwsKms() (*KeyInstanceAwsKms, bool)           { return nil, false }
+func (*KeyInstanceCca) AsAzure() (*KeyInstanceAzure, bool)             { return nil, false }
+func (*KeyInstanceCca) AsGoogleKms() (*KeyInstanceGoogleKms, bool)     { return nil, false }
+func (*KeyInstanceCca) AsIbmCloudKms() (*KeyInstanceIbmCloudKms, bool) { return nil, false }
+
+// AsGoogleKms returns the key instance itself.
+func (instance *KeyInstanceGoogleKms) AsGoogleKms() (*KeyInstanceGoogleKms, bool) {
+	return instance, instance != nil
+}
+func (*KeyInstanceGoogleKms) AsAwsKms() (*KeyInstanceAwsKms, bool)           { return nil, false }
+func (*KeyInstanceGoogleKms) AsAzure() (*KeyInstanceAzure, bool)             { return nil, false }
+func (*KeyInstanceGoogleKms) AsCca() (*KeyInstanceCca, bool)                 { return nil, false }
+func (*KeyInstanceGoogleKms) AsIbmCloudKms() (*KeyInstanceIbmCloudKms, bool) { return nil, false }
+
+// AsIbmCloudKms returns the key instance itself.
+func (instance *KeyInstanceIbmCloudKms) AsIbmCloudKms() (*KeyInstanceIbmCloudKms, bool) {
+	return instance, instance != nil
+}
+func (*KeyInstanceIbmCloudKms) AsAwsKms() (*KeyInstanceAwsKms, bool)       { return nil, false }
+func (*KeyInstanceIbmCloudKms) AsAzure() (*KeyInstanceAzure, bool)         { return nil, false }
+func (*KeyInstanceIbmCloudKms) AsCca() (*KeyInstanceCca, bool)             { return nil, false }
+func (*KeyInstanceIbmCloudKms) AsGoogleKms() (*KeyInstanceGoogleKms, bool) { return nil, false }
@@ -0,0 +1,166 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-hpcs-uko-sdk/ukov4"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`Keystore and key instance types`, func() {
+	var testServer *httptest.Server
+	var ukoService *ukov4.UkoV4
+
+	BeforeEach(func() {
+		testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+
+			res.Header().Set("Content-type", "application/json")
+			switch req.Method + " " + req.URL.EscapedPath() {
+			case "GET /api/v4/keystores/ks1":
+				res.Header().Set("ETag", "etag-1")
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"id": "ks1", "name": "aws", "type": "aws_kms", "aws_region": "eu-central-1", "groups": ["eu"]}`)
+			case "GET /api/v4/keystores":
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"total_count": 2, "limit": 100, "offset": 0, "keystores": [`+
+					`{"id": "ks2", "type": "cca", "cca_host": "ekmf.example.com", "cca_port": 9443},`+
+					`{"id": "ks3", "type": "future_kms"}]}`)
+			case "GET /api/v4/managed_keys/k1":
+				res.Header().Set("ETag", "etag-2")
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"id": "k1", "label": "A", "state": "active", "algorithm": "aes", "instances": [`+
+					`{"id": "i1", "label_in_keystore": "A", "type": "secret_key", "keystore": {"group": "eu", "type": "google_kms"}, "google_kms_algorithm": "google_symmetric_encryption"},`+
+					`{"id": "i2", "label_in_keystore": "A", "type": "secret_key", "keystore": {"group": "eu", "type": "aws_kms"}},`+
+					`{"id": "i3", "label_in_keystore": "A", "type": "secret_key"}]}`)
+			default:
+				Fail("unexpected request " + req.Method + " " + req.URL.String())
+			}
+		}))
+		var err error
+		ukoService, err = ukov4.NewUkoV4(&ukov4.UkoV4Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(err).To(BeNil())
+	})
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	It(`Unmarshal keystores into their type-specific model`, func() {
+		result, _, err := ukoService.GetKeystore(ukoService.NewGetKeystoreOptions("ks1"))
+		Expect(err).To(BeNil())
+		keystore, ok := result.AsAwsKms()
+		Expect(ok).To(BeTrue())
+		Expect(keystore).To(BeIdenticalTo(result))
+		Expect(*keystore.AwsRegion).To(Equal("eu-central-1"))
+		_, ok = result.AsAzure()
+		Expect(ok).To(BeFalse())
+		Expect(result.GetType()).To(Equal(ukov4.KeystoreType_AwsKms))
+		_, ok = result.(*ukov4.Keystore)
+		Expect(ok).To(BeFalse())
+
+		list, _, err := ukoService.ListKeystores(ukoService.NewListKeystoresOptions())
+		Expect(err).To(BeNil())
+		Expect(list.Keystores[0]).To(BeAssignableToTypeOf(&ukov4.KeystoreTypeCca{}))
+		Expect(list.Keystores[1]).To(BeAssignableToTypeOf(&ukov4.Keystore{}))
+		Expect(list.Keystores[0].GetType()).To(Equal(ukov4.KeystoreType_Cca))
+		Expect(list.Keystores[1].GetType()).To(Equal(ukov4.KeystoreType("future_kms")))
+
+		var visited []string
+		visitor := &ukov4.KeystoreVisitor{
+			Cca: func(keystore *ukov4.KeystoreTypeCca) error {
+				visited = append(visited, fmt.Sprintf("cca %s:%d", *keystore.CcaHost, *keystore.CcaPort))
+				return nil
+			},
+			Default: func(keystore ukov4.KeystoreIntf) error {
+				return errors.New("unsupported keystore " + *keystore.(*ukov4.Keystore).Type)
+			},
+		}
+		Expect(ukov4.VisitKeystore(list.Keystores[0], visitor)).To(Succeed())
+		Expect(visited).To(Equal([]string{"cca ekmf.example.com:9443"}))
+		err = ukov4.VisitKeystore(list.Keystores[1], visitor)
+		Expect(err).To(MatchError("unsupported keystore future_kms"))
+		Expect(ukov4.VisitKeystore(nil, visitor)).To(Succeed())
+	})
+	It(`Convert a generic Keystore into its type-specific model`, func() {
+		generic := &ukov4.Keystore{
+			Type:             core.StringPtr(ukov4.Keystore_Type_AzureKeyVault),
+			AzureServiceName: core.StringPtr("kv"),
+		}
+		keystore, ok := generic.AsAzure()
+		Expect(ok).To(BeTrue())
+		Expect(*keystore.AzureServiceName).To(Equal("kv"))
+		_, ok = generic.AsAwsKms()
+		Expect(ok).To(BeFalse())
+
+		var nilKeystore *ukov4.KeystoreTypeAzure
+		_, ok = nilKeystore.AsAzure()
+		Expect(ok).To(BeFalse())
+		_, ok = (&ukov4.Keystore{}).AsCca()
+		Expect(ok).To(BeFalse())
+	})
+	It(`Unmarshal key instances by the type of their keystore`, func() {
+		key, _, err := ukoService.GetManagedKey(ukoService.NewGetManagedKeyOptions("k1"))
+		Expect(err).To(BeNil())
+		Expect(key.Instances).To(HaveLen(3))
+
+		google, ok := key.Instances[0].AsGoogleKms()
+		Expect(ok).To(BeTrue())
+		Expect(*google.GoogleKmsAlgorithm).To(Equal("google_symmetric_encryption"))
+		_, ok = key.Instances[0].AsAwsKms()
+		Expect(ok).To(BeFalse())
+		Expect(key.Instances[1]).To(BeAssignableToTypeOf(&ukov4.KeyInstanceAwsKms{}))
+		Expect(key.Instances[2]).To(BeAssignableToTypeOf(&ukov4.KeyInstance{}))
+
+		var ids []string
+		visitor := &ukov4.KeyInstanceVisitor{
+			GoogleKms: func(instance *ukov4.KeyInstanceGoogleKms) error {
+				ids = append(ids, "google:"+*instance.ID)
+				return nil
+			},
+			Default: func(instance ukov4.KeyInstanceIntf) error {
+				ids = append(ids, "other")
+				return nil
+			},
+		}
+		for _, instance := range key.Instances {
+			Expect(ukov4.VisitKeyInstance(instance, visitor)).To(Succeed())
+		}
+		Expect(ids).To(Equal([]string{"google:i1", "other", "other"}))
+
+		generic := &ukov4.KeyInstance{
+			ID:       core.StringPtr("i4"),
+			Keystore: &ukov4.InstanceInKeystore{Type: core.StringPtr(ukov4.InstanceInKeystore_Type_Cca)},
+		}
+		cca, ok := generic.AsCca()
+		Expect(ok).To(BeTrue())
+		Expect(*cca.ID).To(Equal("i4"))
+
+		query, err := ukov4.ParseManagedKeyQuery(`instances.keystore.type = aws_kms`)
+		Expect(err).To(BeNil())
+		Expect(query.Matches(key)).To(BeTrue())
+	})
+})
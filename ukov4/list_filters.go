@@ -0,0 +1,311 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/go-openapi/strfmt"
+)
+
+// FilterConflictError : The error returned when list filters that cannot be used in conjunction are both set.
+type FilterConflictError struct {
+	// The query parameter that was set.
+	Parameter string
+
+	// The query parameter it cannot be used with.
+	ConflictsWith string
+}
+
+// Error implements the error interface.
+func (e *FilterConflictError) Error() string {
+	return fmt.Sprintf("query parameter '%s' cannot be used in conjunction with the '%s' query parameter", e.Parameter, e.ConflictsWith)
+}
+
+// listManagedKeysConflicts lists, for each ListManagedKeys query parameter, the query parameters it cannot be used with.
+var listManagedKeysConflicts = map[string][]string{
+	"activation_date":             {"activation_date_min", "activation_date_max"},
+	"expiration_date":             {"expiration_date_min", "expiration_date_max", "deactivation_date", "deactivation_date_min", "deactivation_date_max"},
+	"expiration_date_min":         {"deactivation_date", "deactivation_date_min", "deactivation_date_max"},
+	"expiration_date_max":         {"deactivation_date", "deactivation_date_min", "deactivation_date_max"},
+	"deactivation_date":           {"deactivation_date_min", "deactivation_date_max"},
+	"created_at":                  {"created_at_min", "created_at_max"},
+	"updated_at":                  {"updated_at_min", "updated_at_max"},
+	"size":                        {"size_min", "size_max"},
+	"referenced_keystores[].type": {"instances[].keystore.type"},
+}
+
+// listKeyTemplatesConflicts lists, for each ListKeyTemplates query parameter, the query parameters it cannot be used
+// with.
+var listKeyTemplatesConflicts = map[string][]string{
+	"created_at": {"created_at_min", "created_at_max"},
+	"updated_at": {"updated_at_min", "updated_at_max"},
+	"key.size":   {"key.size_min", "key.size_max"},
+}
+
+// findFilterConflict returns a *FilterConflictError for the first pair of conflicting query parameters that are both
+// set, considering only pairs that include the specified query parameter unless it is "".
+func findFilterConflict(conflicts map[string][]string, isSet func(string) bool, name string) error {
+	params := make([]string, 0, len(conflicts))
+	for param := range conflicts {
+		params = append(params, param)
+	}
+	sort.Strings(params)
+	for _, param := range params {
+		for _, conflict := range conflicts[param] {
+			if name != "" && name != param && name != conflict {
+				continue
+			}
+			if isSet(param) && isSet(conflict) {
+				if name == conflict {
+					return &FilterConflictError{Parameter: conflict, ConflictsWith: param}
+				}
+				return &FilterConflictError{Parameter: param, ConflictsWith: conflict}
+			}
+		}
+	}
+	return nil
+}
+
+// formatFilterDate formats a date the way date query parameters expect it.
+func formatFilterDate(date strfmt.Date) string {
+	return date.String()
+}
+
+// formatFilterDateTime formats a time the way date-time query parameters expect it: RFC 3339 in UTC, with
+// milliseconds.
+func formatFilterDateTime(t time.Time) string {
+	return strfmt.DateTime(t.UTC()).String()
+}
+
+// ListManagedKeysValidated : List managed keys after checking the filters
+// Returns the *FilterConflictError of ValidateFilters without sending the request if query parameters that cannot be
+// used in conjunction are both set; otherwise behaves as ListManagedKeys.
+func (uko *UkoV4) ListManagedKeysValidated(listManagedKeysOptions *ListManagedKeysOptions) (result *ManagedKeyList, response *core.DetailedResponse, err error) {
+	return uko.ListManagedKeysValidatedWithContext(context.Background(), listManagedKeysOptions)
+}
+
+// ListManagedKeysValidatedWithContext is an alternate form of the ListManagedKeysValidated method which supports a Context parameter
+func (uko *UkoV4) ListManagedKeysValidatedWithContext(ctx context.Context, listManagedKeysOptions *ListManagedKeysOptions) (result *ManagedKeyList, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(listManagedKeysOptions, "listManagedKeysOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = listManagedKeysOptions.ValidateFilters()
+	if err != nil {
+		return
+	}
+	return uko.ListManagedKeysWithContext(ctx, listManagedKeysOptions)
+}
+
+// ValidateFilters returns a *FilterConflictError if query parameters that cannot be used in conjunction are both set,
+// whichever setters were used to set them. ListManagedKeysValidated runs it before sending the request.
+func (_options *ListManagedKeysOptions) ValidateFilters() error {
+	return findFilterConflict(listManagedKeysConflicts, func(param string) bool {
+		return isKeyQueryParamSet(_options, param)
+	}, "")
+}
+
+// SetActivationDateValue : Allow user to set ActivationDate from a date
+func (_options *ListManagedKeysOptions) SetActivationDateValue(activationDate strfmt.Date) *ListManagedKeysOptions {
+	_options.ActivationDate = core.StringPtr(formatFilterDate(activationDate))
+	return _options
+}
+
+// SetActivationDateMinValue : Allow user to set ActivationDateMin from a date
+func (_options *ListManagedKeysOptions) SetActivationDateMinValue(activationDateMin strfmt.Date) *ListManagedKeysOptions {
+	_options.ActivationDateMin = core.StringPtr(formatFilterDate(activationDateMin))
+	return _options
+}
+
+// SetActivationDateMaxValue : Allow user to set ActivationDateMax from a date
+func (_options *ListManagedKeysOptions) SetActivationDateMaxValue(activationDateMax strfmt.Date) *ListManagedKeysOptions {
+	_options.ActivationDateMax = core.StringPtr(formatFilterDate(activationDateMax))
+	return _options
+}
+
+// SetDeactivationDateValue : Allow user to set DeactivationDate from a date
+func (_options *ListManagedKeysOptions) SetDeactivationDateValue(deactivationDate strfmt.Date) *ListManagedKeysOptions {
+	_options.DeactivationDate = core.StringPtr(formatFilterDate(deactivationDate))
+	return _options
+}
+
+// SetDeactivationDateMinValue : Allow user to set DeactivationDateMin from a date
+func (_options *ListManagedKeysOptions) SetDeactivationDateMinValue(deactivationDateMin strfmt.Date) *ListManagedKeysOptions {
+	_options.DeactivationDateMin = core.StringPtr(formatFilterDate(deactivationDateMin))
+	return _options
+}
+
+// SetDeactivationDateMaxValue : Allow user to set DeactivationDateMax from a date
+func (_options *ListManagedKeysOptions) SetDeactivationDateMaxValue(deactivationDateMax strfmt.Date) *ListManagedKeysOptions {
+	_options.DeactivationDateMax = core.StringPtr(formatFilterDate(deactivationDateMax))
+	return _options
+}
+
+// SetExpirationDateValue : Allow user to set ExpirationDate from a date
+func (_options *ListManagedKeysOptions) SetExpirationDateValue(expirationDate strfmt.Date) *ListManagedKeysOptions {
+	_options.ExpirationDate = core.StringPtr(formatFilterDate(expirationDate))
+	return _options
+}
+
+// SetExpirationDateMinValue : Allow user to set ExpirationDateMin from a date
+func (_options *ListManagedKeysOptions) SetExpirationDateMinValue(expirationDateMin strfmt.Date) *ListManagedKeysOptions {
+	_options.ExpirationDateMin = core.StringPtr(formatFilterDate(expirationDateMin))
+	return _options
+}
+
+// SetExpirationDateMaxValue : Allow user to set ExpirationDateMax from a date
+func (_options *ListManagedKeysOptions) SetExpirationDateMaxValue(expirationDateMax strfmt.Date) *ListManagedKeysOptions {
+	_options.ExpirationDateMax = core.StringPtr(formatFilterDate(expirationDateMax))
+	return _options
+}
+
+// SetCreatedAtValue : Allow user to set CreatedAt from a time
+func (_options *ListManagedKeysOptions) SetCreatedAtValue(createdAt time.Time) *ListManagedKeysOptions {
+	_options.CreatedAt = core.StringPtr(formatFilterDateTime(createdAt))
+	return _options
+}
+
+// SetCreatedAtMinValue : Allow user to set CreatedAtMin from a time
+func (_options *ListManagedKeysOptions) SetCreatedAtMinValue(createdAtMin time.Time) *ListManagedKeysOptions {
+	_options.CreatedAtMin = core.StringPtr(formatFilterDateTime(createdAtMin))
+	return _options
+}
+
+// SetCreatedAtMaxValue : Allow user to set CreatedAtMax from a time
+func (_options *ListManagedKeysOptions) SetCreatedAtMaxValue(createdAtMax time.Time) *ListManagedKeysOptions {
+	_options.CreatedAtMax = core.StringPtr(formatFilterDateTime(createdAtMax))
+	return _options
+}
+
+// SetUpdatedAtValue : Allow user to set UpdatedAt from a time
+func (_options *ListManagedKeysOptions) SetUpdatedAtValue(updatedAt time.Time) *ListManagedKeysOptions {
+	_options.UpdatedAt = core.StringPtr(formatFilterDateTime(updatedAt))
+	return _options
+}
+
+// SetUpdatedAtMinValue : Allow user to set UpdatedAtMin from a time
+func (_options *ListManagedKeysOptions) SetUpdatedAtMinValue(updatedAtMin time.Time) *ListManagedKeysOptions {
+	_options.UpdatedAtMin = core.StringPtr(formatFilterDateTime(updatedAtMin))
+	return _options
+}
+
+// SetUpdatedAtMaxValue : Allow user to set UpdatedAtMax from a time
+func (_options *ListManagedKeysOptions) SetUpdatedAtMaxValue(updatedAtMax time.Time) *ListManagedKeysOptions {
+	_options.UpdatedAtMax = core.StringPtr(formatFilterDateTime(updatedAtMax))
+	return _options
+}
+
+// SetRotatedAtMinValue : Allow user to set RotatedAtMin from a time
+func (_options *ListManagedKeysOptions) SetRotatedAtMinValue(rotatedAtMin time.Time) *ListManagedKeysOptions {
+	_options.RotatedAtMin = core.StringPtr(formatFilterDateTime(rotatedAtMin))
+	return _options
+}
+
+// SetRotatedAtMaxValue : Allow user to set RotatedAtMax from a time
+func (_options *ListManagedKeysOptions) SetRotatedAtMaxValue(rotatedAtMax time.Time) *ListManagedKeysOptions {
+	_options.RotatedAtMax = core.StringPtr(formatFilterDateTime(rotatedAtMax))
+	return _options
+}
+
+// ListKeyTemplatesValidated : List key templates after checking the filters
+// Returns the *FilterConflictError of ValidateFilters without sending the request if query parameters that cannot be
+// used in conjunction are both set; otherwise behaves as ListKeyTemplates.
+func (uko *UkoV4) ListKeyTemplatesValidated(listKeyTemplatesOptions *ListKeyTemplatesOptions) (result *TemplateList, response *core.DetailedResponse, err error) {
+	return uko.ListKeyTemplatesValidatedWithContext(context.Background(), listKeyTemplatesOptions)
+}
+
+// ListKeyTemplatesValidatedWithContext is an alternate form of the ListKeyTemplatesValidated method which supports a Context parameter
+func (uko *UkoV4) ListKeyTemplatesValidatedWithContext(ctx context.Context, listKeyTemplatesOptions *ListKeyTemplatesOptions) (result *TemplateList, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(listKeyTemplatesOptions, "listKeyTemplatesOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = listKeyTemplatesOptions.ValidateFilters()
+	if err != nil {
+		return
+	}
+	return uko.ListKeyTemplatesWithContext(ctx, listKeyTemplatesOptions)
+}
+
+// ValidateFilters returns a *FilterConflictError if query parameters that cannot be used in conjunction are both set,
+// whichever setters were used to set them. ListKeyTemplatesValidated runs it before sending the request.
+func (_options *ListKeyTemplatesOptions) ValidateFilters() error {
+	return findFilterConflict(listKeyTemplatesConflicts, func(param string) bool {
+		switch param {
+		case "created_at":
+			return _options.CreatedAt != nil
+		case "created_at_min":
+			return _options.CreatedAtMin != nil
+		case "created_at_max":
+			return _options.CreatedAtMax != nil
+		case "updated_at":
+			return _options.UpdatedAt != nil
+		case "updated_at_min":
+			return _options.UpdatedAtMin != nil
+		case "updated_at_max":
+			return _options.UpdatedAtMax != nil
+		case "key.size":
+			return _options.KeySize != nil
+		case "key.size_min":
+			return _options.KeySizeMin != nil
+		case "key.size_max":
+			return _options.KeySizeMax != nil
+		}
+		return false
+	}, "")
+}
+
+// SetCreatedAtValue : Allow user to set CreatedAt from a time
+func (_options *ListKeyTemplatesOptions) SetCreatedAtValue(createdAt time.Time) *ListKeyTemplatesOptions {
+	_options.CreatedAt = core.StringPtr(formatFilterDateTime(createdAt))
+	return _options
+}
+
+// SetCreatedAtMinValue : Allow user to set CreatedAtMin from a time
+func (_options *ListKeyTemplatesOptions) SetCreatedAtMinValue(createdAtMin time.Time) *ListKeyTemplatesOptions {
+	_options.CreatedAtMin = core.StringPtr(formatFilterDateTime(createdAtMin))
+	return _options
+}
+
+// SetCreatedAtMaxValue : Allow user to set CreatedAtMax from a time
+func (_options *ListKeyTemplatesOptions) SetCreatedAtMaxValue(createdAtMax time.Time) *ListKeyTemplatesOptions {
+	_options.CreatedAtMax = core.StringPtr(formatFilterDateTime(createdAtMax))
+	return _options
+}
+
+// SetUpdatedAtValue : Allow user to set UpdatedAt from a time
+func (_options *ListKeyTemplatesOptions) SetUpdatedAtValue(updatedAt time.Time) *ListKeyTemplatesOptions {
+	_options.UpdatedAt = core.StringPtr(formatFilterDateTime(updatedAt))
+	return _options
+}
+
+// SetUpdatedAtMinValue : Allow user to set UpdatedAtMin from a time
+func (_options *ListKeyTemplatesOptions) SetUpdatedAtMinValue(updatedAtMin time.Time) *ListKeyTemplatesOptions {
+	_options.UpdatedAtMin = core.StringPtr(formatFilterDateTime(updatedAtMin))
+	return _options
+}
+
+// SetUpdatedAtMaxValue : Allow user to set UpdatedAtMax from a time
+func (_options *ListKeyTemplatesOptions) SetUpdatedAtMaxValue(updatedAtMax time.Time) *ListKeyTemplatesOptions {
+	_options.UpdatedAtMax = core.StringPtr(formatFilterDateTime(updatedAtMax))
+	return _options
+}
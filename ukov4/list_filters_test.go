@@ -0,0 +1,133 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-hpcs-uko-sdk/ukov4"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`Typed list filters`, func() {
+	var testServer *httptest.Server
+	var requests int
+
+	BeforeEach(func() {
+		requests = 0
+		testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+
+			requests++
+			Expect(req.URL.Query().Get("created_at_min")).To(Equal("2023-03-01T08:30:00.000Z"))
+			res.Header().Set("Content-type", "application/json")
+			res.WriteHeader(200)
+			switch req.URL.EscapedPath() {
+			case "/api/v4/managed_keys":
+				Expect(req.URL.Query().Get("expiration_date_max")).To(Equal("2027-01-01"))
+				fmt.Fprint(res, `{"total_count": 0, "limit": 0, "offset": 0, "managed_keys": []}`)
+			case "/api/v4/templates":
+				fmt.Fprint(res, `{"total_count": 0, "limit": 0, "offset": 0, "templates": []}`)
+			default:
+				Fail(fmt.Sprintf("unexpected request %s", req.URL.EscapedPath()))
+			}
+		}))
+	})
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	newService := func() *ukov4.UkoV4 {
+		ukoService, serviceErr := ukov4.NewUkoV4(&ukov4.UkoV4Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+		return ukoService
+	}
+	createdAtMin := time.Date(2023, 3, 1, 9, 30, 0, 0, time.FixedZone("CET", 3600))
+
+	It(`Format typed filters the way the API expects`, func() {
+		ukoService := newService()
+		_, _, err := ukoService.ListManagedKeys(ukoService.NewListManagedKeysOptions().
+			SetCreatedAtMinValue(createdAtMin).
+			SetExpirationDateMaxValue(*CreateMockDate("2027-01-01")))
+		Expect(err).To(BeNil())
+		_, _, err = ukoService.ListKeyTemplates(ukoService.NewListKeyTemplatesOptions().SetCreatedAtMinValue(createdAtMin))
+		Expect(err).To(BeNil())
+		Expect(requests).To(Equal(2))
+	})
+	It(`Reject conflicting filters before sending the request (negative test)`, func() {
+		ukoService := newService()
+		_, _, err := ukoService.ListManagedKeysValidated(nil)
+		Expect(err).ToNot(BeNil())
+		_, _, err = ukoService.ListManagedKeysValidated(ukoService.NewListManagedKeysOptions().
+			SetCreatedAtMinValue(createdAtMin).
+			SetCreatedAt("2023-03-01T08:30:00Z"))
+		Expect(err).To(Equal(&ukov4.FilterConflictError{Parameter: "created_at", ConflictsWith: "created_at_min"}))
+		_, _, err = ukoService.ListKeyTemplatesValidated(ukoService.NewListKeyTemplatesOptions().
+			SetCreatedAtMinValue(createdAtMin).
+			SetCreatedAtMax("2023-03-02T00:00:00Z").
+			SetKeySize("256").
+			SetKeySizeMin("128"))
+		Expect(err).To(Equal(&ukov4.FilterConflictError{Parameter: "key.size", ConflictsWith: "key.size_min"}))
+		Expect(requests).To(Equal(0))
+
+		_, _, err = ukoService.ListManagedKeysValidated(ukoService.NewListManagedKeysOptions().
+			SetCreatedAtMinValue(createdAtMin).
+			SetExpirationDateMaxValue(*CreateMockDate("2027-01-01")))
+		Expect(err).To(BeNil())
+		_, _, err = ukoService.ListKeyTemplatesValidated(ukoService.NewListKeyTemplatesOptions().SetCreatedAtMinValue(createdAtMin))
+		Expect(err).To(BeNil())
+		Expect(requests).To(Equal(2))
+	})
+	It(`Detect conflicting filters set by any setter (negative test)`, func() {
+		managedKeysOptions := new(ukov4.ListManagedKeysOptions).
+			SetCreatedAtMinValue(createdAtMin).
+			SetCreatedAt("2023-03-01T08:30:00Z")
+		Expect(managedKeysOptions.ValidateFilters()).To(Equal(
+			&ukov4.FilterConflictError{Parameter: "created_at", ConflictsWith: "created_at_min"}))
+
+		managedKeysOptions = new(ukov4.ListManagedKeysOptions).
+			SetSizeMin("128").
+			SetSize("256")
+		Expect(managedKeysOptions.ValidateFilters()).To(Equal(
+			&ukov4.FilterConflictError{Parameter: "size", ConflictsWith: "size_min"}))
+
+		managedKeysOptions = new(ukov4.ListManagedKeysOptions).
+			SetExpirationDateMaxValue(*CreateMockDate("2027-01-01")).
+			SetDeactivationDateMinValue(*CreateMockDate("2026-01-01"))
+		Expect(managedKeysOptions.ValidateFilters()).To(BeAssignableToTypeOf(&ukov4.FilterConflictError{}))
+		Expect(new(ukov4.ListManagedKeysOptions).SetSizeMin("128").SetSizeMax("256").ValidateFilters()).To(Succeed())
+
+		keyTemplatesOptions := new(ukov4.ListKeyTemplatesOptions).
+			SetUpdatedAtValue(createdAtMin).
+			SetUpdatedAtMaxValue(createdAtMin)
+		Expect(keyTemplatesOptions.ValidateFilters()).To(Equal(
+			&ukov4.FilterConflictError{Parameter: "updated_at", ConflictsWith: "updated_at_max"}))
+		keyTemplatesOptions = new(ukov4.ListKeyTemplatesOptions).
+			SetKeySize("256").
+			SetKeySizeMax("512")
+		Expect(keyTemplatesOptions.ValidateFilters()).To(Equal(
+			&ukov4.FilterConflictError{Parameter: "key.size", ConflictsWith: "key.size_max"}))
+	})
+})
@@ -0,0 +1,351 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SortError : The error returned for a sort specification the API does not accept.
+type SortError struct {
+	// The resources being listed.
+	Resource string
+
+	// The offending sort field.
+	Field string
+
+	// A description of the problem.
+	Message string
+}
+
+// Error implements the error interface.
+func (e *SortError) Error() string {
+	return fmt.Sprintf("invalid sort field '%s' for %s: %s", e.Field, e.Resource, e.Message)
+}
+
+// sortSpec accumulates the fields of a sort specification, remembering the first invalid one.
+type sortSpec struct {
+	resource string
+	valid    []string
+	fields   []string
+	err      error
+}
+
+func (spec *sortSpec) add(field string, descending bool) {
+	if spec.err != nil {
+		return
+	}
+	valid := false
+	for _, name := range spec.valid {
+		valid = valid || name == field
+	}
+	if !valid {
+		spec.err = &SortError{spec.resource, field, fmt.Sprintf("must be one of %s", strings.Join(spec.valid, ", "))}
+		return
+	}
+	for _, existing := range spec.fields {
+		if strings.TrimPrefix(existing, "-") == field {
+			spec.err = &SortError{spec.resource, field, "specified more than once"}
+			return
+		}
+	}
+	if descending {
+		field = "-" + field
+	}
+	spec.fields = append(spec.fields, field)
+}
+
+func (spec *sortSpec) build() ([]string, error) {
+	if spec.err != nil {
+		return nil, spec.err
+	}
+	return append([]string{}, spec.fields...), nil
+}
+
+// parse adds the fields of a sort parameter in the API's syntax, where a '-' prefix means descending order.
+func (spec *sortSpec) parse(sort []string) {
+	for _, field := range sort {
+		spec.add(strings.TrimPrefix(field, "-"), strings.HasPrefix(field, "-"))
+	}
+}
+
+// ManagedKeysSortField : A field that ListManagedKeysOptions.Sort accepts.
+type ManagedKeysSortField string
+
+// Constants for the sort fields accepted by ListManagedKeysOptions.Sort.
+// The fields documented by ListManagedKeys.
+const (
+	ListManagedKeysOptions_Sort_Label            ManagedKeysSortField = "label"
+	ListManagedKeysOptions_Sort_Algorithm        ManagedKeysSortField = "algorithm"
+	ListManagedKeysOptions_Sort_State            ManagedKeysSortField = "state"
+	ListManagedKeysOptions_Sort_ActivationDate   ManagedKeysSortField = "activation_date"
+	ListManagedKeysOptions_Sort_DeactivationDate ManagedKeysSortField = "deactivation_date"
+	ListManagedKeysOptions_Sort_CreatedAt        ManagedKeysSortField = "created_at"
+	ListManagedKeysOptions_Sort_UpdatedAt        ManagedKeysSortField = "updated_at"
+	ListManagedKeysOptions_Sort_Size             ManagedKeysSortField = "size"
+	ListManagedKeysOptions_Sort_VaultID          ManagedKeysSortField = "vault.id"
+)
+
+// ManagedKeysSort : A builder for the Sort parameter of ListManagedKeysOptions.
+// Fields are validated as they are added and the first invalid one is reported by Build.
+type ManagedKeysSort struct {
+	spec sortSpec
+}
+
+// NewManagedKeysSort : Instantiate ManagedKeysSort
+func NewManagedKeysSort() *ManagedKeysSort {
+	return &ManagedKeysSort{spec: sortSpec{resource: "managed keys", valid: []string{
+		string(ListManagedKeysOptions_Sort_Label),
+		string(ListManagedKeysOptions_Sort_Algorithm),
+		string(ListManagedKeysOptions_Sort_State),
+		string(ListManagedKeysOptions_Sort_ActivationDate),
+		string(ListManagedKeysOptions_Sort_DeactivationDate),
+		string(ListManagedKeysOptions_Sort_CreatedAt),
+		string(ListManagedKeysOptions_Sort_UpdatedAt),
+		string(ListManagedKeysOptions_Sort_Size),
+		string(ListManagedKeysOptions_Sort_VaultID),
+	}}}
+}
+
+// ParseManagedKeysSort validates a Sort parameter of ListManagedKeysOptions written in the API's syntax and returns the
+// equivalent builder.
+func ParseManagedKeysSort(sort []string) (*ManagedKeysSort, error) {
+	builder := NewManagedKeysSort()
+	builder.spec.parse(sort)
+	return builder, builder.spec.err
+}
+
+// Asc : Sort by the specified field in ascending order
+func (sort *ManagedKeysSort) Asc(field ManagedKeysSortField) *ManagedKeysSort {
+	sort.spec.add(string(field), false)
+	return sort
+}
+
+// Desc : Sort by the specified field in descending order
+func (sort *ManagedKeysSort) Desc(field ManagedKeysSortField) *ManagedKeysSort {
+	sort.spec.add(string(field), true)
+	return sort
+}
+
+// Build returns the Sort parameter in the API's syntax, or a *SortError for the first invalid field.
+func (sort *ManagedKeysSort) Build() ([]string, error) {
+	return sort.spec.build()
+}
+
+// SetSortBy : Allow user to set Sort from a sort builder
+// Returns the *SortError of the first invalid field of the builder, in which case Sort is left unchanged.
+func (_options *ListManagedKeysOptions) SetSortBy(sort *ManagedKeysSort) (*ListManagedKeysOptions, error) {
+	fields, err := sort.Build()
+	if err != nil {
+		return _options, err
+	}
+	_options.Sort = fields
+	return _options, nil
+}
+
+// KeystoresSortField : A field that ListKeystoresOptions.Sort accepts.
+type KeystoresSortField string
+
+// Constants for the sort fields accepted by ListKeystoresOptions.Sort.
+// The fields documented by CreateKeystore for listing keystores.
+const (
+	ListKeystoresOptions_Sort_Name      KeystoresSortField = "name"
+	ListKeystoresOptions_Sort_CreatedAt KeystoresSortField = "created_at"
+	ListKeystoresOptions_Sort_UpdatedAt KeystoresSortField = "updated_at"
+	ListKeystoresOptions_Sort_VaultID   KeystoresSortField = "vault.id"
+)
+
+// KeystoresSort : A builder for the Sort parameter of ListKeystoresOptions.
+// Fields are validated as they are added and the first invalid one is reported by Build.
+type KeystoresSort struct {
+	spec sortSpec
+}
+
+// NewKeystoresSort : Instantiate KeystoresSort
+func NewKeystoresSort() *KeystoresSort {
+	return &KeystoresSort{spec: sortSpec{resource: "keystores", valid: []string{
+		string(ListKeystoresOptions_Sort_Name),
+		string(ListKeystoresOptions_Sort_CreatedAt),
+		string(ListKeystoresOptions_Sort_UpdatedAt),
+		string(ListKeystoresOptions_Sort_VaultID),
+	}}}
+}
+
+// ParseKeystoresSort validates a Sort parameter of ListKeystoresOptions written in the API's syntax and returns the
+// equivalent builder.
+func ParseKeystoresSort(sort []string) (*KeystoresSort, error) {
+	builder := NewKeystoresSort()
+	builder.spec.parse(sort)
+	return builder, builder.spec.err
+}
+
+// Asc : Sort by the specified field in ascending order
+func (sort *KeystoresSort) Asc(field KeystoresSortField) *KeystoresSort {
+	sort.spec.add(string(field), false)
+	return sort
+}
+
+// Desc : Sort by the specified field in descending order
+func (sort *KeystoresSort) Desc(field KeystoresSortField) *KeystoresSort {
+	sort.spec.add(string(field), true)
+	return sort
+}
+
+// Build returns the Sort parameter in the API's syntax, or a *SortError for the first invalid field.
+func (sort *KeystoresSort) Build() ([]string, error) {
+	return sort.spec.build()
+}
+
+// SetSortBy : Allow user to set Sort from a sort builder
+// Returns the *SortError of the first invalid field of the builder, in which case Sort is left unchanged.
+func (_options *ListKeystoresOptions) SetSortBy(sort *KeystoresSort) (*ListKeystoresOptions, error) {
+	fields, err := sort.Build()
+	if err != nil {
+		return _options, err
+	}
+	_options.Sort = fields
+	return _options, nil
+}
+
+// KeyTemplatesSortField : A field that ListKeyTemplatesOptions.Sort accepts.
+type KeyTemplatesSortField string
+
+// Constants for the sort fields accepted by ListKeyTemplatesOptions.Sort.
+// The API does not document the sort fields of ListKeyTemplates; these are the fields it documents for keystores,
+// which key templates share.
+const (
+	ListKeyTemplatesOptions_Sort_Name      KeyTemplatesSortField = "name"
+	ListKeyTemplatesOptions_Sort_CreatedAt KeyTemplatesSortField = "created_at"
+	ListKeyTemplatesOptions_Sort_UpdatedAt KeyTemplatesSortField = "updated_at"
+	ListKeyTemplatesOptions_Sort_VaultID   KeyTemplatesSortField = "vault.id"
+)
+
+// KeyTemplatesSort : A builder for the Sort parameter of ListKeyTemplatesOptions.
+// Fields are validated as they are added and the first invalid one is reported by Build.
+type KeyTemplatesSort struct {
+	spec sortSpec
+}
+
+// NewKeyTemplatesSort : Instantiate KeyTemplatesSort
+func NewKeyTemplatesSort() *KeyTemplatesSort {
+	return &KeyTemplatesSort{spec: sortSpec{resource: "key templates", valid: []string{
+		string(ListKeyTemplatesOptions_Sort_Name),
+		string(ListKeyTemplatesOptions_Sort_CreatedAt),
+		string(ListKeyTemplatesOptions_Sort_UpdatedAt),
+		string(ListKeyTemplatesOptions_Sort_VaultID),
+	}}}
+}
+
+// ParseKeyTemplatesSort validates a Sort parameter of ListKeyTemplatesOptions written in the API's syntax and returns
+// the equivalent builder.
+func ParseKeyTemplatesSort(sort []string) (*KeyTemplatesSort, error) {
+	builder := NewKeyTemplatesSort()
+	builder.spec.parse(sort)
+	return builder, builder.spec.err
+}
+
+// Asc : Sort by the specified field in ascending order
+func (sort *KeyTemplatesSort) Asc(field KeyTemplatesSortField) *KeyTemplatesSort {
+	sort.spec.add(string(field), false)
+	return sort
+}
+
+// Desc : Sort by the specified field in descending order
+func (sort *KeyTemplatesSort) Desc(field KeyTemplatesSortField) *KeyTemplatesSort {
+	sort.spec.add(string(field), true)
+	return sort
+}
+
+// Build returns the Sort parameter in the API's syntax, or a *SortError for the first invalid field.
+func (sort *KeyTemplatesSort) Build() ([]string, error) {
+	return sort.spec.build()
+}
+
+// SetSortBy : Allow user to set Sort from a sort builder
+// Returns the *SortError of the first invalid field of the builder, in which case Sort is left unchanged.
+func (_options *ListKeyTemplatesOptions) SetSortBy(sort *KeyTemplatesSort) (*ListKeyTemplatesOptions, error) {
+	fields, err := sort.Build()
+	if err != nil {
+		return _options, err
+	}
+	_options.Sort = fields
+	return _options, nil
+}
+
+// VaultsSortField : A field that ListVaultsOptions.Sort accepts.
+type VaultsSortField string
+
+// Constants for the sort fields accepted by ListVaultsOptions.Sort.
+// The API does not document the sort fields of ListVaults; these are the fields it documents for keystores, except
+// vault.id, which vaults do not have.
+const (
+	ListVaultsOptions_Sort_Name      VaultsSortField = "name"
+	ListVaultsOptions_Sort_CreatedAt VaultsSortField = "created_at"
+	ListVaultsOptions_Sort_UpdatedAt VaultsSortField = "updated_at"
+)
+
+// VaultsSort : A builder for the Sort parameter of ListVaultsOptions.
+// Fields are validated as they are added and the first invalid one is reported by Build.
+type VaultsSort struct {
+	spec sortSpec
+}
+
+// NewVaultsSort : Instantiate VaultsSort
+func NewVaultsSort() *VaultsSort {
+	return &VaultsSort{spec: sortSpec{resource: "vaults", valid: []string{
+		string(ListVaultsOptions_Sort_Name),
+		string(ListVaultsOptions_Sort_CreatedAt),
+		string(ListVaultsOptions_Sort_UpdatedAt),
+	}}}
+}
+
+// ParseVaultsSort validates a Sort parameter of ListVaultsOptions written in the API's syntax and returns the
+// equivalent builder.
+func ParseVaultsSort(sort []string) (*VaultsSort, error) {
+	builder := NewVaultsSort()
+	builder.spec.parse(sort)
+	return builder, builder.spec.err
+}
+
+// Asc : Sort by the specified field in ascending order
+func (sort *VaultsSort) Asc(field VaultsSortField) *VaultsSort {
+	sort.spec.add(string(field), false)
+	return sort
+}
+
+// Desc : Sort by the specified field in descending order
+func (sort *VaultsSort) Desc(field VaultsSortField) *VaultsSort {
+	sort.spec.add(string(field), true)
+	return sort
+}
+
+// Build returns the Sort parameter in the API's syntax, or a *SortError for the first invalid field.
+func (sort *VaultsSort) Build() ([]string, error) {
+	return sort.spec.build()
+}
+
+// SetSortBy : Allow user to set Sort from a sort builder
+// Returns the *SortError of the first invalid field of the builder, in which case Sort is left unchanged.
+func (_options *ListVaultsOptions) SetSortBy(sort *VaultsSort) (*ListVaultsOptions, error) {
+	fields, err := sort.Build()
+	if err != nil {
+		return _options, err
+	}
+	_options.Sort = fields
+	return _options, nil
+}
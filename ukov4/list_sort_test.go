@@ -0,0 +1,101 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4_test
+
+import (
+	"github.com/IBM/ibm-hpcs-uko-sdk/ukov4"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`Sort builders`, func() {
+	It(`Render the API's sort syntax`, func() {
+		sort, err := ukov4.NewManagedKeysSort().
+			Desc(ukov4.ListManagedKeysOptions_Sort_UpdatedAt).
+			Asc(ukov4.ListManagedKeysOptions_Sort_Label).
+			Build()
+		Expect(err).To(BeNil())
+		Expect(sort).To(Equal([]string{"-updated_at", "label"}))
+
+		sort, err = ukov4.NewVaultsSort().Desc(ukov4.ListVaultsOptions_Sort_CreatedAt).Build()
+		Expect(err).To(BeNil())
+		Expect(sort).To(Equal([]string{"-created_at"}))
+
+		builder, err := ukov4.ParseKeyTemplatesSort([]string{"-created_at", "name"})
+		Expect(err).To(BeNil())
+		Expect(builder.Build()).To(Equal([]string{"-created_at", "name"}))
+	})
+	It(`Set the sort parameter of list options`, func() {
+		options, err := new(ukov4.ListKeystoresOptions).
+			SetSortBy(ukov4.NewKeystoresSort().Asc(ukov4.ListKeystoresOptions_Sort_VaultID).Desc(ukov4.ListKeystoresOptions_Sort_Name))
+		Expect(err).To(BeNil())
+		Expect(options.Sort).To(Equal([]string{"vault.id", "-name"}))
+
+		keyTemplatesOptions, err := new(ukov4.ListKeyTemplatesOptions).
+			SetSortBy(ukov4.NewKeyTemplatesSort().Desc(ukov4.ListKeyTemplatesOptions_Sort_UpdatedAt))
+		Expect(err).To(BeNil())
+		Expect(keyTemplatesOptions.Sort).To(Equal([]string{"-updated_at"}))
+
+		vaultsOptions := new(ukov4.ListVaultsOptions).SetSort([]string{"name"})
+		_, err = vaultsOptions.SetSortBy(ukov4.NewVaultsSort().Asc("nmae"))
+		Expect(err).To(BeAssignableToTypeOf(&ukov4.SortError{}))
+		Expect(vaultsOptions.Sort).To(Equal([]string{"name"}))
+
+		managedKeysOptions, err := new(ukov4.ListManagedKeysOptions).
+			SetSortBy(ukov4.NewManagedKeysSort().Asc(ukov4.ListManagedKeysOptions_Sort_DeactivationDate))
+		Expect(err).To(BeNil())
+		Expect(managedKeysOptions.Sort).To(Equal([]string{"deactivation_date"}))
+	})
+	It(`Accept the sort fields documented by the API`, func() {
+		for _, field := range []string{"label", "algorithm", "state", "activation_date", "deactivation_date", "created_at",
+			"updated_at", "size", "vault.id"} {
+			_, err := ukov4.ParseManagedKeysSort([]string{field, "-" + field})
+			Expect(err).To(MatchError(ContainSubstring("more than once")), field)
+			_, err = ukov4.ParseManagedKeysSort([]string{"-" + field})
+			Expect(err).To(BeNil(), field)
+		}
+		for _, field := range []string{"name", "created_at", "updated_at", "vault.id"} {
+			_, err := ukov4.ParseKeystoresSort([]string{"-" + field})
+			Expect(err).To(BeNil(), field)
+			_, err = ukov4.ParseKeyTemplatesSort([]string{field})
+			Expect(err).To(BeNil(), field)
+		}
+		for _, field := range []string{"name", "created_at", "updated_at"} {
+			_, err := ukov4.ParseVaultsSort([]string{field})
+			Expect(err).To(BeNil(), field)
+		}
+	})
+	It(`Reject invalid sort specifications (negative test)`, func() {
+		_, err := ukov4.NewKeystoresSort().Asc("nmae").Build()
+		Expect(err).To(BeAssignableToTypeOf(&ukov4.SortError{}))
+		Expect(err.(*ukov4.SortError).Field).To(Equal("nmae"))
+
+		_, err = ukov4.NewKeystoresSort().Asc(ukov4.ListKeystoresOptions_Sort_Name).Desc(ukov4.ListKeystoresOptions_Sort_Name).Build()
+		Expect(err).To(MatchError(ContainSubstring("more than once")))
+
+		for _, field := range []string{"-keys_count", "expiration_date", "rotated_at"} {
+			_, err = ukov4.ParseManagedKeysSort([]string{field})
+			Expect(err).To(BeAssignableToTypeOf(&ukov4.SortError{}), field)
+		}
+		for _, field := range []string{"description", "location", "type"} {
+			_, err = ukov4.ParseKeystoresSort([]string{field})
+			Expect(err).To(BeAssignableToTypeOf(&ukov4.SortError{}), field)
+		}
+		_, err = ukov4.ParseVaultsSort([]string{"vault.id"})
+		Expect(err).ToNot(BeNil())
+	})
+})
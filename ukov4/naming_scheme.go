@@ -0,0 +1,327 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+)
+
+// Names of the naming scheme tags computed by the service rather than supplied by the caller. Tag names are matched
+// case-insensitively against these values.
+const (
+	NamingSchemeTag_Algorithm    = "algorithm"
+	NamingSchemeTag_KeystoreType = "keystore_type"
+	NamingSchemeTag_Size         = "size"
+)
+
+// ComputedNamingSchemeTags is the set of naming scheme tags computed by the service. Values for these tags are taken
+// from the key properties and the target keystore when a naming scheme is rendered.
+var ComputedNamingSchemeTags = map[string]bool{
+	NamingSchemeTag_Algorithm:    true,
+	NamingSchemeTag_KeystoreType: true,
+	NamingSchemeTag_Size:         true,
+}
+
+// IsComputedNamingSchemeTag returns true if the value of the specified tag is computed by the service.
+func IsComputedNamingSchemeTag(tag string) bool {
+	return ComputedNamingSchemeTags[strings.ToLower(tag)]
+}
+
+// NamingScheme : A parsed managed key naming scheme, such as "<app>-<env>-<algorithm>".
+type NamingScheme struct {
+	// The naming scheme as written.
+	Scheme string
+
+	segments []namingSchemeSegment
+}
+
+// namingSchemeSegment is either literal text or a tag of a naming scheme.
+type namingSchemeSegment struct {
+	text  string
+	isTag bool
+}
+
+// NamingSchemeError : The error returned for a malformed naming scheme.
+type NamingSchemeError struct {
+	// The naming scheme as written.
+	Scheme string
+
+	// The byte offset of the problem within the naming scheme.
+	Position int
+
+	// A description of the problem.
+	Message string
+}
+
+// Error implements the error interface.
+func (e *NamingSchemeError) Error() string {
+	return fmt.Sprintf("invalid naming scheme '%s' at position %d: %s", e.Scheme, e.Position, e.Message)
+}
+
+// MissingTagsError : The error returned when a naming scheme cannot be rendered because tag values are missing.
+type MissingTagsError struct {
+	// The names of the tags without a value.
+	Tags []string
+}
+
+// Error implements the error interface.
+func (e *MissingTagsError) Error() string {
+	return fmt.Sprintf("missing values for naming scheme tags: %s", strings.Join(e.Tags, ", "))
+}
+
+// ParseNamingScheme parses and validates a naming scheme. Every tag must be enclosed in angle brackets, must not be
+// empty and may only contain letters, digits, '_', '-', '.' and spaces; brackets cannot be nested.
+func ParseNamingScheme(scheme string) (namingScheme *NamingScheme, err error) {
+	namingScheme = &NamingScheme{Scheme: scheme}
+	start := -1
+	literal := 0
+	for i, r := range scheme {
+		switch {
+		case r == '<':
+			if start >= 0 {
+				return nil, &NamingSchemeError{scheme, i, "nested '<'"}
+			}
+			if i > literal {
+				namingScheme.segments = append(namingScheme.segments, namingSchemeSegment{text: scheme[literal:i]})
+			}
+			start = i
+		case r == '>':
+			if start < 0 {
+				return nil, &NamingSchemeError{scheme, i, "'>' without matching '<'"}
+			}
+			tag := scheme[start+1 : i]
+			if strings.TrimSpace(tag) == "" {
+				return nil, &NamingSchemeError{scheme, start, "empty tag"}
+			}
+			namingScheme.segments = append(namingScheme.segments, namingSchemeSegment{text: tag, isTag: true})
+			start = -1
+			literal = i + 1
+		case start >= 0 && !isNamingSchemeTagRune(r):
+			return nil, &NamingSchemeError{scheme, i, fmt.Sprintf("invalid character '%c' in tag", r)}
+		}
+	}
+	if start >= 0 {
+		return nil, &NamingSchemeError{scheme, start, "'<' without matching '>'"}
+	}
+	if literal < len(scheme) {
+		namingScheme.segments = append(namingScheme.segments, namingSchemeSegment{text: scheme[literal:]})
+	}
+	return
+}
+
+func isNamingSchemeTagRune(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' ||
+		r == '_' || r == '-' || r == '.' || r == ' '
+}
+
+// Tags returns the distinct tags of the naming scheme, in order of first appearance.
+func (namingScheme *NamingScheme) Tags() (tags []string) {
+	seen := map[string]bool{}
+	for _, segment := range namingScheme.segments {
+		if segment.isTag && !seen[segment.text] {
+			seen[segment.text] = true
+			tags = append(tags, segment.text)
+		}
+	}
+	return
+}
+
+// ComputedTags returns the distinct tags of the naming scheme whose values are computed by the service.
+func (namingScheme *NamingScheme) ComputedTags() (tags []string) {
+	for _, tag := range namingScheme.Tags() {
+		if IsComputedNamingSchemeTag(tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return
+}
+
+// UserTags returns the distinct tags of the naming scheme whose values must be supplied by the caller as label tags.
+func (namingScheme *NamingScheme) UserTags() (tags []string) {
+	for _, tag := range namingScheme.Tags() {
+		if !IsComputedNamingSchemeTag(tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return
+}
+
+// MissingTags returns the user tags of the naming scheme that have no value in the specified label tags.
+func (namingScheme *NamingScheme) MissingTags(labelTags []Tag) (missing []string) {
+	values := tagValues(labelTags)
+	for _, tag := range namingScheme.UserTags() {
+		if _, ok := values[tag]; !ok {
+			missing = append(missing, tag)
+		}
+	}
+	return
+}
+
+// Render substitutes the tags of the naming scheme with the specified values. Computed tags are looked up
+// case-insensitively; all other tags must match a value exactly. A *MissingTagsError lists every tag without a value.
+func (namingScheme *NamingScheme) Render(values map[string]string) (label string, err error) {
+	var builder strings.Builder
+	var missing []string
+	for _, segment := range namingScheme.segments {
+		if !segment.isTag {
+			builder.WriteString(segment.text)
+			continue
+		}
+		value, ok := values[segment.text]
+		if !ok && IsComputedNamingSchemeTag(segment.text) {
+			value, ok = values[strings.ToLower(segment.text)]
+		}
+		if !ok {
+			if !core.SliceContains(missing, segment.text) {
+				missing = append(missing, segment.text)
+			}
+			continue
+		}
+		builder.WriteString(value)
+	}
+	if len(missing) > 0 {
+		return "", &MissingTagsError{Tags: missing}
+	}
+	return builder.String(), nil
+}
+
+// tagValues returns the label tags as a map from tag name to value.
+func tagValues(labelTags []Tag) map[string]string {
+	values := make(map[string]string, len(labelTags))
+	for _, tag := range labelTags {
+		if tag.Name != nil && tag.Value != nil {
+			values[*tag.Name] = *tag.Value
+		}
+	}
+	return values
+}
+
+// RenderedKeyLabels : The labels a managed key would get from a template.
+type RenderedKeyLabels struct {
+	// The label of the managed key.
+	Label *string `json:"label"`
+
+	// The label of the key in each keystore group of the template, in template order.
+	Keystores []RenderedKeystoreLabel `json:"keystores"`
+}
+
+// RenderedKeystoreLabel : The label a managed key would get in a keystore group.
+type RenderedKeystoreLabel struct {
+	// Which keystore group the key is distributed to.
+	Group *string `json:"group,omitempty"`
+
+	// Type of keystore.
+	Type *string `json:"type,omitempty"`
+
+	// The naming scheme used for the label.
+	NamingScheme *string `json:"naming_scheme,omitempty"`
+
+	// The label of the key in the keystore.
+	Label *string `json:"label_in_keystore"`
+}
+
+// RenderKeyLabels computes the label of a managed key created from a template with the specified naming scheme, key
+// properties and keystores, and the label of its instance in each keystore group. A keystore without a naming scheme of
+// its own uses the template's naming scheme. A malformed scheme returns a *NamingSchemeError; tags without a value in
+// any of the schemes are reported together in a single *MissingTagsError.
+func RenderKeyLabels(namingScheme string, key *KeyProperties, keystores []KeystoresPropertiesCreateIntf, labelTags []Tag) (result *RenderedKeyLabels, err error) {
+	values := tagValues(labelTags)
+	if key != nil {
+		if key.Algorithm != nil {
+			values[NamingSchemeTag_Algorithm] = *key.Algorithm
+		}
+		if key.Size != nil {
+			values[NamingSchemeTag_Size] = *key.Size
+		}
+	}
+
+	templateScheme, err := ParseNamingScheme(namingScheme)
+	if err != nil {
+		return
+	}
+	var missing []string
+	addMissing := func(err error) error {
+		if missingErr, ok := err.(*MissingTagsError); ok {
+			for _, tag := range missingErr.Tags {
+				if !core.SliceContains(missing, tag) {
+					missing = append(missing, tag)
+				}
+			}
+			return nil
+		}
+		return err
+	}
+
+	result = &RenderedKeyLabels{}
+	label, err := templateScheme.Render(values)
+	if err = addMissing(err); err != nil {
+		return nil, err
+	}
+	result.Label = core.StringPtr(label)
+
+	for _, keystore := range keystores {
+		var properties *KeystoresPropertiesCreate
+		properties, err = toKeystoresPropertiesCreate(keystore)
+		if err != nil {
+			return nil, err
+		}
+		scheme := templateScheme
+		if properties.NamingScheme != nil && *properties.NamingScheme != "" {
+			scheme, err = ParseNamingScheme(*properties.NamingScheme)
+			if err != nil {
+				return nil, err
+			}
+		}
+		keystoreValues := values
+		if properties.Type != nil {
+			keystoreValues = make(map[string]string, len(values)+1)
+			for name, value := range values {
+				keystoreValues[name] = value
+			}
+			keystoreValues[NamingSchemeTag_KeystoreType] = *properties.Type
+		}
+		label, err = scheme.Render(keystoreValues)
+		if err = addMissing(err); err != nil {
+			return nil, err
+		}
+		result.Keystores = append(result.Keystores, RenderedKeystoreLabel{
+			Group:        properties.Group,
+			Type:         properties.Type,
+			NamingScheme: core.StringPtr(scheme.Scheme),
+			Label:        core.StringPtr(label),
+		})
+	}
+	if len(missing) > 0 {
+		return nil, &MissingTagsError{Tags: missing}
+	}
+	return
+}
+
+// RenderKeyLabels computes the labels a managed key created from the template with the specified label tags would
+// get. See the RenderKeyLabels function.
+func (template *Template) RenderKeyLabels(labelTags []Tag) (*RenderedKeyLabels, error) {
+	return RenderKeyLabels(core.StringNilMapper(template.NamingScheme), template.Key, template.Keystores, labelTags)
+}
+
+// RenderKeyLabels computes the labels a managed key created from a template with these options and the specified label
+// tags would get. See the RenderKeyLabels function.
+func (_options *CreateKeyTemplateOptions) RenderKeyLabels(labelTags []Tag) (*RenderedKeyLabels, error) {
+	return RenderKeyLabels(core.StringNilMapper(_options.NamingScheme), _options.Key, _options.Keystores, labelTags)
+}
@@ -0,0 +1,75 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4_test
+
+import (
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-hpcs-uko-sdk/ukov4"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`NamingScheme`, func() {
+	tags := func(nameValues ...string) (labelTags []ukov4.Tag) {
+		for i := 0; i < len(nameValues); i += 2 {
+			labelTags = append(labelTags, ukov4.Tag{Name: core.StringPtr(nameValues[i]), Value: core.StringPtr(nameValues[i+1])})
+		}
+		return
+	}
+
+	It(`Parse a naming scheme and classify its tags`, func() {
+		scheme, err := ukov4.ParseNamingScheme("<app>-<env>-<Algorithm>-<app>")
+		Expect(err).To(BeNil())
+		Expect(scheme.Tags()).To(Equal([]string{"app", "env", "Algorithm"}))
+		Expect(scheme.ComputedTags()).To(Equal([]string{"Algorithm"}))
+		Expect(scheme.UserTags()).To(Equal([]string{"app", "env"}))
+		Expect(scheme.MissingTags(tags("app", "billing"))).To(Equal([]string{"env"}))
+	})
+	It(`Reject malformed naming schemes (negative test)`, func() {
+		for _, scheme := range []string{"<app", "app>", "<>", "<a<b>>", "<a/b>"} {
+			_, err := ukov4.ParseNamingScheme(scheme)
+			Expect(err).To(BeAssignableToTypeOf(&ukov4.NamingSchemeError{}), scheme)
+		}
+	})
+	It(`Render key and keystore labels`, func() {
+		options := &ukov4.CreateKeyTemplateOptions{
+			NamingScheme: core.StringPtr("<app>-<algorithm>-<size>"),
+			Key:          &ukov4.KeyProperties{Algorithm: core.StringPtr("aes"), Size: core.StringPtr("256")},
+			Keystores: []ukov4.KeystoresPropertiesCreateIntf{
+				&ukov4.KeystoresPropertiesCreate{Group: core.StringPtr("g1"), Type: core.StringPtr("aws_kms")},
+				&ukov4.KeystoresPropertiesCreateAwsKms{Group: core.StringPtr("g2"), Type: core.StringPtr("aws_kms"), NamingScheme: core.StringPtr("<app>_<keystore_type>")},
+			},
+		}
+		labels, err := options.RenderKeyLabels(tags("app", "billing"))
+		Expect(err).To(BeNil())
+		Expect(*labels.Label).To(Equal("billing-aes-256"))
+		Expect(labels.Keystores).To(HaveLen(2))
+		Expect(*labels.Keystores[0].Label).To(Equal("billing-aes-256"))
+		Expect(*labels.Keystores[1].Group).To(Equal("g2"))
+		Expect(*labels.Keystores[1].Label).To(Equal("billing_aws_kms"))
+	})
+	It(`Report every missing tag at once (negative test)`, func() {
+		template := &ukov4.Template{
+			NamingScheme: core.StringPtr("<app>-<env>"),
+			Keystores: []ukov4.KeystoresPropertiesCreateIntf{
+				&ukov4.KeystoresPropertiesCreate{NamingScheme: core.StringPtr("<region>")},
+			},
+		}
+		_, err := template.RenderKeyLabels(nil)
+		Expect(err).To(Equal(&ukov4.MissingTagsError{Tags: []string{"app", "env", "region"}}))
+	})
+})
@@ -0,0 +1,407 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+)
+
+// TemplateCompatibilityReport : The result of checking that the key properties of a template fit its keystores.
+type TemplateCompatibilityReport struct {
+	// The problems found, in keystore order.
+	Findings []TemplateCompatibilityFinding `json:"findings"`
+}
+
+// HasErrors returns true if any finding would make the service reject the template.
+func (report *TemplateCompatibilityReport) HasErrors() bool {
+	for _, finding := range report.Findings {
+		if core.StringNilMapper(finding.Severity) == TemplateCompatibilityFinding_Severity_Error {
+			return true
+		}
+	}
+	return false
+}
+
+// Errors returns the findings that would make the service reject the template.
+func (report *TemplateCompatibilityReport) Errors() (findings []TemplateCompatibilityFinding) {
+	for _, finding := range report.Findings {
+		if core.StringNilMapper(finding.Severity) == TemplateCompatibilityFinding_Severity_Error {
+			findings = append(findings, finding)
+		}
+	}
+	return
+}
+
+// TemplateCompatibilityFinding : A mismatch between the key properties of a template and one of its keystores.
+type TemplateCompatibilityFinding struct {
+	// The index of the keystore entry in the template, or -1 for findings not tied to an entry of the template.
+	KeystoreIndex *int64 `json:"keystore_index"`
+
+	// Which keystore group the entry distributes the key to.
+	Group *string `json:"group,omitempty"`
+
+	// Type of keystore.
+	Type *string `json:"type,omitempty"`
+
+	// The JSON name of the offending property.
+	Field *string `json:"field"`
+
+	// How serious the finding is.
+	Severity *string `json:"severity"`
+
+	// A description of the problem.
+	Message *string `json:"message"`
+}
+
+// Constants associated with the TemplateCompatibilityFinding.Severity property.
+// How serious the finding is.
+const (
+	TemplateCompatibilityFinding_Severity_Error   = "error"
+	TemplateCompatibilityFinding_Severity_Warning = "warning"
+)
+
+// ValidateCompatibility checks that the key properties of the template to be created fit every keystore entry. See
+// ValidateTemplateCompatibility.
+func (_options *CreateKeyTemplateOptions) ValidateCompatibility() (*TemplateCompatibilityReport, error) {
+	return ValidateTemplateCompatibility(_options.Key, _options.Keystores)
+}
+
+// ValidateTemplateCompatibility checks that the key algorithm and size fit every keystore entry of a template: that
+// the keystore type supports them, and that the keystore-specific properties, such as the Google KMS algorithm and
+// purpose, the Azure protection level and key operations, and the CCA key type and usage control, agree with them.
+// Properties that only apply to other keystore types are reported as warnings, since the service ignores them.
+func ValidateTemplateCompatibility(key *KeyProperties, keystores []KeystoresPropertiesCreateIntf) (report *TemplateCompatibilityReport, err error) {
+	err = core.ValidateNotNil(key, "key cannot be nil")
+	if err != nil {
+		return
+	}
+	report = &TemplateCompatibilityReport{Findings: []TemplateCompatibilityFinding{}}
+	for i, keystore := range keystores {
+		var properties *KeystoresPropertiesCreate
+		properties, err = toKeystoresPropertiesCreate(keystore)
+		if err != nil {
+			return nil, err
+		}
+		checker := &compatibilityChecker{report: report, index: i, keystore: properties,
+			algorithm: core.StringNilMapper(key.Algorithm), size: core.StringNilMapper(key.Size)}
+		checker.check()
+	}
+	return
+}
+
+// ValidateTemplateUpdateCompatibility checks that a template still fits its keystores once the update is applied. The
+// update does not carry the key algorithm nor the keystore types, so they are taken from the current template;
+// keystore entries of the update are matched with those of the current template by group.
+func ValidateTemplateUpdateCompatibility(current *Template, update *UpdateKeyTemplateOptions) (report *TemplateCompatibilityReport, err error) {
+	err = core.ValidateNotNil(current, "current cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateNotNil(update, "update cannot be nil")
+	if err != nil {
+		return
+	}
+
+	key := KeyProperties{}
+	if current.Key != nil {
+		key = *current.Key
+	}
+	if update.Key != nil && update.Key.Size != nil {
+		key.Size = update.Key.Size
+	}
+
+	keystores := make([]KeystoresPropertiesCreateIntf, len(current.Keystores))
+	byGroup := map[string]*KeystoresPropertiesCreate{}
+	for i, keystore := range current.Keystores {
+		var properties *KeystoresPropertiesCreate
+		properties, err = toKeystoresPropertiesCreate(keystore)
+		if err != nil {
+			return
+		}
+		merged := *properties
+		keystores[i] = &merged
+		byGroup[core.StringNilMapper(merged.Group)] = &merged
+	}
+	var unmatched []TemplateCompatibilityFinding
+	for _, keystore := range update.Keystores {
+		var properties *KeystoresPropertiesUpdate
+		properties, err = toKeystoresPropertiesUpdate(keystore)
+		if err != nil {
+			return
+		}
+		merged, ok := byGroup[core.StringNilMapper(properties.Group)]
+		if !ok {
+			unmatched = append(unmatched, TemplateCompatibilityFinding{
+				KeystoreIndex: core.Int64Ptr(-1),
+				Group:         properties.Group,
+				Field:         core.StringPtr("group"),
+				Severity:      core.StringPtr(TemplateCompatibilityFinding_Severity_Error),
+				Message: core.StringPtr(fmt.Sprintf("the template has no keystore group '%s'",
+					core.StringNilMapper(properties.Group))),
+			})
+			continue
+		}
+		mergeKeystoresPropertiesUpdate(merged, properties)
+	}
+
+	report, err = ValidateTemplateCompatibility(&key, keystores)
+	if err != nil {
+		return
+	}
+	report.Findings = append(report.Findings, unmatched...)
+	return
+}
+
+func mergeKeystoresPropertiesUpdate(target *KeystoresPropertiesCreate, update *KeystoresPropertiesUpdate) {
+	if update.GoogleKeyProtectionLevel != nil {
+		target.GoogleKeyProtectionLevel = update.GoogleKeyProtectionLevel
+	}
+	if update.GoogleKeyPurpose != nil {
+		target.GoogleKeyPurpose = update.GoogleKeyPurpose
+	}
+	if update.GoogleKmsAlgorithm != nil {
+		target.GoogleKmsAlgorithm = update.GoogleKmsAlgorithm
+	}
+	if update.CcaUsageControl != nil {
+		target.CcaUsageControl = update.CcaUsageControl
+	}
+	if update.CcaKeyType != nil {
+		target.CcaKeyType = update.CcaKeyType
+	}
+	if update.CcaKeyWords != nil {
+		target.CcaKeyWords = update.CcaKeyWords
+	}
+}
+
+// keystoreKeySizes lists, for each keystore type, the key algorithms it supports and their sizes; nil sizes mean the
+// size is not restricted.
+var keystoreKeySizes = map[string]map[string][]string{
+	KeystoresPropertiesCreate_Type_AwsKms: {
+		KeyProperties_Algorithm_Aes: {"256"},
+	},
+	KeystoresPropertiesCreate_Type_AzureKeyVault: {
+		KeyProperties_Algorithm_Aes: {"256"},
+		KeyProperties_Algorithm_Ec:  {"256", "384", "521"},
+		KeyProperties_Algorithm_Rsa: {"2048", "3072", "4096"},
+	},
+	KeystoresPropertiesCreate_Type_GoogleKms: {
+		KeyProperties_Algorithm_Aes:  {"256"},
+		KeyProperties_Algorithm_Ec:   {"256", "384"},
+		KeyProperties_Algorithm_Hmac: {"256"},
+		KeyProperties_Algorithm_Rsa:  {"2048", "3072", "4096"},
+	},
+	KeystoresPropertiesCreate_Type_IbmCloudKms: {
+		KeyProperties_Algorithm_Aes: {"128", "192", "256"},
+	},
+	KeystoresPropertiesCreate_Type_Cca: {
+		KeyProperties_Algorithm_Aes:       {"128", "192", "256"},
+		KeyProperties_Algorithm_Des:       {"128", "192"},
+		KeyProperties_Algorithm_Dilithium: nil,
+		KeyProperties_Algorithm_Ec:        {"256", "384", "521"},
+		KeyProperties_Algorithm_Rsa:       {"1024", "2048", "3072", "4096"},
+	},
+}
+
+// compatibilityChecker checks a single keystore entry of a template.
+type compatibilityChecker struct {
+	report    *TemplateCompatibilityReport
+	index     int
+	keystore  *KeystoresPropertiesCreate
+	algorithm string
+	size      string
+}
+
+func (checker *compatibilityChecker) add(severity string, field string, format string, args ...interface{}) {
+	checker.report.Findings = append(checker.report.Findings, TemplateCompatibilityFinding{
+		KeystoreIndex: core.Int64Ptr(int64(checker.index)),
+		Group:         checker.keystore.Group,
+		Type:          checker.keystore.Type,
+		Field:         core.StringPtr(field),
+		Severity:      core.StringPtr(severity),
+		Message:       core.StringPtr(fmt.Sprintf(format, args...)),
+	})
+}
+
+func (checker *compatibilityChecker) check() {
+	keystoreType := core.StringNilMapper(checker.keystore.Type)
+	algorithms, ok := keystoreKeySizes[keystoreType]
+	if !ok {
+		checker.add(TemplateCompatibilityFinding_Severity_Error, "type", "unknown keystore type '%s'", keystoreType)
+		return
+	}
+	sizes, ok := algorithms[checker.algorithm]
+	if !ok {
+		checker.add(TemplateCompatibilityFinding_Severity_Error, "algorithm",
+			"%s keystores do not support %s keys", keystoreType, checker.algorithm)
+	} else if sizes != nil && !core.SliceContains(sizes, checker.size) {
+		checker.add(TemplateCompatibilityFinding_Severity_Error, "size",
+			"%s keystores do not support %s keys of size %s; supported sizes are %s",
+			keystoreType, checker.algorithm, checker.size, strings.Join(sizes, ", "))
+	}
+
+	switch keystoreType {
+	case KeystoresPropertiesCreate_Type_GoogleKms:
+		checker.checkGoogle()
+	case KeystoresPropertiesCreate_Type_AzureKeyVault:
+		checker.checkAzure()
+	case KeystoresPropertiesCreate_Type_Cca:
+		checker.checkCca()
+	}
+	checker.checkIgnored(keystoreType)
+}
+
+// googleKmsAlgorithmSize matches the RSA modulus size in a Google KMS algorithm name.
+var googleKmsAlgorithmSize = regexp.MustCompile(`_(2048|3072|4096)(_|$)`)
+
+// googleKmsAlgorithmRequirements returns the key algorithm, size and purpose a Google KMS algorithm requires.
+func googleKmsAlgorithmRequirements(googleKmsAlgorithm string) (algorithm string, size string, purpose string) {
+	switch {
+	case googleKmsAlgorithm == KeystoresPropertiesCreate_GoogleKmsAlgorithm_GoogleSymmetricEncryption:
+		return KeyProperties_Algorithm_Aes, "256", KeystoresPropertiesCreate_GoogleKeyPurpose_EncryptDecrypt
+	case googleKmsAlgorithm == KeystoresPropertiesCreate_GoogleKmsAlgorithm_HmacSha256:
+		return KeyProperties_Algorithm_Hmac, "256", KeystoresPropertiesCreate_GoogleKeyPurpose_Mac
+	case googleKmsAlgorithm == KeystoresPropertiesCreate_GoogleKmsAlgorithm_EcSignP384Sha384:
+		return KeyProperties_Algorithm_Ec, "384", KeystoresPropertiesCreate_GoogleKeyPurpose_AsymmetricSign
+	case strings.HasPrefix(googleKmsAlgorithm, "ec_sign_"):
+		return KeyProperties_Algorithm_Ec, "256", KeystoresPropertiesCreate_GoogleKeyPurpose_AsymmetricSign
+	case strings.HasPrefix(googleKmsAlgorithm, "rsa_decrypt_"), strings.HasPrefix(googleKmsAlgorithm, "rsa_sign_"):
+		if match := googleKmsAlgorithmSize.FindStringSubmatch(googleKmsAlgorithm); match != nil {
+			size = match[1]
+		}
+		purpose = KeystoresPropertiesCreate_GoogleKeyPurpose_AsymmetricSign
+		if strings.HasPrefix(googleKmsAlgorithm, "rsa_decrypt_") {
+			purpose = KeystoresPropertiesCreate_GoogleKeyPurpose_AsymmetricDecrypt
+		}
+		return KeyProperties_Algorithm_Rsa, size, purpose
+	}
+	return
+}
+
+func (checker *compatibilityChecker) checkGoogle() {
+	keystore := checker.keystore
+	if keystore.GoogleKmsAlgorithm == nil {
+		return
+	}
+	googleKmsAlgorithm := *keystore.GoogleKmsAlgorithm
+	algorithm, size, purpose := googleKmsAlgorithmRequirements(googleKmsAlgorithm)
+	switch {
+	case algorithm == "":
+		checker.add(TemplateCompatibilityFinding_Severity_Error, "google_kms_algorithm",
+			"unknown Google KMS algorithm '%s'", googleKmsAlgorithm)
+		return
+	case algorithm != checker.algorithm:
+		checker.add(TemplateCompatibilityFinding_Severity_Error, "google_kms_algorithm",
+			"Google KMS algorithm '%s' requires %s keys, not %s", googleKmsAlgorithm, algorithm, checker.algorithm)
+	case size != checker.size:
+		checker.add(TemplateCompatibilityFinding_Severity_Error, "google_kms_algorithm",
+			"Google KMS algorithm '%s' requires keys of size %s, not %s", googleKmsAlgorithm, size, checker.size)
+	}
+	if keystore.GoogleKeyPurpose != nil && *keystore.GoogleKeyPurpose != purpose {
+		checker.add(TemplateCompatibilityFinding_Severity_Error, "google_key_purpose",
+			"Google KMS algorithm '%s' requires key purpose '%s', not '%s'", googleKmsAlgorithm, purpose, *keystore.GoogleKeyPurpose)
+	}
+	if googleKmsAlgorithm == KeystoresPropertiesCreate_GoogleKmsAlgorithm_EcSignSecp256k1Sha256 &&
+		core.StringNilMapper(keystore.GoogleKeyProtectionLevel) != KeystoresPropertiesCreate_GoogleKeyProtectionLevel_Hsm {
+		checker.add(TemplateCompatibilityFinding_Severity_Error, "google_key_protection_level",
+			"Google KMS algorithm '%s' is only available with the hsm protection level", googleKmsAlgorithm)
+	}
+}
+
+// azureKeyOperations lists the Azure key operations each key algorithm supports.
+var azureKeyOperations = map[string][]string{
+	KeyProperties_Algorithm_Aes: {
+		KeystoresPropertiesCreate_AzureKeyOperations_Decrypt, KeystoresPropertiesCreate_AzureKeyOperations_Encrypt,
+		KeystoresPropertiesCreate_AzureKeyOperations_UnwrapKey, KeystoresPropertiesCreate_AzureKeyOperations_WrapKey,
+	},
+	KeyProperties_Algorithm_Ec: {
+		KeystoresPropertiesCreate_AzureKeyOperations_Sign, KeystoresPropertiesCreate_AzureKeyOperations_Verify,
+	},
+	KeyProperties_Algorithm_Rsa: {
+		KeystoresPropertiesCreate_AzureKeyOperations_Decrypt, KeystoresPropertiesCreate_AzureKeyOperations_Encrypt,
+		KeystoresPropertiesCreate_AzureKeyOperations_Sign, KeystoresPropertiesCreate_AzureKeyOperations_UnwrapKey,
+		KeystoresPropertiesCreate_AzureKeyOperations_Verify, KeystoresPropertiesCreate_AzureKeyOperations_WrapKey,
+	},
+}
+
+func (checker *compatibilityChecker) checkAzure() {
+	keystore := checker.keystore
+	if checker.algorithm == KeyProperties_Algorithm_Aes &&
+		core.StringNilMapper(keystore.AzureKeyProtectionLevel) == KeystoresPropertiesCreate_AzureKeyProtectionLevel_Software {
+		checker.add(TemplateCompatibilityFinding_Severity_Error, "azure_key_protection_level",
+			"Azure Key Vault only supports aes keys with the hsm protection level")
+	}
+	supported, ok := azureKeyOperations[checker.algorithm]
+	if !ok {
+		return
+	}
+	for _, operation := range keystore.AzureKeyOperations {
+		if !core.SliceContains(supported, operation) {
+			checker.add(TemplateCompatibilityFinding_Severity_Error, "azure_key_operations",
+				"Azure Key Vault does not support the '%s' operation for %s keys", operation, checker.algorithm)
+		}
+	}
+}
+
+func (checker *compatibilityChecker) checkCca() {
+	keystore := checker.keystore
+	switch checker.algorithm {
+	case KeyProperties_Algorithm_Aes, KeyProperties_Algorithm_Des:
+		if keystore.CcaUsageControl != nil {
+			checker.add(TemplateCompatibilityFinding_Severity_Error, "cca_usage_control",
+				"CCA usage control only applies to asymmetric keys, not %s keys", checker.algorithm)
+		}
+		if core.StringNilMapper(keystore.CcaKeyType) == KeystoresPropertiesCreate_CcaKeyType_Cipher &&
+			checker.algorithm == KeyProperties_Algorithm_Des {
+			checker.add(TemplateCompatibilityFinding_Severity_Error, "cca_key_type",
+				"CCA key type 'cipher' is only available for aes keys")
+		}
+	default:
+		if keystore.CcaKeyType != nil {
+			checker.add(TemplateCompatibilityFinding_Severity_Error, "cca_key_type",
+				"CCA key type only applies to symmetric keys, not %s keys", checker.algorithm)
+		}
+	}
+}
+
+// checkIgnored reports the keystore-specific properties that do not apply to the keystore type.
+func (checker *compatibilityChecker) checkIgnored(keystoreType string) {
+	keystore := checker.keystore
+	properties := []struct {
+		keystoreType string
+		field        string
+		set          bool
+	}{
+		{KeystoresPropertiesCreate_Type_GoogleKms, "google_key_protection_level", keystore.GoogleKeyProtectionLevel != nil},
+		{KeystoresPropertiesCreate_Type_GoogleKms, "google_key_purpose", keystore.GoogleKeyPurpose != nil},
+		{KeystoresPropertiesCreate_Type_GoogleKms, "google_kms_algorithm", keystore.GoogleKmsAlgorithm != nil},
+		{KeystoresPropertiesCreate_Type_AzureKeyVault, "azure_key_protection_level", keystore.AzureKeyProtectionLevel != nil},
+		{KeystoresPropertiesCreate_Type_AzureKeyVault, "azure_key_operations", keystore.AzureKeyOperations != nil},
+		{KeystoresPropertiesCreate_Type_Cca, "cca_usage_control", keystore.CcaUsageControl != nil},
+		{KeystoresPropertiesCreate_Type_Cca, "cca_key_type", keystore.CcaKeyType != nil},
+		{KeystoresPropertiesCreate_Type_Cca, "cca_key_words", keystore.CcaKeyWords != nil},
+	}
+	for _, property := range properties {
+		if property.set && property.keystoreType != keystoreType {
+			checker.add(TemplateCompatibilityFinding_Severity_Warning, property.field,
+				"'%s' only applies to %s keystores and is ignored", property.field, property.keystoreType)
+		}
+	}
+}
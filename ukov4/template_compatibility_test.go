@@ -0,0 +1,96 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4_test
+
+import (
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-hpcs-uko-sdk/ukov4"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`ValidateTemplateCompatibility`, func() {
+	fields := func(report *ukov4.TemplateCompatibilityReport) (result []string) {
+		for _, finding := range report.Findings {
+			result = append(result, *finding.Severity+":"+*finding.Field)
+		}
+		return
+	}
+
+	It(`Accept compatible templates`, func() {
+		options := &ukov4.CreateKeyTemplateOptions{
+			Key: &ukov4.KeyProperties{Algorithm: core.StringPtr("rsa"), Size: core.StringPtr("3072")},
+			Keystores: []ukov4.KeystoresPropertiesCreateIntf{
+				&ukov4.KeystoresPropertiesCreateGoogleKms{
+					Group:              core.StringPtr("google"),
+					Type:               core.StringPtr("google_kms"),
+					GoogleKmsAlgorithm: core.StringPtr("rsa_sign_pss_3072_sha256"),
+					GoogleKeyPurpose:   core.StringPtr("asymmetric_sign"),
+				},
+				&ukov4.KeystoresPropertiesCreate{Group: core.StringPtr("azure"), Type: core.StringPtr("azure_key_vault"), AzureKeyOperations: []string{"sign", "verify"}},
+				&ukov4.KeystoresPropertiesCreate{Group: core.StringPtr("cca"), Type: core.StringPtr("cca"), CcaUsageControl: core.StringPtr("signature_only")},
+			},
+		}
+		report, err := options.ValidateCompatibility()
+		Expect(err).To(BeNil())
+		Expect(report.Findings).To(BeEmpty())
+		Expect(report.HasErrors()).To(BeFalse())
+	})
+	It(`Report incompatible keystores (negative test)`, func() {
+		report, err := ukov4.ValidateTemplateCompatibility(
+			&ukov4.KeyProperties{Algorithm: core.StringPtr("aes"), Size: core.StringPtr("128")},
+			[]ukov4.KeystoresPropertiesCreateIntf{
+				&ukov4.KeystoresPropertiesCreate{Type: core.StringPtr("azure_key_vault"), AzureKeyOperations: []string{"sign"}},
+				&ukov4.KeystoresPropertiesCreate{Type: core.StringPtr("google_kms"), GoogleKmsAlgorithm: core.StringPtr("rsa_decrypt_oaep_2048_sha256")},
+				&ukov4.KeystoresPropertiesCreate{Type: core.StringPtr("cca"), CcaUsageControl: core.StringPtr("signature_only"), CcaKeyType: core.StringPtr("cipher")},
+				&ukov4.KeystoresPropertiesCreate{Type: core.StringPtr("aws_kms"), CcaKeyType: core.StringPtr("data")},
+			})
+		Expect(err).To(BeNil())
+		Expect(fields(report)).To(Equal([]string{
+			"error:size", "error:azure_key_operations",
+			"error:size", "error:google_kms_algorithm",
+			"error:cca_usage_control",
+			"error:size", "warning:cca_key_type",
+		}))
+		Expect(*report.Findings[3].KeystoreIndex).To(Equal(int64(1)))
+		Expect(report.Errors()).To(HaveLen(6))
+	})
+	It(`Validate an update against the current template`, func() {
+		current := &ukov4.Template{
+			Key: &ukov4.KeyProperties{Algorithm: core.StringPtr("ec"), Size: core.StringPtr("256")},
+			Keystores: []ukov4.KeystoresPropertiesCreateIntf{
+				&ukov4.KeystoresPropertiesCreate{Group: core.StringPtr("g1"), Type: core.StringPtr("google_kms"), GoogleKmsAlgorithm: core.StringPtr("ec_sign_p256_sha256")},
+			},
+		}
+		update := &ukov4.UpdateKeyTemplateOptions{
+			Key: &ukov4.KeyPropertiesUpdate{Size: core.StringPtr("384")},
+			Keystores: []ukov4.KeystoresPropertiesUpdateIntf{
+				&ukov4.KeystoresPropertiesUpdate{Group: core.StringPtr("g2"), CcaKeyType: core.StringPtr("data")},
+			},
+		}
+		report, err := ukov4.ValidateTemplateUpdateCompatibility(current, update)
+		Expect(err).To(BeNil())
+		Expect(fields(report)).To(Equal([]string{"error:google_kms_algorithm", "error:group"}))
+
+		update.Keystores = []ukov4.KeystoresPropertiesUpdateIntf{
+			&ukov4.KeystoresPropertiesUpdate{Group: core.StringPtr("g1"), GoogleKmsAlgorithm: core.StringPtr("ec_sign_p384_sha384")},
+		}
+		report, err = ukov4.ValidateTemplateUpdateCompatibility(current, update)
+		Expect(err).To(BeNil())
+		Expect(report.Findings).To(BeEmpty())
+	})
+})
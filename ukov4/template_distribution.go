@@ -0,0 +1,219 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+)
+
+// PreviewTemplateDistribution : Preview the keystores a template distributes keys to
+// Resolves the group and type of each keystore entry of an existing or new template against the keystores of its
+// vault, and lists the key instance that a managed key created from the template would get in each of them, with its
+// label and the keystore-specific properties of the entry. Label tags that have no value in the options are rendered
+// as the tag itself, such as "<app>", and are listed in the preview. Entries that match no keystore are reported as
+// warnings. Nothing is modified.
+func (uko *UkoV4) PreviewTemplateDistribution(previewTemplateDistributionOptions *PreviewTemplateDistributionOptions) (result *TemplateDistributionPreview, err error) {
+	return uko.PreviewTemplateDistributionWithContext(context.Background(), previewTemplateDistributionOptions)
+}
+
+// PreviewTemplateDistributionWithContext is an alternate form of the PreviewTemplateDistribution method which supports a Context parameter
+func (uko *UkoV4) PreviewTemplateDistributionWithContext(ctx context.Context, previewTemplateDistributionOptions *PreviewTemplateDistributionOptions) (result *TemplateDistributionPreview, err error) {
+	err = core.ValidateNotNil(previewTemplateDistributionOptions, "previewTemplateDistributionOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	options := previewTemplateDistributionOptions
+
+	var vaultID, namingScheme string
+	var key *KeyProperties
+	var entries []KeystoresPropertiesCreateIntf
+	switch {
+	case options.Template != nil && options.CreateKeyTemplateOptions != nil:
+		err = fmt.Errorf("only one of Template and CreateKeyTemplateOptions can be set")
+		return
+	case options.Template != nil:
+		if options.Template.Vault != nil {
+			vaultID = core.StringNilMapper(options.Template.Vault.ID)
+		}
+		namingScheme = core.StringNilMapper(options.Template.NamingScheme)
+		key, entries = options.Template.Key, options.Template.Keystores
+	case options.CreateKeyTemplateOptions != nil:
+		if options.CreateKeyTemplateOptions.Vault != nil {
+			vaultID = core.StringNilMapper(options.CreateKeyTemplateOptions.Vault.ID)
+		}
+		namingScheme = core.StringNilMapper(options.CreateKeyTemplateOptions.NamingScheme)
+		key, entries = options.CreateKeyTemplateOptions.Key, options.CreateKeyTemplateOptions.Keystores
+	default:
+		err = fmt.Errorf("one of Template and CreateKeyTemplateOptions must be set")
+		return
+	}
+	if vaultID == "" {
+		err = fmt.Errorf("the template has no vault")
+		return
+	}
+
+	labels, missingTags, err := renderPreviewLabels(namingScheme, key, entries, options.LabelTags)
+	if err != nil {
+		return
+	}
+
+	pager, err := uko.NewKeystoresPager(&ListKeystoresOptions{VaultID: []string{vaultID}})
+	if err != nil {
+		return
+	}
+	items, err := pager.GetAllWithContext(ctx)
+	if err != nil {
+		return
+	}
+	keystores := make([]*Keystore, 0, len(items))
+	for _, item := range items {
+		keystore := new(Keystore)
+		if err = remarshal(item, keystore); err != nil {
+			return
+		}
+		keystores = append(keystores, keystore)
+	}
+
+	result = &TemplateDistributionPreview{
+		VaultID:     core.StringPtr(vaultID),
+		Label:       labels.Label,
+		MissingTags: missingTags,
+		Instances:   []TemplateDistributionInstance{},
+	}
+	for i, entry := range entries {
+		var properties *KeystoresPropertiesCreate
+		properties, err = toKeystoresPropertiesCreate(entry)
+		if err != nil {
+			return nil, err
+		}
+		group := core.StringNilMapper(properties.Group)
+		keystoreType := core.StringNilMapper(properties.Type)
+		matched := 0
+		for _, keystore := range keystores {
+			if !core.SliceContains(keystore.Groups, group) ||
+				(keystoreType != "" && core.StringNilMapper(keystore.Type) != keystoreType) {
+				continue
+			}
+			matched++
+			result.Instances = append(result.Instances, TemplateDistributionInstance{
+				Keystore: &KeystoreGroupKeystore{
+					ID:   keystore.ID,
+					Name: keystore.Name,
+					Type: keystore.Type,
+				},
+				Group:           properties.Group,
+				NamingScheme:    labels.Keystores[i].NamingScheme,
+				LabelInKeystore: labels.Keystores[i].Label,
+				Properties:      properties,
+			})
+		}
+		if matched == 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf(
+				"keystore entry %d (group '%s', type '%s') matches no keystore in vault '%s'", i, group, keystoreType, vaultID))
+		}
+	}
+	return
+}
+
+// renderPreviewLabels renders the labels of a key created from a template. Tags without a value are rendered as the
+// tag itself and returned as missing.
+func renderPreviewLabels(namingScheme string, key *KeyProperties, keystores []KeystoresPropertiesCreateIntf, labelTags []Tag) (labels *RenderedKeyLabels, missing []string, err error) {
+	labels, err = RenderKeyLabels(namingScheme, key, keystores, labelTags)
+	missingErr, ok := err.(*MissingTagsError)
+	if !ok {
+		return
+	}
+	missing = missingErr.Tags
+	placeholders := append([]Tag{}, labelTags...)
+	for _, tag := range missing {
+		placeholders = append(placeholders, Tag{Name: core.StringPtr(tag), Value: core.StringPtr("<" + tag + ">")})
+	}
+	labels, err = RenderKeyLabels(namingScheme, key, keystores, placeholders)
+	return
+}
+
+// PreviewTemplateDistributionOptions : The PreviewTemplateDistribution options.
+type PreviewTemplateDistributionOptions struct {
+	// An existing template. Exactly one of Template and CreateKeyTemplateOptions must be set.
+	Template *Template
+
+	// The options of a template to be created.
+	CreateKeyTemplateOptions *CreateKeyTemplateOptions
+
+	// The label tags of the managed key, used to render its labels.
+	LabelTags []Tag
+}
+
+// NewPreviewTemplateDistributionOptions : Instantiate PreviewTemplateDistributionOptions for an existing template
+func (*UkoV4) NewPreviewTemplateDistributionOptions(template *Template) *PreviewTemplateDistributionOptions {
+	return &PreviewTemplateDistributionOptions{
+		Template: template,
+	}
+}
+
+// NewPreviewTemplateDistributionOptionsForCreate : Instantiate PreviewTemplateDistributionOptions for a template to be
+// created
+func (*UkoV4) NewPreviewTemplateDistributionOptionsForCreate(createKeyTemplateOptions *CreateKeyTemplateOptions) *PreviewTemplateDistributionOptions {
+	return &PreviewTemplateDistributionOptions{
+		CreateKeyTemplateOptions: createKeyTemplateOptions,
+	}
+}
+
+// SetLabelTags : Allow user to set LabelTags
+func (_options *PreviewTemplateDistributionOptions) SetLabelTags(labelTags []Tag) *PreviewTemplateDistributionOptions {
+	_options.LabelTags = labelTags
+	return _options
+}
+
+// TemplateDistributionPreview : The key instances a managed key created from a template would get.
+type TemplateDistributionPreview struct {
+	// UUID of the vault of the template.
+	VaultID *string `json:"vault_id"`
+
+	// The label of the managed key.
+	Label *string `json:"label"`
+
+	// The naming scheme tags that had no value and were rendered as the tag itself.
+	MissingTags []string `json:"missing_tags,omitempty"`
+
+	// The key instances, in the order of the keystore entries of the template.
+	Instances []TemplateDistributionInstance `json:"instances"`
+
+	// Keystore entries that match no keystore.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// TemplateDistributionInstance : The key instance a managed key would get in a keystore.
+type TemplateDistributionInstance struct {
+	// The keystore.
+	Keystore *KeystoreGroupKeystore `json:"keystore"`
+
+	// The group of the keystore entry that matched the keystore.
+	Group *string `json:"group"`
+
+	// The naming scheme used for the label.
+	NamingScheme *string `json:"naming_scheme,omitempty"`
+
+	// The label of the key in the keystore.
+	LabelInKeystore *string `json:"label_in_keystore"`
+
+	// The keystore entry of the template, with its keystore-specific properties.
+	Properties *KeystoresPropertiesCreate `json:"properties"`
+}
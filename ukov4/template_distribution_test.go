@@ -0,0 +1,140 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-hpcs-uko-sdk/ukov4"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`PreviewTemplateDistribution`, func() {
+	var testServer *httptest.Server
+	var ukoService *ukov4.UkoV4
+
+	BeforeEach(func() {
+		testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+
+			res.Header().Set("Content-type", "application/json")
+			switch req.Method + " " + req.URL.EscapedPath() {
+			case "GET /api/v4/keystores":
+				Expect(req.URL.Query().Get("vault.id")).To(Equal("v1"))
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"total_count": 4, "limit": 100, "offset": 0, "keystores": [
+					{"id": "ks1", "vault": {"id": "v1"}, "name": "aws-eu-1", "type": "aws_kms", "groups": ["eu"]},
+					{"id": "ks2", "vault": {"id": "v1"}, "name": "aws-eu-2", "type": "aws_kms", "groups": ["eu", "prod"]},
+					{"id": "ks3", "vault": {"id": "v1"}, "name": "gcp-eu", "type": "google_kms", "groups": ["eu"]},
+					{"id": "ks4", "vault": {"id": "v1"}, "name": "aws-us", "type": "aws_kms", "groups": ["us"]}]}`)
+			default:
+				Fail("unexpected request " + req.Method + " " + req.URL.String())
+			}
+		}))
+		var err error
+		ukoService, err = ukov4.NewUkoV4(&ukov4.UkoV4Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(err).To(BeNil())
+	})
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	createOptions := func() *ukov4.CreateKeyTemplateOptions {
+		return &ukov4.CreateKeyTemplateOptions{
+			Vault:        &ukov4.VaultReferenceInCreationRequest{ID: core.StringPtr("v1")},
+			Name:         core.StringPtr("aes-eu"),
+			NamingScheme: core.StringPtr("<app>-<algorithm>"),
+			Key:          &ukov4.KeyProperties{Algorithm: core.StringPtr("aes"), Size: core.StringPtr("256")},
+			Keystores: []ukov4.KeystoresPropertiesCreateIntf{
+				&ukov4.KeystoresPropertiesCreateAwsKms{Group: core.StringPtr("eu"), Type: core.StringPtr("aws_kms")},
+				&ukov4.KeystoresPropertiesCreateGoogleKms{
+					Group:              core.StringPtr("eu"),
+					Type:               core.StringPtr("google_kms"),
+					NamingScheme:       core.StringPtr("<app>_<keystore_type>"),
+					GoogleKmsAlgorithm: core.StringPtr("google_symmetric_encryption"),
+				},
+				&ukov4.KeystoresPropertiesCreateAwsKms{Group: core.StringPtr("apac"), Type: core.StringPtr("aws_kms")},
+			},
+		}
+	}
+
+	It(`Preview the keystores and labels of a template to be created`, func() {
+		options := ukoService.NewPreviewTemplateDistributionOptionsForCreate(createOptions()).
+			SetLabelTags([]ukov4.Tag{{Name: core.StringPtr("app"), Value: core.StringPtr("pay")}})
+		preview, err := ukoService.PreviewTemplateDistribution(options)
+		Expect(err).To(BeNil())
+		Expect(*preview.Label).To(Equal("pay-aes"))
+		Expect(preview.MissingTags).To(BeEmpty())
+
+		Expect(preview.Instances).To(HaveLen(3))
+		ids := []string{}
+		for _, instance := range preview.Instances {
+			ids = append(ids, *instance.Keystore.ID)
+		}
+		Expect(ids).To(Equal([]string{"ks1", "ks2", "ks3"}))
+		Expect(*preview.Instances[0].LabelInKeystore).To(Equal("pay-aes"))
+		google := preview.Instances[2]
+		Expect(*google.LabelInKeystore).To(Equal("pay_google_kms"))
+		Expect(*google.NamingScheme).To(Equal("<app>_<keystore_type>"))
+		Expect(*google.Properties.GoogleKmsAlgorithm).To(Equal("google_symmetric_encryption"))
+
+		Expect(preview.Warnings).To(Equal([]string{
+			"keystore entry 2 (group 'apac', type 'aws_kms') matches no keystore in vault 'v1'",
+		}))
+	})
+	It(`Preview an existing template without label tags`, func() {
+		template := &ukov4.Template{
+			Vault:        &ukov4.VaultReference{ID: core.StringPtr("v1")},
+			NamingScheme: core.StringPtr("<app>-<env>"),
+			Key:          &ukov4.KeyProperties{Algorithm: core.StringPtr("aes")},
+			Keystores: []ukov4.KeystoresPropertiesCreateIntf{
+				&ukov4.KeystoresPropertiesCreate{Group: core.StringPtr("us"), Type: core.StringPtr("aws_kms")},
+			},
+		}
+		preview, err := ukoService.PreviewTemplateDistribution(ukoService.NewPreviewTemplateDistributionOptions(template))
+		Expect(err).To(BeNil())
+		Expect(*preview.Label).To(Equal("<app>-<env>"))
+		Expect(preview.MissingTags).To(Equal([]string{"app", "env"}))
+		Expect(preview.Instances).To(HaveLen(1))
+		Expect(*preview.Instances[0].Keystore.Name).To(Equal("aws-us"))
+		Expect(preview.Warnings).To(BeEmpty())
+	})
+	It(`Reject invalid options (negative test)`, func() {
+		_, err := ukoService.PreviewTemplateDistribution(nil)
+		Expect(err).ToNot(BeNil())
+		_, err = ukoService.PreviewTemplateDistribution(&ukov4.PreviewTemplateDistributionOptions{})
+		Expect(err).To(MatchError("one of Template and CreateKeyTemplateOptions must be set"))
+		_, err = ukoService.PreviewTemplateDistribution(&ukov4.PreviewTemplateDistributionOptions{
+			Template: &ukov4.Template{}, CreateKeyTemplateOptions: createOptions()})
+		Expect(err).ToNot(BeNil())
+		_, err = ukoService.PreviewTemplateDistribution(ukoService.NewPreviewTemplateDistributionOptions(&ukov4.Template{}))
+		Expect(err).To(MatchError("the template has no vault"))
+
+		options := createOptions()
+		options.NamingScheme = core.StringPtr("<app")
+		_, err = ukoService.PreviewTemplateDistribution(ukoService.NewPreviewTemplateDistributionOptionsForCreate(options))
+		_, ok := err.(*ukov4.NamingSchemeError)
+		Expect(ok).To(BeTrue())
+	})
+})
@@ -6173,10 +6173,46 @@ func (*KeyInstance) isaKeyInstance() bool {
 
 type KeyInstanceIntf interface {
 	isaKeyInstance() bool
+	AsAwsKms() (*KeyInstanceAwsKms, bool)
+	AsAzure() (*KeyInstanceAzure, bool)
+	AsCca() (*KeyInstanceCca, bool)
+	AsGoogleKms() (*KeyInstanceGoogleKms, bool)
+	AsIbmCloudKms() (*KeyInstanceIbmCloudKms, bool)
 }
 
 // UnmarshalKeyInstance unmarshals an instance of KeyInstance from the specified map of raw messages.
+// Key instances are unmarshalled into the model of the type of their keystore, such as KeyInstanceAwsKms; instances
+// in keystores of unknown types are unmarshalled into a KeyInstance.
+// This is a breaking change: asserting a key instance returned by the service to *KeyInstance now yields ok == false
+// for instances in keystores of known types. Use the As methods or VisitKeyInstance instead.
 func UnmarshalKeyInstance(m map[string]json.RawMessage, result interface{}) (err error) {
+	// Retrieve discriminator value to determine correct "subclass".
+	var keystore *InstanceInKeystore
+	err = core.UnmarshalModel(m, "keystore", &keystore, UnmarshalInstanceInKeystore)
+	if err != nil {
+		err = fmt.Errorf("error unmarshalling discriminator property 'keystore.type': %s", err.Error())
+		return
+	}
+	var discValue string
+	if keystore != nil && keystore.Type != nil {
+		discValue = *keystore.Type
+	}
+	if discValue == "aws_kms" {
+		err = core.UnmarshalModel(m, "", result, UnmarshalKeyInstanceAwsKms)
+		return
+	} else if discValue == "azure_key_vault" {
+		err = core.UnmarshalModel(m, "", result, UnmarshalKeyInstanceAzure)
+		return
+	} else if discValue == "cca" {
+		err = core.UnmarshalModel(m, "", result, UnmarshalKeyInstanceCca)
+		return
+	} else if discValue == "google_kms" {
+		err = core.UnmarshalModel(m, "", result, UnmarshalKeyInstanceGoogleKms)
+		return
+	} else if discValue == "ibm_cloud_kms" {
+		err = core.UnmarshalModel(m, "", result, UnmarshalKeyInstanceIbmCloudKms)
+		return
+	}
 	obj := new(KeyInstance)
 	err = core.UnmarshalPrimitive(m, "id", &obj.ID)
 	if err != nil {
@@ -6603,10 +6639,42 @@ func (*Keystore) isaKeystore() bool {
 
 type KeystoreIntf interface {
 	isaKeystore() bool
+	AsAwsKms() (*KeystoreTypeAwsKms, bool)
+	AsAzure() (*KeystoreTypeAzure, bool)
+	AsCca() (*KeystoreTypeCca, bool)
+	AsGoogleKms() (*KeystoreTypeGoogleKms, bool)
+	AsIbmCloudKms() (*KeystoreTypeIbmCloudKms, bool)
+	GetType() KeystoreType
 }
 
 // UnmarshalKeystore unmarshals an instance of Keystore from the specified map of raw messages.
+// Keystores are unmarshalled into the model of their type, such as KeystoreTypeAwsKms; keystores of unknown types
+// are unmarshalled into a Keystore. This is a breaking change: asserting a keystore returned by the service to
+// *Keystore now yields ok == false for every known type. Use the As methods, VisitKeystore or GetType instead.
 func UnmarshalKeystore(m map[string]json.RawMessage, result interface{}) (err error) {
+	// Retrieve discriminator value to determine correct "subclass".
+	var discValue string
+	err = core.UnmarshalPrimitive(m, "type", &discValue)
+	if err != nil {
+		err = fmt.Errorf("error unmarshalling discriminator property 'type': %s", err.Error())
+		return
+	}
+	if discValue == "aws_kms" {
+		err = core.UnmarshalModel(m, "", result, UnmarshalKeystoreTypeAwsKms)
+		return
+	} else if discValue == "azure_key_vault" {
+		err = core.UnmarshalModel(m, "", result, UnmarshalKeystoreTypeAzure)
+		return
+	} else if discValue == "cca" {
+		err = core.UnmarshalModel(m, "", result, UnmarshalKeystoreTypeCca)
+		return
+	} else if discValue == "google_kms" {
+		err = core.UnmarshalModel(m, "", result, UnmarshalKeystoreTypeGoogleKms)
+		return
+	} else if discValue == "ibm_cloud_kms" {
+		err = core.UnmarshalModel(m, "", result, UnmarshalKeystoreTypeIbmCloudKms)
+		return
+	}
 	obj := new(Keystore)
 	err = core.UnmarshalModel(m, "vault", &obj.Vault, UnmarshalVaultReference)
 	if err != nil {
@@ -0,0 +1,579 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/go-openapi/strfmt"
+)
+
+// VaultBundleFormatVersion is the version of the vault bundle format written by ExportVault. ImportVault rejects
+// bundles of any other version.
+const VaultBundleFormatVersion int64 = 1
+
+// keystoreCredential is a keystore property holding a credential, which is never exported.
+type keystoreCredential struct {
+	name  string
+	field func(*KeystoreCreationRequest) **string
+}
+
+// keystoreCredentials are the credential properties of each keystore type.
+var keystoreCredentials = map[string][]keystoreCredential{
+	KeystoreCreationRequest_Type_AwsKms: {
+		{"aws_access_key_id", func(k *KeystoreCreationRequest) **string { return &k.AwsAccessKeyID }},
+		{"aws_secret_access_key", func(k *KeystoreCreationRequest) **string { return &k.AwsSecretAccessKey }},
+	},
+	KeystoreCreationRequest_Type_AzureKeyVault: {
+		{"azure_service_principal_password", func(k *KeystoreCreationRequest) **string { return &k.AzureServicePrincipalPassword }},
+	},
+	KeystoreCreationRequest_Type_GoogleKms: {
+		{"google_credentials", func(k *KeystoreCreationRequest) **string { return &k.GoogleCredentials }},
+	},
+	KeystoreCreationRequest_Type_IbmCloudKms: {
+		{"ibm_api_key", func(k *KeystoreCreationRequest) **string { return &k.IbmApiKey }},
+	},
+}
+
+// CredentialPlaceholder returns the placeholder written by ExportVault in place of a keystore credential, such as
+// "${aws-eu.aws_secret_access_key}". The name inside the braces is the key of the credential in
+// ImportVaultOptions.Credentials.
+func CredentialPlaceholder(keystoreName string, property string) string {
+	return "${" + keystoreName + "." + property + "}"
+}
+
+// credentialName returns the name inside a placeholder, or false if the value is not a placeholder.
+func credentialName(value string) (string, bool) {
+	if strings.HasPrefix(value, "${") && strings.HasSuffix(value, "}") {
+		return value[2 : len(value)-1], true
+	}
+	return "", false
+}
+
+// MissingCredentialsError : The error returned by ImportVault when credential placeholders of the bundle have no value.
+type MissingCredentialsError struct {
+	// The names of the placeholders without a value.
+	Credentials []string
+}
+
+// Error implements the error interface.
+func (e *MissingCredentialsError) Error() string {
+	return fmt.Sprintf("missing values for credentials: %s", strings.Join(e.Credentials, ", "))
+}
+
+// VaultBundle : A portable description of a vault and its contents, written by ExportVault and read by ImportVault.
+type VaultBundle struct {
+	// The version of the bundle format.
+	FormatVersion *int64 `json:"format_version"`
+
+	// Date and time when the bundle was exported.
+	ExportedAt *strfmt.DateTime `json:"exported_at,omitempty"`
+
+	// The exported vault.
+	Vault *ExportedVault `json:"vault"`
+
+	// The keystores of the vault, with credentials replaced by placeholders.
+	Keystores []ExportedKeystore `json:"keystores"`
+
+	// The user-defined key templates of the vault.
+	Templates []ExportedKeyTemplate `json:"templates"`
+
+	// The metadata of the managed keys of the vault. Key material is never exported.
+	ManagedKeys []ExportedManagedKey `json:"managed_keys"`
+}
+
+// ExportedVault : A vault in a vault bundle.
+type ExportedVault struct {
+	// The UUID of the vault in the exporting instance.
+	ID *string `json:"id"`
+
+	// Name of the vault.
+	Name *string `json:"name"`
+
+	// Description of the vault.
+	Description *string `json:"description,omitempty"`
+
+	// The label of the primary recovery key of the vault.
+	RecoveryKeyLabel *string `json:"recovery_key_label,omitempty"`
+}
+
+// ExportedKeystore : A keystore in a vault bundle.
+type ExportedKeystore struct {
+	// The UUID of the keystore in the exporting instance.
+	ID *string `json:"id"`
+
+	// The properties the keystore is created with. The vault is not set; credentials are placeholders returned by
+	// CredentialPlaceholder.
+	Keystore *KeystoreCreationRequest `json:"keystore"`
+}
+
+// ExportedKeyTemplate : A key template in a vault bundle.
+type ExportedKeyTemplate struct {
+	// The UUID of the template in the exporting instance.
+	ID *string `json:"id"`
+
+	// Name of the key template.
+	Name *string `json:"name"`
+
+	// Description of the key template.
+	Description *string `json:"description,omitempty"`
+
+	// Managed key naming scheme which will be applied to every key created with this template.
+	NamingScheme *string `json:"naming_scheme,omitempty"`
+
+	// State of the template which determines if the template is archived or unarchived.
+	State *string `json:"state,omitempty"`
+
+	// Properties describing the properties of the managed key.
+	Key *KeyProperties `json:"key"`
+
+	// The type and group of the target keystores the managed keys are to be installed in.
+	Keystores []KeystoresPropertiesCreate `json:"keystores"`
+}
+
+// ExportedManagedKey : The metadata of a managed key in a vault bundle.
+type ExportedManagedKey struct {
+	// The UUID of the key in the exporting instance.
+	ID *string `json:"id"`
+
+	// The label of the key.
+	Label *string `json:"label"`
+
+	// Description of the managed key.
+	Description *string `json:"description,omitempty"`
+
+	// The name of the template the key was created from.
+	TemplateName *string `json:"template_name,omitempty"`
+
+	// The state of the key.
+	State *string `json:"state,omitempty"`
+
+	// The algorithm of the key.
+	Algorithm *string `json:"algorithm,omitempty"`
+
+	// The size of the underlying cryptographic key or curve name.
+	Size *string `json:"size,omitempty"`
+
+	// Key label tags of the key.
+	LabelTags []Tag `json:"label_tags,omitempty"`
+
+	// Tags of the key.
+	Tags []Tag `json:"tags,omitempty"`
+}
+
+// CredentialPlaceholders returns the names of the credential placeholders of the bundle, sorted. A value for each of
+// them must be passed to ImportVault.
+func (bundle *VaultBundle) CredentialPlaceholders() (names []string) {
+	for _, keystore := range bundle.Keystores {
+		if keystore.Keystore == nil {
+			continue
+		}
+		for _, credential := range keystoreCredentials[core.StringNilMapper(keystore.Keystore.Type)] {
+			value := *credential.field(keystore.Keystore)
+			if value == nil {
+				continue
+			}
+			if name, ok := credentialName(*value); ok && !core.SliceContains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return
+}
+
+// ExportVault : Export the configuration of a vault
+// Writes a bundle of the vault, its keystores with credentials replaced by placeholders, its user-defined key templates
+// and the metadata of its managed keys. System and shadow templates are not exported since the service creates them.
+func (uko *UkoV4) ExportVault(exportVaultOptions *ExportVaultOptions) (result *VaultBundle, err error) {
+	return uko.ExportVaultWithContext(context.Background(), exportVaultOptions)
+}
+
+// ExportVaultWithContext is an alternate form of the ExportVault method which supports a Context parameter
+func (uko *UkoV4) ExportVaultWithContext(ctx context.Context, exportVaultOptions *ExportVaultOptions) (result *VaultBundle, err error) {
+	err = core.ValidateNotNil(exportVaultOptions, "exportVaultOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(exportVaultOptions, "exportVaultOptions")
+	if err != nil {
+		return
+	}
+
+	vault, _, err := uko.GetVaultWithContext(ctx, &GetVaultOptions{ID: exportVaultOptions.VaultID})
+	if err != nil {
+		return
+	}
+	exportedAt := strfmt.DateTime(time.Now().UTC())
+	bundle := &VaultBundle{
+		FormatVersion: core.Int64Ptr(VaultBundleFormatVersion),
+		ExportedAt:    &exportedAt,
+		Vault: &ExportedVault{
+			ID:               vault.ID,
+			Name:             vault.Name,
+			Description:      vault.Description,
+			RecoveryKeyLabel: vault.RecoveryKeyLabel,
+		},
+		Keystores:   []ExportedKeystore{},
+		Templates:   []ExportedKeyTemplate{},
+		ManagedKeys: []ExportedManagedKey{},
+	}
+	vaultFilter := []string{*exportVaultOptions.VaultID}
+
+	keystoresPager, err := uko.NewKeystoresPager(&ListKeystoresOptions{VaultID: vaultFilter})
+	if err != nil {
+		return
+	}
+	keystores, err := keystoresPager.GetAllWithContext(ctx)
+	if err != nil {
+		return
+	}
+	for _, keystore := range keystores {
+		var exported ExportedKeystore
+		exported, err = exportKeystore(keystore)
+		if err != nil {
+			return
+		}
+		bundle.Keystores = append(bundle.Keystores, exported)
+	}
+
+	templatesPager, err := uko.NewKeyTemplatesPager(&ListKeyTemplatesOptions{VaultID: vaultFilter})
+	if err != nil {
+		return
+	}
+	templates, err := templatesPager.GetAllWithContext(ctx)
+	if err != nil {
+		return
+	}
+	for i := range templates {
+		template := &templates[i]
+		if !core.SliceContains(template.Type, Template_Type_UserDefined) {
+			continue
+		}
+		exported := ExportedKeyTemplate{
+			ID:           template.ID,
+			Name:         template.Name,
+			Description:  template.Description,
+			NamingScheme: template.NamingScheme,
+			State:        template.State,
+			Key:          template.Key,
+			Keystores:    []KeystoresPropertiesCreate{},
+		}
+		for _, keystore := range template.Keystores {
+			var properties *KeystoresPropertiesCreate
+			properties, err = toKeystoresPropertiesCreate(keystore)
+			if err != nil {
+				return
+			}
+			exported.Keystores = append(exported.Keystores, *properties)
+		}
+		bundle.Templates = append(bundle.Templates, exported)
+	}
+
+	keysPager, err := uko.NewManagedKeysPager(&ListManagedKeysOptions{VaultID: vaultFilter})
+	if err != nil {
+		return
+	}
+	keys, err := keysPager.GetAllWithContext(ctx)
+	if err != nil {
+		return
+	}
+	for i := range keys {
+		key := &keys[i]
+		exported := ExportedManagedKey{
+			ID:          key.ID,
+			Label:       key.Label,
+			Description: key.Description,
+			State:       key.State,
+			Algorithm:   key.Algorithm,
+			Size:        key.Size,
+			LabelTags:   key.LabelTags,
+			Tags:        key.Tags,
+		}
+		if key.Template != nil {
+			exported.TemplateName = key.Template.Name
+		}
+		bundle.ManagedKeys = append(bundle.ManagedKeys, exported)
+	}
+	result = bundle
+	return
+}
+
+// exportKeystore converts a keystore into its creation request with credentials replaced by placeholders.
+func exportKeystore(keystore KeystoreIntf) (exported ExportedKeystore, err error) {
+	request := new(KeystoreCreationRequest)
+	err = remarshal(keystore, request)
+	if err != nil {
+		return
+	}
+	source := new(Keystore)
+	err = remarshal(keystore, source)
+	if err != nil {
+		return
+	}
+	request.Vault = nil
+	for _, credentials := range keystoreCredentials {
+		for _, credential := range credentials {
+			*credential.field(request) = nil
+		}
+	}
+	name := core.StringNilMapper(source.Name)
+	if name == "" {
+		name = core.StringNilMapper(source.ID)
+	}
+	for _, credential := range keystoreCredentials[core.StringNilMapper(request.Type)] {
+		*credential.field(request) = core.StringPtr(CredentialPlaceholder(name, credential.name))
+	}
+	exported = ExportedKeystore{ID: source.ID, Keystore: request}
+	return
+}
+
+// ExportVaultOptions : The ExportVault options.
+type ExportVaultOptions struct {
+	// The UUID of the vault to export.
+	VaultID *string `json:"vault_id" validate:"required,ne="`
+}
+
+// NewExportVaultOptions : Instantiate ExportVaultOptions
+func (*UkoV4) NewExportVaultOptions(vaultID string) *ExportVaultOptions {
+	return &ExportVaultOptions{
+		VaultID: core.StringPtr(vaultID),
+	}
+}
+
+// SetVaultID : Allow user to set VaultID
+func (_options *ExportVaultOptions) SetVaultID(vaultID string) *ExportVaultOptions {
+	_options.VaultID = core.StringPtr(vaultID)
+	return _options
+}
+
+// ImportVault : Recreate the configuration of an exported vault
+// Creates a vault, or uses the vault given by VaultID, and creates the keystores and key templates of the bundle in
+// it. Credential placeholders are replaced with the values of Credentials; if any of them has no value, a
+// *MissingCredentialsError is returned before anything is created. When CreateManagedKeys is set, a managed key is
+// created from its template for each exported key; the new keys have new key material. The result maps the UUID of
+// each resource in the bundle to the UUID of the resource created for it. If a creation fails, the result so far is
+// returned together with the error.
+func (uko *UkoV4) ImportVault(importVaultOptions *ImportVaultOptions) (result *VaultImportResult, err error) {
+	return uko.ImportVaultWithContext(context.Background(), importVaultOptions)
+}
+
+// ImportVaultWithContext is an alternate form of the ImportVault method which supports a Context parameter
+func (uko *UkoV4) ImportVaultWithContext(ctx context.Context, importVaultOptions *ImportVaultOptions) (result *VaultImportResult, err error) {
+	err = core.ValidateNotNil(importVaultOptions, "importVaultOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(importVaultOptions, "importVaultOptions")
+	if err != nil {
+		return
+	}
+	bundle := importVaultOptions.Bundle
+	if bundle.FormatVersion == nil || *bundle.FormatVersion != VaultBundleFormatVersion {
+		err = fmt.Errorf("unsupported vault bundle format version: %s", int64NilMapper(bundle.FormatVersion))
+		return
+	}
+	if bundle.Vault == nil {
+		err = fmt.Errorf("vault bundle has no vault")
+		return
+	}
+	var missing []string
+	for _, name := range bundle.CredentialPlaceholders() {
+		if _, ok := importVaultOptions.Credentials[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		err = &MissingCredentialsError{Credentials: missing}
+		return
+	}
+
+	result = &VaultImportResult{IDMap: map[string]string{}}
+	vaultID := core.StringNilMapper(importVaultOptions.VaultID)
+	if vaultID == "" {
+		name := bundle.Vault.Name
+		if importVaultOptions.Name != nil {
+			name = importVaultOptions.Name
+		}
+		var vault *Vault
+		vault, _, err = uko.CreateVaultWithContext(ctx, &CreateVaultOptions{
+			Name:             name,
+			Description:      bundle.Vault.Description,
+			RecoveryKeyLabel: bundle.Vault.RecoveryKeyLabel,
+		})
+		if err != nil {
+			return
+		}
+		vaultID = *vault.ID
+	}
+	result.VaultID = core.StringPtr(vaultID)
+	result.remap(bundle.Vault.ID, &vaultID)
+	vault := &VaultReferenceInCreationRequest{ID: core.StringPtr(vaultID)}
+
+	for _, exported := range bundle.Keystores {
+		if exported.Keystore == nil {
+			continue
+		}
+		body := *exported.Keystore
+		body.Vault = vault
+		for _, credential := range keystoreCredentials[core.StringNilMapper(body.Type)] {
+			field := credential.field(&body)
+			if *field == nil {
+				continue
+			}
+			if name, ok := credentialName(**field); ok {
+				*field = core.StringPtr(importVaultOptions.Credentials[name])
+			}
+		}
+		var created KeystoreIntf
+		created, _, err = uko.CreateKeystoreWithContext(ctx, &CreateKeystoreOptions{KeystoreBody: &body})
+		if err != nil {
+			return
+		}
+		keystore := new(Keystore)
+		err = remarshal(created, keystore)
+		if err != nil {
+			return
+		}
+		result.remap(exported.ID, keystore.ID)
+	}
+
+	for _, exported := range bundle.Templates {
+		keystores := make([]KeystoresPropertiesCreateIntf, len(exported.Keystores))
+		for i := range exported.Keystores {
+			keystores[i] = &exported.Keystores[i]
+		}
+		var template *Template
+		template, _, err = uko.CreateKeyTemplateWithContext(ctx, &CreateKeyTemplateOptions{
+			Vault:        vault,
+			Name:         exported.Name,
+			Key:          exported.Key,
+			Keystores:    keystores,
+			Description:  exported.Description,
+			NamingScheme: exported.NamingScheme,
+			State:        exported.State,
+		})
+		if err != nil {
+			return
+		}
+		result.remap(exported.ID, template.ID)
+	}
+
+	if importVaultOptions.CreateManagedKeys != nil && *importVaultOptions.CreateManagedKeys {
+		for _, exported := range bundle.ManagedKeys {
+			if exported.TemplateName == nil {
+				continue
+			}
+			var key *ManagedKey
+			key, _, err = uko.CreateManagedKeyWithContext(ctx, &CreateManagedKeyOptions{
+				TemplateName: exported.TemplateName,
+				Vault:        vault,
+				Label:        exported.Label,
+				Description:  exported.Description,
+			})
+			if err != nil {
+				return
+			}
+			result.remap(exported.ID, key.ID)
+		}
+	}
+	return
+}
+
+// ImportVaultOptions : The ImportVault options.
+type ImportVaultOptions struct {
+	// The bundle to import.
+	Bundle *VaultBundle `json:"bundle" validate:"required"`
+
+	// The name of the created vault; defaults to the name of the exported vault.
+	Name *string `json:"name,omitempty"`
+
+	// The UUID of an existing vault to import into instead of creating a vault.
+	VaultID *string `json:"vault_id,omitempty"`
+
+	// The value of each credential placeholder of the bundle, keyed by the name inside the placeholder.
+	Credentials map[string]string `json:"-"`
+
+	// Create the managed keys of the bundle from their templates.
+	CreateManagedKeys *bool `json:"create_managed_keys,omitempty"`
+}
+
+// NewImportVaultOptions : Instantiate ImportVaultOptions
+func (*UkoV4) NewImportVaultOptions(bundle *VaultBundle) *ImportVaultOptions {
+	return &ImportVaultOptions{
+		Bundle: bundle,
+	}
+}
+
+// SetBundle : Allow user to set Bundle
+func (_options *ImportVaultOptions) SetBundle(bundle *VaultBundle) *ImportVaultOptions {
+	_options.Bundle = bundle
+	return _options
+}
+
+// SetName : Allow user to set Name
+func (_options *ImportVaultOptions) SetName(name string) *ImportVaultOptions {
+	_options.Name = core.StringPtr(name)
+	return _options
+}
+
+// SetVaultID : Allow user to set VaultID
+func (_options *ImportVaultOptions) SetVaultID(vaultID string) *ImportVaultOptions {
+	_options.VaultID = core.StringPtr(vaultID)
+	return _options
+}
+
+// SetCredentials : Allow user to set Credentials
+func (_options *ImportVaultOptions) SetCredentials(credentials map[string]string) *ImportVaultOptions {
+	_options.Credentials = credentials
+	return _options
+}
+
+// SetCredential : Allow user to set the value of a single credential placeholder
+func (_options *ImportVaultOptions) SetCredential(name string, value string) *ImportVaultOptions {
+	if _options.Credentials == nil {
+		_options.Credentials = map[string]string{}
+	}
+	_options.Credentials[name] = value
+	return _options
+}
+
+// SetCreateManagedKeys : Allow user to set CreateManagedKeys
+func (_options *ImportVaultOptions) SetCreateManagedKeys(createManagedKeys bool) *ImportVaultOptions {
+	_options.CreateManagedKeys = core.BoolPtr(createManagedKeys)
+	return _options
+}
+
+// VaultImportResult : The result of an ImportVault run.
+type VaultImportResult struct {
+	// The UUID of the vault the bundle was imported into.
+	VaultID *string `json:"vault_id"`
+
+	// The UUID of each resource created, keyed by the UUID of the resource in the bundle.
+	IDMap map[string]string `json:"id_map"`
+}
+
+func (result *VaultImportResult) remap(sourceID *string, targetID *string) {
+	if sourceID != nil && targetID != nil {
+		result.IDMap[*sourceID] = *targetID
+	}
+}
@@ -0,0 +1,186 @@
+/**
+ * (C) Copyright IBM Corp. 2023.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ukov4_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-hpcs-uko-sdk/ukov4"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`ExportVault and ImportVault`, func() {
+	var testServer *httptest.Server
+	var created map[string][]map[string]interface{}
+
+	BeforeEach(func() {
+		created = map[string][]map[string]interface{}{}
+		testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+
+			res.Header().Set("Content-type", "application/json")
+			switch req.Method + " " + req.URL.EscapedPath() {
+			case "GET /api/v4/vaults/v1":
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"id": "v1", "name": "staging", "description": "Staging keys", "keys_count": 1, "key_templates_count": 2, "keystores_count": 2}`)
+			case "GET /api/v4/keystores":
+				Expect(req.URL.Query().Get("vault.id")).To(Equal("v1"))
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"total_count": 2, "limit": 2, "offset": 0, "keystores": [
+					{"id": "ks1", "vault": {"id": "v1"}, "name": "aws-eu", "type": "aws_kms", "groups": ["eu"], "aws_region": "eu-central-1", "aws_access_key_id": "AKIA"},
+					{"id": "ks2", "vault": {"id": "v1"}, "name": "cca", "type": "cca", "groups": ["hsm"], "cca_host": "hsm.example.com", "cca_port": 9000}]}`)
+			case "GET /api/v4/templates":
+				Expect(req.URL.Query().Get("vault.id")).To(Equal("v1"))
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"total_count": 2, "limit": 2, "offset": 0, "templates": [
+					{"id": "t1", "vault": {"id": "v1"}, "name": "aes-eu", "naming_scheme": "A-<app>", "type": ["user_defined"], "state": "unarchived", "keys_count": 1, "description": "AES", "key": {"algorithm": "aes", "size": "256", "activation_date": "P0D", "expiration_date": "P365D", "state": "active"}, "keystores": [{"group": "eu", "type": "aws_kms"}]},
+					{"id": "t2", "vault": {"id": "v1"}, "name": "shadow", "type": ["shadow"], "state": "unarchived", "keys_count": 0, "description": "", "key": {"algorithm": "aes"}, "keystores": []}]}`)
+			case "GET /api/v4/managed_keys":
+				Expect(req.URL.Query().Get("vault.id")).To(Equal("v1"))
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"total_count": 1, "limit": 1, "offset": 0, "managed_keys": [
+					{"id": "k1", "vault": {"id": "v1"}, "template": {"id": "t1", "name": "aes-eu"}, "label": "A-pay", "description": "Payments", "state": "active", "algorithm": "aes", "size": "256", "label_tags": [{"name": "app", "value": "pay"}], "referenced_keystores": [], "instances": [], "status_in_keystores": []}]}`)
+			case "POST /api/v4/vaults", "POST /api/v4/keystores", "POST /api/v4/templates", "POST /api/v4/managed_keys":
+				collection := req.URL.EscapedPath()[len("/api/v4/"):]
+				body, _ := ioutil.ReadAll(req.Body)
+				var item map[string]interface{}
+				Expect(json.Unmarshal(body, &item)).To(Succeed())
+				item["id"] = fmt.Sprintf("new-%s-%d", collection, len(created[collection])+1)
+				created[collection] = append(created[collection], item)
+				res.WriteHeader(201)
+				Expect(json.NewEncoder(res).Encode(item)).To(Succeed())
+			default:
+				Fail(fmt.Sprintf("unexpected request %s %s", req.Method, req.URL.EscapedPath()))
+			}
+		}))
+	})
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	newService := func() *ukov4.UkoV4 {
+		ukoService, serviceErr := ukov4.NewUkoV4(&ukov4.UkoV4Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+		return ukoService
+	}
+	exportBundle := func(ukoService *ukov4.UkoV4) *ukov4.VaultBundle {
+		bundle, err := ukoService.ExportVault(ukoService.NewExportVaultOptions("v1"))
+		Expect(err).To(BeNil())
+		return bundle
+	}
+
+	It(`Invoke ExportVault and ImportVault with invalid options (negative test)`, func() {
+		ukoService := newService()
+		_, err := ukoService.ExportVault(nil)
+		Expect(err).ToNot(BeNil())
+		_, err = ukoService.ExportVault(new(ukov4.ExportVaultOptions))
+		Expect(err).ToNot(BeNil())
+		_, err = ukoService.ImportVault(nil)
+		Expect(err).ToNot(BeNil())
+		_, err = ukoService.ImportVault(new(ukov4.ImportVaultOptions))
+		Expect(err).ToNot(BeNil())
+		_, err = ukoService.ImportVault(ukoService.NewImportVaultOptions(&ukov4.VaultBundle{FormatVersion: core.Int64Ptr(2)}))
+		Expect(err).ToNot(BeNil())
+	})
+	It(`Export a vault with credentials replaced by placeholders`, func() {
+		bundle := exportBundle(newService())
+		Expect(*bundle.FormatVersion).To(Equal(ukov4.VaultBundleFormatVersion))
+		Expect(*bundle.Vault.Name).To(Equal("staging"))
+
+		Expect(bundle.Keystores).To(HaveLen(2))
+		aws := bundle.Keystores[0]
+		Expect(*aws.ID).To(Equal("ks1"))
+		Expect(aws.Keystore.Vault).To(BeNil())
+		Expect(*aws.Keystore.AwsRegion).To(Equal("eu-central-1"))
+		Expect(*aws.Keystore.AwsAccessKeyID).To(Equal("${aws-eu.aws_access_key_id}"))
+		Expect(*aws.Keystore.AwsSecretAccessKey).To(Equal(ukov4.CredentialPlaceholder("aws-eu", "aws_secret_access_key")))
+		Expect(*bundle.Keystores[1].Keystore.CcaPort).To(Equal(int64(9000)))
+
+		Expect(bundle.Templates).To(HaveLen(1))
+		Expect(*bundle.Templates[0].Name).To(Equal("aes-eu"))
+		Expect(*bundle.Templates[0].Keystores[0].Group).To(Equal("eu"))
+
+		Expect(bundle.ManagedKeys).To(HaveLen(1))
+		Expect(*bundle.ManagedKeys[0].TemplateName).To(Equal("aes-eu"))
+		Expect(*bundle.ManagedKeys[0].LabelTags[0].Value).To(Equal("pay"))
+
+		Expect(bundle.CredentialPlaceholders()).To(Equal([]string{"aws-eu.aws_access_key_id", "aws-eu.aws_secret_access_key"}))
+
+		buffer, err := json.Marshal(bundle)
+		Expect(err).To(BeNil())
+		Expect(string(buffer)).ToNot(ContainSubstring("AKIA"))
+		decoded := new(ukov4.VaultBundle)
+		Expect(json.Unmarshal(buffer, decoded)).To(Succeed())
+		Expect(decoded.CredentialPlaceholders()).To(HaveLen(2))
+	})
+	It(`Refuse to import without a value for every credential`, func() {
+		ukoService := newService()
+		options := ukoService.NewImportVaultOptions(exportBundle(ukoService)).SetCredential("aws-eu.aws_access_key_id", "AKIA2")
+		_, err := ukoService.ImportVault(options)
+		Expect(err).ToNot(BeNil())
+		missingErr, ok := err.(*ukov4.MissingCredentialsError)
+		Expect(ok).To(BeTrue())
+		Expect(missingErr.Credentials).To(Equal([]string{"aws-eu.aws_secret_access_key"}))
+		Expect(created).To(BeEmpty())
+	})
+	It(`Import a bundle into a new vault`, func() {
+		ukoService := newService()
+		options := ukoService.NewImportVaultOptions(exportBundle(ukoService)).
+			SetName("prod").
+			SetCredentials(map[string]string{
+				"aws-eu.aws_access_key_id":     "AKIA2",
+				"aws-eu.aws_secret_access_key": "secret2",
+			})
+		result, err := ukoService.ImportVault(options)
+		Expect(err).To(BeNil())
+		Expect(*result.VaultID).To(Equal("new-vaults-1"))
+		Expect(result.IDMap).To(Equal(map[string]string{
+			"v1":  "new-vaults-1",
+			"ks1": "new-keystores-1",
+			"ks2": "new-keystores-2",
+			"t1":  "new-templates-1",
+		}))
+
+		Expect(created["vaults"][0]["name"]).To(Equal("prod"))
+		Expect(created["keystores"][0]["vault"]).To(Equal(map[string]interface{}{"id": "new-vaults-1"}))
+		Expect(created["keystores"][0]["aws_access_key_id"]).To(Equal("AKIA2"))
+		Expect(created["keystores"][0]["aws_secret_access_key"]).To(Equal("secret2"))
+		Expect(created["templates"][0]["naming_scheme"]).To(Equal("A-<app>"))
+		Expect(created["managed_keys"]).To(BeEmpty())
+	})
+	It(`Import a bundle into an existing vault with its managed keys`, func() {
+		ukoService := newService()
+		bundle := exportBundle(ukoService)
+		bundle.Keystores = bundle.Keystores[1:]
+		result, err := ukoService.ImportVault(ukoService.NewImportVaultOptions(bundle).SetVaultID("v9").SetCreateManagedKeys(true))
+		Expect(err).To(BeNil())
+		Expect(created["vaults"]).To(BeEmpty())
+		Expect(result.IDMap["v1"]).To(Equal("v9"))
+		Expect(result.IDMap["k1"]).To(Equal("new-managed_keys-1"))
+		Expect(created["managed_keys"][0]["template_name"]).To(Equal("aes-eu"))
+		Expect(created["managed_keys"][0]["vault"]).To(Equal(map[string]interface{}{"id": "v9"}))
+	})
+})